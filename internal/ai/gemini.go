@@ -10,13 +10,22 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/guardian-sh/guardian/internal/checks"
 )
 
 // DefaultModel is the Gemini model to use (can be overridden by GEMINI_MODEL env var)
 const DefaultModel = "gemini-1.5-flash"
 
+// OfflineEnv forces local-only analysis when set to a non-empty value
+const OfflineEnv = "GUARDIAN_OFFLINE"
+
+// callProvider sends a prompt to the AI provider (replaced in tests)
+var callProvider = callGemini
+
 // GeminiResponse is the structured response from the Gemini API
 type GeminiResponse struct {
 	Candidates []struct {
@@ -48,6 +57,30 @@ func getModel() string {
 	return DefaultModel
 }
 
+// IsOffline reports whether GUARDIAN_OFFLINE is set
+func IsOffline() bool {
+	return os.Getenv(OfflineEnv) != ""
+}
+
+// LoadKey returns the API key from GEMINI_API_KEY or ~/.guardian/credentials
+func LoadKey() string {
+	if key := os.Getenv("GEMINI_API_KEY"); key != "" {
+		return key
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(homeDir, ".guardian", "credentials"))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
 // isRetryableError returns true if the error/status code is transient
 func isRetryableError(statusCode int) bool {
 	// Retry on rate limits (429), server errors (5xx), and some client errors
@@ -108,6 +141,11 @@ func ValidateKey(apiKey string) (bool, error) {
 
 // ScanProject uses Gemini to analyze a project
 func ScanProject(apiKey string, dir string) (*ScanResults, error) {
+	// Offline mode never sends code anywhere
+	if IsOffline() {
+		return ScanLocal(dir), nil
+	}
+
 	// First, gather project info locally
 	info := gatherProjectInfo(dir)
 
@@ -115,7 +153,7 @@ func ScanProject(apiKey string, dir string) (*ScanResults, error) {
 	prompt := buildScanPrompt(info)
 
 	// Call Gemini API
-	response, err := callGemini(apiKey, prompt)
+	response, err := callProvider(apiKey, prompt)
 	if err != nil {
 		// Fall back to local analysis - log the reason
 		log.Printf("Gemini API failed (%v), using local analysis", err)
@@ -128,6 +166,11 @@ func ScanProject(apiKey string, dir string) (*ScanResults, error) {
 	return results, nil
 }
 
+// ScanLocal analyzes a project without making any network call
+func ScanLocal(dir string) *ScanResults {
+	return localAnalysis(gatherProjectInfo(dir))
+}
+
 // ProjectInfo holds locally gathered project information
 type ProjectInfo struct {
 	Files        []string
@@ -173,8 +216,11 @@ func gatherProjectInfo(dir string) *ProjectInfo {
 
 		relPath, _ := filepath.Rel(dir, path)
 
-		// Skip hidden and common exclude dirs
+		// Skip hidden and common exclude dirs (but never the root itself, which may be ".")
 		if fileInfo.IsDir() {
+			if path == dir {
+				return nil
+			}
 			name := fileInfo.Name()
 			if strings.HasPrefix(name, ".") || name == "node_modules" ||
 				name == "__pycache__" || name == "venv" || name == ".venv" {
@@ -521,6 +567,40 @@ func findSecrets(sampleCode map[string]string) []SecretLocation {
 	return secrets
 }
 
+// SecretsToIssues turns the secrets a scan found into check issues, so they
+// can be listed and fixed like anything else. Locations are sorted by file
+// and line, and repeats are dropped.
+func SecretsToIssues(r *ScanResults) []checks.Issue {
+	if r == nil {
+		return nil
+	}
+
+	var issues []checks.Issue
+	seen := make(map[SecretLocation]bool)
+	for _, loc := range r.SecretsFound {
+		if seen[loc] {
+			continue
+		}
+		seen[loc] = true
+
+		issues = append(issues, checks.Issue{
+			File:     filepath.ToSlash(loc.File),
+			Line:     max(loc.Line, 1),
+			Rule:     "secret-pattern",
+			Message:  "Possible hardcoded secret found by scan - revoke it and load from environment variables",
+			Severity: "critical",
+		})
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
 func generateRecommendations(results *ScanResults) []string {
 	var recs []string
 
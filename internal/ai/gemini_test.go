@@ -0,0 +1,185 @@
+package ai
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guardian-sh/guardian/internal/checks"
+)
+
+// Helper to replace the provider call for the duration of a test
+func stubProvider(t *testing.T, fn func(apiKey, prompt string) (string, error)) {
+	t.Helper()
+	orig := callProvider
+	callProvider = fn
+	t.Cleanup(func() { callProvider = orig })
+}
+
+// ============================================================================
+// OFFLINE MODE
+// ============================================================================
+
+func TestScanProject_OfflineMakesNoProviderCall(t *testing.T) {
+	called := false
+	stubProvider(t, func(apiKey, prompt string) (string, error) {
+		called = true
+		return "", nil
+	})
+	t.Setenv(OfflineEnv, "1")
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = 1\n"), 0644)
+
+	results, err := ScanProject("AIza-test", dir)
+	if err != nil {
+		t.Fatalf("offline scan failed: %v", err)
+	}
+	if called {
+		t.Error("offline scan should not call the provider")
+	}
+	if results.Language != "Python" {
+		t.Errorf("expected local analysis to detect Python, got %q", results.Language)
+	}
+}
+
+func TestScanLocal_MakesNoProviderCall(t *testing.T) {
+	stubProvider(t, func(apiKey, prompt string) (string, error) {
+		t.Error("ScanLocal should not call the provider")
+		return "", nil
+	})
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "index.ts"), []byte("const x = 1;\n"), 0644)
+
+	results := ScanLocal(dir)
+	if results.Language != "TypeScript" {
+		t.Errorf("expected TypeScript, got %q", results.Language)
+	}
+}
+
+func TestScanProject_OnlineUsesProvider(t *testing.T) {
+	called := false
+	stubProvider(t, func(apiKey, prompt string) (string, error) {
+		called = true
+		return `{"language": "python"}`, nil
+	})
+	t.Setenv(OfflineEnv, "")
+
+	if _, err := ScanProject("AIza-test", t.TempDir()); err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if !called {
+		t.Error("online scan should call the provider")
+	}
+}
+
+// ============================================================================
+// SECRETS AS ISSUES
+// ============================================================================
+
+func TestSecretsToIssues(t *testing.T) {
+	results := &ScanResults{
+		SecretsFound: []SecretLocation{
+			{File: "src/settings.py", Line: 12},
+			{File: "config.js", Line: 3},
+		},
+	}
+
+	issues := SecretsToIssues(results)
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d: %+v", len(issues), issues)
+	}
+
+	// Sorted by file, then line
+	want := []struct {
+		file string
+		line int
+	}{{"config.js", 3}, {"src/settings.py", 12}}
+	for i, w := range want {
+		issue := issues[i]
+		if issue.File != w.file || issue.Line != w.line {
+			t.Errorf("issue %d: expected %s:%d, got %s:%d", i, w.file, w.line, issue.File, issue.Line)
+		}
+		if issue.Rule != "secret-pattern" || issue.Severity != "critical" {
+			t.Errorf("issue %d: expected critical secret-pattern, got %s %s", i, issue.Severity, issue.Rule)
+		}
+	}
+}
+
+func TestSecretsToIssues_DropsRepeats(t *testing.T) {
+	results := &ScanResults{
+		SecretsFound: []SecretLocation{{File: "a.py", Line: 1}, {File: "a.py", Line: 1}},
+	}
+	if issues := SecretsToIssues(results); len(issues) != 1 {
+		t.Errorf("expected 1 issue, got %d", len(issues))
+	}
+	if issues := SecretsToIssues(nil); issues != nil {
+		t.Errorf("expected no issues for nil results, got %+v", issues)
+	}
+}
+
+// ============================================================================
+// FIX SUGGESTIONS
+// ============================================================================
+
+// fakeProvider replies with reply, or fails with err
+type fakeProvider struct {
+	reply  string
+	err    error
+	prompt string
+}
+
+func (f *fakeProvider) Complete(prompt string) (string, error) {
+	f.prompt = prompt
+	return f.reply, f.err
+}
+
+func TestSuggestFix_SendsIssueAndCodeAndStripsFence(t *testing.T) {
+	p := &fakeProvider{reply: "```diff\n--- a/app.py\n+++ b/app.py\n@@ -1 +1 @@\n-eval(x)\n+int(x)\n```\n"}
+	issue := checks.Issue{File: "app.py", Line: 1, Rule: "ban-eval", Message: "eval() is dangerous"}
+
+	patch, err := SuggestFix(p, issue, "1 │ eval(x)\n")
+	if err != nil {
+		t.Fatalf("SuggestFix failed: %v", err)
+	}
+	if !strings.HasPrefix(patch, "--- a/app.py") || strings.Contains(patch, "```") {
+		t.Errorf("expected the diff without its fence, got %q", patch)
+	}
+	for _, want := range []string{"app.py", "ban-eval", "eval() is dangerous", "1 │ eval(x)"} {
+		if !strings.Contains(p.prompt, want) {
+			t.Errorf("prompt should contain %q, got:\n%s", want, p.prompt)
+		}
+	}
+}
+
+func TestSuggestFix_ProviderError(t *testing.T) {
+	p := &fakeProvider{err: errors.New("quota exceeded")}
+	if _, err := SuggestFix(p, checks.Issue{Rule: "ban-eval"}, ""); err == nil {
+		t.Error("expected the provider's error")
+	}
+	if _, err := SuggestFix(&fakeProvider{reply: "  "}, checks.Issue{Rule: "ban-eval"}, ""); err == nil {
+		t.Error("expected an error for an empty reply")
+	}
+}
+
+func TestNewProvider_NeedsKeyAndOnline(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv(OfflineEnv, "")
+	if _, ok := NewProvider(); ok {
+		t.Error("expected no provider without a key")
+	}
+
+	t.Setenv("GEMINI_API_KEY", "AIza-test")
+	if _, ok := NewProvider(); !ok {
+		t.Error("expected a provider with a key")
+	}
+
+	t.Setenv(OfflineEnv, "1")
+	if _, ok := NewProvider(); ok {
+		t.Error("expected no provider offline")
+	}
+}
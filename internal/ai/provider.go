@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/guardian-sh/guardian/internal/checks"
+)
+
+// Provider answers a prompt with an AI model's reply
+type Provider interface {
+	Complete(prompt string) (string, error)
+}
+
+// gemini is the Gemini API called with the user's key
+type gemini struct {
+	key string
+}
+
+func (g gemini) Complete(prompt string) (string, error) {
+	return callProvider(g.key, prompt)
+}
+
+// NewProvider returns the configured provider. ok is false when there's no
+// key or GUARDIAN_OFFLINE is set, so callers fall back to a prompt the user
+// runs themselves.
+func NewProvider() (p Provider, ok bool) {
+	if IsOffline() {
+		return nil, false
+	}
+	key := LoadKey()
+	if key == "" {
+		return nil, false
+	}
+	return gemini{key: key}, true
+}
+
+// SuggestFix asks p for a unified diff fixing issue, given the code around
+// it. The patch is only returned for the user to review; nothing is applied.
+func SuggestFix(p Provider, issue checks.Issue, code string) (string, error) {
+	reply, err := p.Complete(buildFixPrompt(issue, code))
+	if err != nil {
+		return "", err
+	}
+	patch := stripCodeFence(reply)
+	if patch == "" {
+		return "", fmt.Errorf("no fix in the response")
+	}
+	return patch, nil
+}
+
+func buildFixPrompt(issue checks.Issue, code string) string {
+	var sb strings.Builder
+	sb.WriteString("You are fixing one issue a code checker found. Reply with ONLY a unified diff\n")
+	sb.WriteString("(--- a/file, +++ b/file, @@ hunks) that fixes it with the smallest change.\n")
+	sb.WriteString("Don't delete the code to silence the checker - fix it properly.\n\n")
+	fmt.Fprintf(&sb, "File: %s\nLine: %d\nRule: %s\nMessage: %s\n\n", issue.File, issue.Line, issue.Rule, issue.Message)
+	sb.WriteString("Code (line numbers are for reference only):\n")
+	sb.WriteString(code)
+	return sb.String()
+}
+
+// stripCodeFence drops the ``` lines models wrap a diff in
+func stripCodeFence(reply string) string {
+	reply = strings.TrimSpace(reply)
+	if !strings.HasPrefix(reply, "```") {
+		return reply
+	}
+	lines := strings.Split(reply, "\n")[1:]
+	if n := len(lines); n > 0 && strings.TrimSpace(lines[n-1]) == "```" {
+		lines = lines[:n-1]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
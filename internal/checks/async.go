@@ -0,0 +1,184 @@
+package checks
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	pyDefRe = regexp.MustCompile(`^(async\s+)?def\s+\w+`)
+
+	// JS function headers. Arrow functions don't start a frame of their
+	// own unless marked async: a sync callback inside an async function
+	// still runs on the event loop.
+	jsAsyncFnRe = regexp.MustCompile(`\basync\s+(?:function\b|\*?\s*\w+\s*\(|\([^)]*\)\s*=>|\w+\s*=>)`)
+	jsSyncFnRe  = regexp.MustCompile(`\bfunction\b`)
+)
+
+// asyncFrame is an enclosing function: by indentation in Python, by brace
+// depth in JS
+type asyncFrame struct {
+	level int
+	async bool
+}
+
+// asyncTracker follows which function each line is in, so a blocking call
+// can be judged by whether it runs on an event loop
+type asyncTracker struct {
+	lang   string
+	frames []asyncFrame
+	depth  int // JS brace depth
+}
+
+func newAsyncTracker(lang string) *asyncTracker {
+	if lang != langPython && lang != langJS {
+		return nil
+	}
+	return &asyncTracker{lang: lang}
+}
+
+// inAsync reports whether the innermost enclosing function is async
+func (t *asyncTracker) inAsync() bool {
+	return len(t.frames) > 0 && t.frames[len(t.frames)-1].async
+}
+
+// feed takes the next non-blank, non-comment line and reports whether it
+// runs inside an async function
+func (t *asyncTracker) feed(line string) bool {
+	if t.lang == langPython {
+		return t.feedPython(line)
+	}
+	return t.feedJS(line)
+}
+
+func (t *asyncTracker) feedPython(line string) bool {
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	for len(t.frames) > 0 && t.frames[len(t.frames)-1].level >= indent {
+		t.frames = t.frames[:len(t.frames)-1]
+	}
+
+	// The def line itself belongs to the enclosing function
+	inAsync := t.inAsync()
+	if m := pyDefRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+		t.frames = append(t.frames, asyncFrame{level: indent, async: m[1] != ""})
+	}
+	return inAsync
+}
+
+func (t *asyncTracker) feedJS(line string) bool {
+	inAsync := t.inAsync()
+
+	// A header on this line opens its frame at the next "{"
+	pending, pendingAsync := -1, false
+	if loc := jsAsyncFnRe.FindStringIndex(line); loc != nil {
+		pending, pendingAsync = loc[0], true
+	} else if loc := jsSyncFnRe.FindStringIndex(line); loc != nil {
+		pending = loc[0]
+	}
+
+	for i, r := range line {
+		switch r {
+		case '{':
+			t.depth++
+			if pending >= 0 && i >= pending {
+				t.frames = append(t.frames, asyncFrame{level: t.depth, async: pendingAsync})
+				inAsync = inAsync || pendingAsync
+				pending = -1
+			}
+		case '}':
+			for len(t.frames) > 0 && t.frames[len(t.frames)-1].level >= t.depth {
+				t.frames = t.frames[:len(t.frames)-1]
+			}
+			t.depth--
+		}
+	}
+
+	// async x => fetchSync(x) - no braces, just this line
+	if pending >= 0 && pendingAsync {
+		inAsync = true
+	}
+	return inAsync
+}
+
+// blockingCallRe matches a call to any of the configured blocking calls as
+// a whole name, so "open" doesn't match aiofiles.open or reopen
+func blockingCallRe(calls []string) *regexp.Regexp {
+	if len(calls) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(calls))
+	for i, call := range calls {
+		quoted[i] = regexp.QuoteMeta(call)
+	}
+	return regexp.MustCompile(`(?:^|[^\w.])(` + strings.Join(quoted, "|") + `)\s*\(`)
+}
+
+var (
+	// Function definitions, for telling async names from sync ones
+	pyAsyncDefNameRe = regexp.MustCompile(`^\s*async\s+def\s+(\w+)`)
+	pySyncDefNameRe  = regexp.MustCompile(`^\s*def\s+(\w+)`)
+	jsAsyncDeclRe    = regexp.MustCompile(`\basync\s+function\s*\*?\s*(\w+)|\b(?:const|let|var)\s+(\w+)\s*=\s*async\b|^\s*(?:static\s+)?async\s+\*?\s*(\w+)\s*\(`)
+	jsSyncDeclRe     = regexp.MustCompile(`\bfunction\s*\*?\s*(\w+)`)
+
+	// A statement that starts by calling a bare name, self.name or this.name
+	callStmtRe = regexp.MustCompile(`^(?:(?:self|this)\.)?(\w+)\s*\(`)
+)
+
+// asyncFunctionNames collects the functions a file defines as async. A name
+// the file also defines as sync, e.g. methods of two classes, is left out
+// because a call to it can't be told apart.
+func asyncFunctionNames(lines []string, lang string) map[string]bool {
+	async := make(map[string]bool)
+	sync := make(map[string]bool)
+	for _, line := range lines {
+		switch lang {
+		case langPython:
+			if m := pyAsyncDefNameRe.FindStringSubmatch(line); m != nil {
+				async[m[1]] = true
+			} else if m := pySyncDefNameRe.FindStringSubmatch(line); m != nil {
+				sync[m[1]] = true
+			}
+		case langJS:
+			if m := jsAsyncDeclRe.FindStringSubmatch(line); m != nil {
+				async[m[1]+m[2]+m[3]] = true
+			} else if m := jsSyncDeclRe.FindStringSubmatch(line); m != nil {
+				sync[m[1]] = true
+			}
+		}
+	}
+	for name := range sync {
+		delete(async, name)
+	}
+	if len(async) == 0 {
+		return nil
+	}
+	return async
+}
+
+// unawaitedCall returns the async function a line calls and then drops:
+// the whole statement is the call, with no await, assignment or chained
+// .then() to pick up the result
+func unawaitedCall(trimmed string, async map[string]bool) string {
+	m := callStmtRe.FindStringSubmatch(trimmed)
+	if m == nil || !async[m[1]] {
+		return ""
+	}
+
+	depth := 0
+	for i := len(m[0]) - 1; i < len(trimmed); i++ {
+		switch trimmed[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(trimmed[i+1:]), ";"))
+				if rest == "" || strings.HasPrefix(rest, "#") || strings.HasPrefix(rest, "//") {
+					return m[1]
+				}
+				return ""
+			}
+		}
+	}
+	return "" // Call continues on the next line
+}
@@ -0,0 +1,155 @@
+package checks
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BaselineFile holds the issues a team has accepted, relative to the
+// project root. Unlike LastRunFile it only changes when someone updates it
+// on purpose, so it's meant to be committed.
+const BaselineFile = ".guardian/baseline.json"
+
+// How baseline issues are matched against a new run
+const (
+	BaselineStrict = "strict" // File, line and rule: any shift makes an issue new
+	BaselineFuzzy  = "fuzzy"  // File, rule and the trimmed line's text: survives reformatting
+)
+
+// BaselineFormats are the values --baseline-format accepts
+var BaselineFormats = []string{BaselineStrict, BaselineFuzzy}
+
+// Baseline is the content of BaselineFile. Format is empty for baselines
+// written before formats existed, which were a bare list matched by file,
+// rule and message.
+type Baseline struct {
+	Format string          `json:"format"`
+	Issues []BaselineIssue `json:"issues"`
+}
+
+// BaselineIssue is an accepted issue. Hash is set in fuzzy baselines.
+type BaselineIssue struct {
+	Issue
+	Hash string `json:"hash,omitempty"`
+}
+
+// SaveBaseline replaces the baseline with issues, found under dir. Fuzzy
+// baselines record a hash of each issue's line so it can be found again
+// after the line moves.
+func SaveBaseline(dir string, issues []Issue, format string) error {
+	b := Baseline{Format: format, Issues: []BaselineIssue{}}
+	hashes := lineHashes(dir, issues, format)
+	for i, issue := range issues {
+		b.Issues = append(b.Issues, BaselineIssue{Issue: issue, Hash: hashes[i]})
+	}
+
+	path := filepath.Join(dir, BaselineFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// LoadBaseline reads the accepted issues. found is false when no baseline
+// has been written yet.
+func LoadBaseline(dir string) (b *Baseline, found bool, err error) {
+	data, err := os.ReadFile(filepath.Join(dir, BaselineFile))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	b = &Baseline{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &b.Issues)
+	} else {
+		err = json.Unmarshal(data, b)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+// FilterBaseline drops issues, found under dir, that are already in the
+// baseline. Repeats are counted the same way as DiffIssues, so a second
+// copy of an accepted issue is still reported.
+func FilterBaseline(dir string, issues []Issue, b *Baseline) []Issue {
+	if b.Format == "" {
+		accepted := make([]Issue, len(b.Issues))
+		for i, bi := range b.Issues {
+			accepted[i] = bi.Issue
+		}
+		added, _ := DiffIssues(accepted, issues)
+		return added
+	}
+
+	remaining := make(map[baselineKey]int)
+	for _, bi := range b.Issues {
+		remaining[newBaselineKey(bi.Issue, bi.Hash, b.Format)]++
+	}
+
+	var kept []Issue
+	hashes := lineHashes(dir, issues, b.Format)
+	for i, issue := range issues {
+		key := newBaselineKey(issue, hashes[i], b.Format)
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
+// baselineKey identifies an accepted issue; which fields are set depends
+// on the format
+type baselineKey struct {
+	File, Rule, Hash string
+	Line             int
+}
+
+func newBaselineKey(issue Issue, hash, format string) baselineKey {
+	if format == BaselineStrict {
+		return baselineKey{File: issue.File, Rule: issue.Rule, Line: issue.Line}
+	}
+	return baselineKey{File: issue.File, Rule: issue.Rule, Hash: hash}
+}
+
+// lineHashes hashes the trimmed source line of each issue for fuzzy
+// matching. Lines that can't be read hash to "", so they match on file and
+// rule alone.
+func lineHashes(dir string, issues []Issue, format string) []string {
+	hashes := make([]string, len(issues))
+	if format != BaselineFuzzy {
+		return hashes
+	}
+
+	files := make(map[string][]string)
+	for i, issue := range issues {
+		lines, ok := files[issue.File]
+		if !ok {
+			if content, err := os.ReadFile(filepath.Join(dir, issue.File)); err == nil {
+				lines = strings.Split(string(content), "\n")
+			}
+			files[issue.File] = lines
+		}
+		if issue.Line < 1 || issue.Line > len(lines) {
+			continue
+		}
+		sum := sha256.Sum256([]byte(strings.TrimSpace(lines[issue.Line-1])))
+		hashes[i] = hex.EncodeToString(sum[:8])
+	}
+	return hashes
+}
@@ -0,0 +1,56 @@
+package checks
+
+import (
+	"context"
+	"time"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// BenchResult is the throughput of the builtin checks on one directory
+type BenchResult struct {
+	Files   int
+	Lines   int
+	Issues  int
+	Rounds  int
+	Elapsed time.Duration // Average per round
+}
+
+// FilesPerSec is how many files one round checked per second
+func (r BenchResult) FilesPerSec() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Files) / r.Elapsed.Seconds()
+}
+
+// NsPerLine is how long one round spent on each line
+func (r BenchResult) NsPerLine() float64 {
+	if r.Lines == 0 {
+		return 0
+	}
+	return float64(r.Elapsed.Nanoseconds()) / float64(r.Lines)
+}
+
+// Bench times the builtin checks over dir, averaged over rounds runs. The
+// scripts in .guardian/ are never used, so results are comparable between
+// projects and releases.
+func Bench(dir string, cfg *config.Config, rounds int) BenchResult {
+	rounds = max(rounds, 1)
+	info := DryRunWithConfig(dir, cfg)
+
+	var issues []Issue
+	start := time.Now()
+	for i := 0; i < rounds; i++ {
+		issues, _ = runBuiltinChecks(context.Background(), dir, cfg, nil)
+	}
+	TakeFileErrors() // Reported by a real check, not here
+
+	return BenchResult{
+		Files:   info.FileCount,
+		Lines:   info.TotalLines,
+		Issues:  len(issues),
+		Rounds:  rounds,
+		Elapsed: time.Since(start) / time.Duration(rounds),
+	}
+}
@@ -0,0 +1,48 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// os.chmod(, Path.chmod(, fs.chmodSync(, fs.promises.chmod(, os.Chmod(, f.Chmod(
+	chmodCallRe = regexp.MustCompile(`(?:^|[^\w])[fl]?(?:chmod(?:Sync)?|Chmod)\s*\(`)
+
+	// An octal mode: 0o777 (Python 3, JS, Go), 0777 (Go, legacy JS), or a
+	// quoted "777" string, which Node also accepts
+	octalModeRe = regexp.MustCompile(`\b0[oO]?([0-7]{3,4})\b|['"]([0-7]{3,4})['"]`)
+)
+
+// permissiveChmod returns the mode set by a chmod call when it grants a
+// permission bit that max doesn't
+func permissiveChmod(call string, max int) (int, bool) {
+	for _, m := range octalModeRe.FindAllStringSubmatch(call, -1) {
+		digits := m[1]
+		if digits == "" {
+			digits = m[2]
+		}
+		mode, err := strconv.ParseInt(digits, 8, 32)
+		if err != nil {
+			continue
+		}
+		if int(mode)&0o777&^max != 0 {
+			return int(mode), true
+		}
+	}
+	return 0, false
+}
+
+// describeMode says what's risky about a mode, for the issue message
+func describeMode(mode int) string {
+	switch {
+	case mode&0o002 != 0:
+		return fmt.Sprintf("%O makes it world-writable", mode)
+	case mode&0o020 != 0:
+		return fmt.Sprintf("%O makes it group-writable", mode)
+	case mode&0o001 != 0:
+		return fmt.Sprintf("%O makes it executable by everyone", mode)
+	}
+	return fmt.Sprintf("%O is more permissive than max_chmod_mode", mode)
+}
@@ -0,0 +1,123 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// customRuleRes caches compiled custom rule patterns across files
+var customRuleRes sync.Map // pattern -> *regexp.Regexp
+
+// customRuleRe compiles a custom rule's pattern once. Patterns are validated
+// when the config loads, so nil only comes from hand-built configs.
+func customRuleRe(pattern string) *regexp.Regexp {
+	if re, ok := customRuleRes.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+	customRuleRes.Store(pattern, re)
+	return re
+}
+
+// customRuleApplies reports whether a rule's languages include lang
+func customRuleApplies(rule config.CustomRule, lang string) bool {
+	if len(rule.Languages) == 0 {
+		return true
+	}
+	for _, l := range rule.Languages {
+		if languageAliases[strings.ToLower(l)] == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCustomRules runs the config's [[custom_rules]] over one file's lines.
+// A rule with a scope only matches that part of each line: its code, its
+// comments or its strings.
+func checkCustomRules(relPath, lang string, lines []string, cfg *config.Config) []Issue {
+	var issues []Issue
+	var scopes []lineScopes // Split on first use by a scoped rule
+
+	for _, rule := range cfg.CustomRules {
+		re := customRuleRe(rule.Pattern)
+		if re == nil || !customRuleApplies(rule, lang) {
+			continue
+		}
+
+		severity := rule.Severity
+		if severity == "" {
+			severity = "warning"
+		}
+		message := rule.Message
+		if message == "" {
+			message = "Matches custom rule " + rule.ID
+		}
+
+		if rule.Scope != "" && scopes == nil {
+			scopes = splitScopes(lines, lang)
+		}
+
+		for i, line := range lines {
+			text := line
+			if rule.Scope != "" {
+				text = scopes[i].scopeText(rule.Scope, line)
+			}
+			if re.MatchString(text) {
+				issues = append(issues, Issue{
+					File:     relPath,
+					Line:     i + 1,
+					Rule:     rule.ID,
+					Message:  message,
+					Severity: severity,
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// runCustomRuleChecks walks dir for custom rules only, for runs where the
+// scripts did the rest of the checking
+func runCustomRuleChecks(dir string, cfg *config.Config) []Issue {
+	if len(cfg.CustomRules) == 0 {
+		return nil
+	}
+
+	var issues []Issue
+	walkSource(dir, cfg, nil, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if excludedDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		lang := languageFor(path, cfg)
+		if lang == "" || lang == langDeps {
+			return nil
+		}
+		relPath, _ := filepath.Rel(dir, path)
+		relPath = filepath.ToSlash(relPath)
+		lines := readSourceLines(path, relPath, cfg)
+		if lines == nil {
+			return nil
+		}
+		issues = append(issues, checkCustomRules(relPath, lang, lines, cfg)...)
+		return nil
+	})
+
+	return issues
+}
@@ -0,0 +1,129 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+var (
+	// requirements.txt, requirements-dev.txt, requirements_test.txt
+	requirementsNameRe = regexp.MustCompile(`^requirements(?:[-_.][\w.-]*)?\.txt$`)
+
+	// The package name at the start of a requirement, before extras and versions
+	requirementNameRe = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+	// npm specs that install from somewhere other than the registry
+	urlDependencyRe = regexp.MustCompile(`^(?:git\+|git:|github:|gitlab:|bitbucket:|gist:|file:|link:|https?:)` +
+		`|^[\w.-]+/[\w.-]+(?:#.*)?$`) // GitHub shorthand: user/repo#ref
+)
+
+// isDependencyFile matches the manifests checkDependencyFile reads:
+// requirements*.txt and package.json
+func isDependencyFile(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	return name == "package.json" || requirementsNameRe.MatchString(name)
+}
+
+// checkDependencyFile looks for dependencies a fresh install can't
+// reproduce: unpinned Python requirements and npm packages installed from
+// git or local paths
+func checkDependencyFile(relPath string, lines []string, cfg *config.Config) []Issue {
+	if strings.EqualFold(filepath.Base(relPath), "package.json") {
+		if !cfg.Security.BanURLDependencies {
+			return nil
+		}
+		return checkPackageJSON(relPath, lines)
+	}
+	if !cfg.Security.BanUnpinnedDependencies {
+		return nil
+	}
+	return checkRequirements(relPath, lines)
+}
+
+// checkRequirements flags requirements not pinned with ==. Options (-r,
+// -e, --hash) and direct URL references are left alone.
+func checkRequirements(relPath string, lines []string) []Issue {
+	var issues []Issue
+	for _, line := range joinContinuations(lines) {
+		req := line.text
+		if i := strings.Index(req, " #"); i >= 0 {
+			req = req[:i]
+		}
+		if i := strings.Index(req, ";"); i >= 0 {
+			req = req[:i] // Environment marker
+		}
+		req = strings.TrimSpace(req)
+		if req == "" || strings.HasPrefix(req, "#") || strings.HasPrefix(req, "-") || strings.Contains(req, "://") {
+			continue
+		}
+
+		name := requirementNameRe.FindString(req)
+		if name == "" || strings.Contains(req, "==") {
+			continue
+		}
+		issues = append(issues, Issue{
+			File:     relPath,
+			Line:     line.num,
+			Rule:     "unpinned-dependency",
+			Message:  fmt.Sprintf("%s isn't pinned - use %s==<version> so every install gets the same code", name, name),
+			Severity: "info",
+		})
+	}
+	return issues
+}
+
+// checkPackageJSON flags dependencies installed from git, a URL or a local
+// path. A package.json that doesn't parse is skipped.
+func checkPackageJSON(relPath string, lines []string) []Issue {
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(strings.Join(lines, "\n")), &manifest); err != nil {
+		return nil
+	}
+
+	var issues []Issue
+	for _, section := range []string{"dependencies", "devDependencies", "optionalDependencies", "peerDependencies"} {
+		var deps map[string]string
+		if json.Unmarshal(manifest[section], &deps) != nil {
+			continue
+		}
+		names := make([]string, 0, len(deps))
+		for name := range deps {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			spec := deps[name]
+			if !urlDependencyRe.MatchString(spec) {
+				continue
+			}
+			issues = append(issues, Issue{
+				File:     relPath,
+				Line:     dependencyLine(lines, name, spec),
+				Rule:     "url-dependency",
+				Message:  fmt.Sprintf("%s is installed from %s - depend on a published version so installs are reproducible", name, spec),
+				Severity: "warning",
+			})
+		}
+	}
+	return issues
+}
+
+// dependencyLine finds the line declaring name: spec, or 1 if it's not on
+// a single line
+func dependencyLine(lines []string, name, spec string) int {
+	key, _ := json.Marshal(name)
+	value, _ := json.Marshal(spec)
+	for i, line := range lines {
+		if strings.Contains(line, string(key)) && strings.Contains(line, string(value)) {
+			return i + 1
+		}
+	}
+	return 1
+}
@@ -0,0 +1,35 @@
+package checks
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// utf8BOM is the byte order mark some Windows editors put at the start of
+// UTF-8 files
+const utf8BOM = "\ufeff"
+
+// decodeSource returns a file's text as UTF-8 with any BOM dropped, so line
+// 1 reads like every other line. With project.encoding = "latin-1", text
+// that isn't valid UTF-8 is transcoded; valid UTF-8 is left alone, so a
+// project that's partly converted still reads correctly.
+func decodeSource(content string, cfg *config.Config) string {
+	content = strings.TrimPrefix(content, utf8BOM)
+	if config.IsLatin1(cfg.Project.Encoding) && !utf8.ValidString(content) {
+		return latin1ToUTF8(content)
+	}
+	return content
+}
+
+// latin1ToUTF8 transcodes ISO-8859-1, where every byte is the code point of
+// the same value
+func latin1ToUTF8(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/4)
+	for i := 0; i < len(s); i++ {
+		b.WriteRune(rune(s[i]))
+	}
+	return b.String()
+}
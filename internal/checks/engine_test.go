@@ -0,0 +1,194 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/guardian-sh/guardian/internal/config"
+	"github.com/guardian-sh/guardian/internal/scaffolding"
+)
+
+// sharedRules are implemented by both the scaffolded Python scripts and the
+// builtin engine. The engines are only compared on these: func-size and
+// mutable-default are script-only (they need Python's ast module), and
+// rules like ban-print and no-timeout exist only in the builtin engine.
+var sharedRules = map[string]bool{
+	"file-size":        true,
+	"dangerous-cmd":    true,
+	"mock-data":        true,
+	"ban-eval":         true,
+	"subprocess-shell": true,
+	"sql-injection":    true,
+	"secret-pattern":   true,
+	"ban-star":         true,
+	"todo-marker":      true,
+	"ban-except":       true,
+}
+
+// engineFixtures are Python sources that should produce the same rules
+// from either engine
+var engineFixtures = map[string]string{
+	"eval.py":           "result = eval(user_input)\n",
+	"exec_string.py":    "msg = \"never call eval(x) on input\"\n",
+	"subprocess.py":     "import subprocess\nsubprocess.run(cmd, shell=True)\n",
+	"sql.py":            "query = f\"SELECT * FROM users WHERE id = {user_id}\"\n",
+	"secret.py":         "api_key = \"sk-live-4f9a8b7c6d5e4f3a\"\n",
+	"secret_comment.py": "# password = \"hunter2\" is just an example\n",
+	"star.py":           "from os import *\n",
+	"todo.py":           "x = 1  # TODO: remove\n",
+	"except.py":         "try:\n    run()\nexcept:\n    pass\n",
+	"dangerous.py":      "os.system(\"rm -rf /tmp/build\")\n",
+	"mock.py":           "EMAIL = \"test@example.com\"\n",
+	"clean.py":          "def add(a, b):\n    return a + b\n",
+	"big.py":            strings.Repeat("x = 1\n", 501),
+}
+
+// Helper to collect the shared rules firing per file, as "file: rule,rule"
+func rulesByFile(issues []Issue) map[string]string {
+	sets := make(map[string]map[string]bool)
+	for _, issue := range issues {
+		if !sharedRules[issue.Rule] {
+			continue
+		}
+		if sets[issue.File] == nil {
+			sets[issue.File] = make(map[string]bool)
+		}
+		sets[issue.File][issue.Rule] = true
+	}
+
+	out := make(map[string]string)
+	for file, set := range sets {
+		var rules []string
+		for rule := range set {
+			rules = append(rules, rule)
+		}
+		sort.Strings(rules)
+		out[file] = strings.Join(rules, ",")
+	}
+	return out
+}
+
+// ============================================================================
+// SCRIPT VS BUILTIN ENGINE
+// ============================================================================
+
+func TestEngines_AgreeOnFixtures(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs python3 scripts")
+	}
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+
+	dir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(oldDir)
+
+	if err := scaffolding.Install(scaffolding.InstallConfig{Language: "python"}); err != nil {
+		t.Fatalf("failed to install python scaffolding: %v", err)
+	}
+	for name, content := range engineFixtures {
+		os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+	}
+
+	cfg := config.DefaultConfig()
+	scriptIssues, err := RunWithEngine(".", cfg, EngineScript)
+	if err != nil {
+		t.Fatalf("script engine failed: %v", err)
+	}
+	builtinIssues, _ := RunWithEngine(".", cfg, EngineBuiltin)
+
+	script := rulesByFile(scriptIssues)
+	builtin := rulesByFile(builtinIssues)
+
+	// Guard against a vacuous pass if the scripts silently print nothing
+	if script["eval.py"] != "ban-eval" {
+		t.Fatalf("script engine should flag eval.py, got %q (all: %v)", script["eval.py"], script)
+	}
+
+	for name := range engineFixtures {
+		if script[name] != builtin[name] {
+			t.Errorf("%s: script engine fired [%s], builtin fired [%s]", name, script[name], builtin[name])
+		}
+	}
+}
+
+func TestRunWithEngine_BothMergesScriptAndBuiltin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs python3 scripts")
+	}
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+
+	// A custom guardian.py that only knows file-size, worded its own way
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".guardian"), 0755)
+	script := `import pathlib, sys
+failed = False
+for path in pathlib.Path(".").rglob("*.py"):
+    if ".guardian" in path.parts:
+        continue
+    n = len(path.read_text().splitlines())
+    if n > 500:
+        print(f"{path}:1 [file-size] too long: {n} lines")
+        failed = True
+sys.exit(1 if failed else 0)
+`
+	os.WriteFile(filepath.Join(dir, ".guardian", "guardian.py"), []byte(script), 0644)
+	os.WriteFile(filepath.Join(dir, "app.py"), []byte("result = eval(user_input)\n"+strings.Repeat("x = 1\n", 500)), 0644)
+
+	cfg := config.DefaultConfig()
+	scriptOnly, err := RunWithEngine(dir, cfg, EngineScript)
+	if err != nil {
+		t.Fatalf("script engine failed: %v", err)
+	}
+	if got := rulesByFile(scriptOnly)["app.py"]; got != "file-size" {
+		t.Fatalf("the script should only find file-size, got %q", got)
+	}
+
+	issues, err := RunWithEngine(dir, cfg, EngineBoth)
+	if err != nil {
+		t.Fatalf("both engines failed: %v", err)
+	}
+	if got := rulesByFile(issues)["app.py"]; got != "ban-eval,file-size" {
+		t.Errorf("expected both the script's and the builtin rules, got %q", got)
+	}
+	if n := len(filterRule(issues, "file-size")); n != 1 {
+		t.Errorf("file-size found by both engines should be reported once, got %d: %+v", n, issues)
+	}
+}
+
+func TestMergeEngineIssues(t *testing.T) {
+	builtin := []Issue{
+		{File: "a.py", Line: 1, Rule: "file-size", Message: "File has 600 lines (max 500)"},
+		{File: "a.py", Line: 3, Rule: "ban-eval", Message: "Avoid eval()"},
+	}
+	script := []Issue{
+		{File: "a.py", Line: 1, Rule: "file-size", Message: "600 lines"},        // Same issue, other wording
+		{File: "a.py", Line: 9, Rule: "func-size", Message: "handle() is long"}, // Script-only rule
+	}
+
+	merged := mergeEngineIssues(builtin, script)
+	var got []string
+	for _, issue := range merged {
+		got = append(got, fmt.Sprintf("%d %s %s", issue.Line, issue.Rule, issue.Message))
+	}
+	want := []string{"1 file-size File has 600 lines (max 500)", "3 ban-eval Avoid eval()", "9 func-size handle() is long"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRunWithEngine_ScriptMissing(t *testing.T) {
+	if _, err := RunWithEngine(t.TempDir(), nil, EngineScript); err == nil {
+		t.Error("expected an error when guardian.py isn't installed")
+	}
+}
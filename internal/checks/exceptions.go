@@ -0,0 +1,183 @@
+package checks
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Exception handling checks need the whole except/catch body, not one line,
+// so they're collected here as checkFileWithConfig feeds lines in.
+var (
+	pyExceptRe = regexp.MustCompile(`^except\b[^:]*:(.*)$`)
+	jsCatchRe  = regexp.MustCompile(`\bcatch\s*(?:\([^)]*\))?\s*\{`)
+
+	// Logging calls below the level anyone reads in production
+	debugLogRe = regexp.MustCompile(`^(?:\w+\.)*(?:console|logging|_?log(?:ger)?|LOG(?:GER)?)\.(?:debug|trace)\s*\(`)
+)
+
+// handlerBlock is an except/catch body being collected
+type handlerBlock struct {
+	line   int             // Line of the except/catch
+	indent int             // Python: indentation of the except line
+	depth  int             // JS: brace depth inside the catch
+	body   strings.Builder // Body source, newline separated
+}
+
+// exceptionTracker follows try/except (Python) and try/catch (JS) handlers
+// through a file and classifies each body once it ends
+type exceptionTracker struct {
+	lang    string
+	relPath string
+	block   *handlerBlock
+	issues  []Issue
+}
+
+func newExceptionTracker(lang, relPath string) *exceptionTracker {
+	if lang != langPython && lang != langJS {
+		return nil
+	}
+	return &exceptionTracker{lang: lang, relPath: relPath}
+}
+
+// feed takes the next non-blank, non-comment line
+func (t *exceptionTracker) feed(lineNum int, line string) {
+	if t.lang == langPython {
+		t.feedPython(lineNum, line)
+	} else {
+		t.feedJS(lineNum, line)
+	}
+}
+
+func (t *exceptionTracker) feedPython(lineNum int, line string) {
+	trimmed := strings.TrimSpace(line)
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+	// A handler ends at the first line indented no deeper than its except,
+	// or at the next handler (nested ones are judged on their own)
+	m := pyExceptRe.FindStringSubmatch(trimmed)
+	if t.block != nil && (indent <= t.block.indent || m != nil) {
+		t.close()
+	}
+
+	if m != nil {
+		t.block = &handlerBlock{line: lineNum, indent: indent}
+		t.block.body.WriteString(strings.TrimSpace(m[1]) + "\n")
+		return
+	}
+	if t.block != nil {
+		t.block.body.WriteString(trimmed + "\n")
+	}
+}
+
+func (t *exceptionTracker) feedJS(lineNum int, line string) {
+	rest := line
+	if t.block == nil {
+		loc := jsCatchRe.FindStringIndex(line)
+		if loc == nil {
+			return
+		}
+		t.block = &handlerBlock{line: lineNum, depth: 1}
+		rest = line[loc[1]:]
+	}
+
+	for i, r := range rest {
+		switch r {
+		case '{':
+			t.block.depth++
+		case '}':
+			t.block.depth--
+			if t.block.depth == 0 {
+				t.block.body.WriteString(rest[:i])
+				t.close()
+				return
+			}
+		}
+	}
+	t.block.body.WriteString(rest + "\n")
+}
+
+// close classifies the current handler body
+func (t *exceptionTracker) close() {
+	block := t.block
+	t.block = nil
+
+	if isLogAndIgnore(splitStatements(block.body.String())) {
+		t.issues = append(t.issues, Issue{
+			File:     t.relPath,
+			Line:     block.line,
+			Rule:     "log-and-ignore",
+			Message:  "Exception is only logged at debug level, then ignored - handle it, log it visibly, or re-raise",
+			Severity: "warning",
+		})
+	}
+}
+
+// finish closes a handler still open at the end of the file
+func (t *exceptionTracker) finish() []Issue {
+	if t.block != nil {
+		t.close()
+	}
+	return t.issues
+}
+
+// isLogAndIgnore reports whether a handler body does nothing but debug
+// logging, optionally followed by pass/continue
+func isLogAndIgnore(stmts []string) bool {
+	logged := false
+	for _, stmt := range stmts {
+		bare, _, _ := strings.Cut(stmt, "#")
+		bare, _, _ = strings.Cut(bare, "//")
+		bare = strings.TrimSpace(bare)
+
+		switch {
+		case bare == "":
+			// Just a trailing comment
+		case debugLogRe.MatchString(stmt):
+			logged = true
+		case bare == "pass" || bare == "continue" || bare == "...":
+		default:
+			return false
+		}
+	}
+	return logged
+}
+
+// splitStatements splits source on ';' and newlines outside brackets and
+// quotes, so a log call spread over several lines stays one statement
+func splitStatements(src string) []string {
+	var stmts []string
+	var cur strings.Builder
+	depth := 0
+	var quote rune
+
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); s != "" {
+			stmts = append(stmts, s)
+		}
+		cur.Reset()
+	}
+
+	for _, r := range src {
+		if r == '\n' && quote != '`' {
+			quote = 0 // An unclosed quote (e.g. an apostrophe in a comment) ends with the line
+		}
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case (r == ';' || r == '\n') && depth <= 0:
+			flush()
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	flush()
+	return stmts
+}
@@ -0,0 +1,304 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// FixableRules are the only rules ApplyFixes touches. Each fix either drops
+// debug output or is a behaviour-preserving rewrite, so they're safe to
+// apply without review.
+var FixableRules = map[string]bool{
+	"ban-print":       true,
+	"ban-console":     true,
+	"mutable-default": true,
+}
+
+// Fix is one change ApplyFixes made
+type Fix struct {
+	Line        int // Line in the original source
+	Rule        string
+	Description string
+}
+
+var (
+	// A line that starts with a debug call; standaloneCall checks the call
+	// is all there is
+	printStmtRe   = regexp.MustCompile(`^print\s*\(`)
+	consoleStmtRe = regexp.MustCompile(`^console\.log\s*\(`)
+
+	// A single-line def, and the mutable literals it may default to
+	pyDefLineRe      = regexp.MustCompile(`^(\s*)(?:async\s+)?def\s+\w+\s*\((.*)\)\s*(?:->.*)?:\s*$`)
+	mutableDefaultRe = regexp.MustCompile(`^(\s*\w+)\s*=\s*(\[\s*\]|\{\s*\}|list\(\s*\)|dict\(\s*\)|set\(\s*\))\s*$`)
+)
+
+// FixFile applies the safe fixes to one file in place and returns what
+// changed. The file is only written when something was fixed. cfg is the
+// file's own config, with any .guardian.toml overrides applied.
+func FixFile(path, relPath string, cfg *config.Config) ([]Fix, error) {
+	_, fixed, fixes, err := fixContent(path, relPath, cfg)
+	if err != nil || len(fixes) == 0 {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return fixes, os.WriteFile(path, []byte(fixed), info.Mode().Perm())
+}
+
+// PreviewFix works out the fixes FixFile would make without writing
+// anything, and returns them with a unified diff of the change ("" when
+// there's nothing to fix)
+func PreviewFix(path, relPath string, cfg *config.Config) ([]Fix, string, error) {
+	original, fixed, fixes, err := fixContent(path, relPath, cfg)
+	if err != nil || len(fixes) == 0 {
+		return nil, "", err
+	}
+	return fixes, UnifiedDiff(filepath.ToSlash(relPath), original, fixed), nil
+}
+
+// fixContent checks one file and returns its content before and after the
+// safe fixes
+func fixContent(path, relPath string, cfg *config.Config) (original, fixed string, fixes []Fix, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	lang := languageFor(path, cfg)
+	issues := checkFileWithConfig(path, relPath, cfg)
+	fixed, fixes = ApplyFixes(string(content), lang, issues, cfg)
+	return string(content), fixed, fixes, nil
+}
+
+// ApplyFixes rewrites src to fix the issues it can. Only Python and JS are
+// fixed; in Go, dropping a print can leave an unused import behind.
+func ApplyFixes(src, lang string, issues []Issue, cfg *config.Config) (string, []Fix) {
+	if lang != langPython && lang != langJS {
+		return src, nil
+	}
+
+	lines := strings.Split(src, "\n")
+	remove := make(map[int]bool) // 0-based
+	var fixes []Fix
+
+	for _, issue := range issues {
+		idx := issue.Line - 1
+		if !FixableRules[issue.Rule] || cfg.IsRuleDisabled(issue.Rule) || idx < 0 || idx >= len(lines) || remove[idx] {
+			continue
+		}
+		trimmed := strings.TrimSpace(lines[idx])
+
+		switch {
+		case issue.Rule == "ban-print" && lang == langPython && printStmtRe.MatchString(trimmed) && standaloneCall(trimmed):
+		case issue.Rule == "ban-console" && consoleStmtRe.MatchString(trimmed) && standaloneCall(trimmed):
+		default:
+			// Part of a larger expression or spread over lines - leave it
+			continue
+		}
+		remove[idx] = true
+		fixes = append(fixes, Fix{Line: issue.Line, Rule: issue.Rule, Description: "removed " + trimmed})
+	}
+
+	var out []string
+	for i, line := range lines {
+		if !remove[i] {
+			out = append(out, line)
+			continue
+		}
+		// Removing the only statement in a Python block would leave it empty
+		if lang == langPython && onlyStatementInBlock(lines, remove, i) {
+			out = append(out, leadingSpace(line)+"pass")
+		}
+	}
+
+	// Mutable defaults aren't found by the builtin checks, so honour the
+	// toggles here the way checkFileWithConfig would
+	headerRules, headerAll := fileHeaderDisables(lines)
+	if lang == langPython && cfg.Quality.BanMutableDefaults && !cfg.IsRuleDisabled("mutable-default") &&
+		languageRuleEnabled(lang, "mutable-default", cfg) && !headerAll && !headerRules["mutable-default"] {
+		var defaultFixes []Fix
+		out, defaultFixes = fixMutableDefaults(out, remove)
+		fixes = append(fixes, defaultFixes...)
+	}
+
+	return strings.Join(out, "\n"), fixes
+}
+
+// standaloneCall reports whether s is one call and nothing else: the paren
+// matching its first "(" ends the line, apart from an optional ";". Parens
+// inside string literals don't count.
+func standaloneCall(s string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				rest := strings.TrimSpace(s[i+1:])
+				return rest == "" || rest == ";"
+			}
+		}
+	}
+	return false
+}
+
+// leadingSpace returns a line's indentation
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// onlyStatementInBlock reports whether lines[i] is the last line left in
+// the block opened just above it, once the removed lines are gone
+func onlyStatementInBlock(lines []string, remove map[int]bool, i int) bool {
+	indent := len(leadingSpace(lines[i]))
+
+	prev := i - 1
+	for prev >= 0 && (remove[prev] || strings.TrimSpace(lines[prev]) == "") {
+		prev--
+	}
+	if prev < 0 || !strings.HasSuffix(strings.TrimSpace(lines[prev]), ":") || len(leadingSpace(lines[prev])) >= indent {
+		return false
+	}
+
+	next := i + 1
+	for next < len(lines) && (remove[next] || strings.TrimSpace(lines[next]) == "") {
+		next++
+	}
+	return next >= len(lines) || len(leadingSpace(lines[next])) < indent
+}
+
+// fixMutableDefaults rewrites def f(items=[]) to default to None and
+// create the list in the body. Line numbers in the returned fixes refer to
+// the original source, so removed lines are counted back in.
+func fixMutableDefaults(lines []string, removed map[int]bool) ([]string, []Fix) {
+	var fixes []Fix
+	var out []string
+
+	origLine := func(i int) int {
+		n := 0
+		for orig := 0; ; orig++ {
+			if removed[orig] {
+				continue
+			}
+			if n == i {
+				return orig + 1
+			}
+			n++
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		m := pyDefLineRe.FindStringSubmatch(line)
+		if m == nil {
+			out = append(out, line)
+			continue
+		}
+
+		params := splitParams(m[2])
+		var inits []string
+		for j, p := range params {
+			pm := mutableDefaultRe.FindStringSubmatch(p)
+			if pm == nil {
+				continue
+			}
+			name := strings.TrimSpace(pm[1])
+			params[j] = leadingSpace(p) + name + "=None"
+			inits = append(inits, "if "+name+" is None:", "    "+name+" = "+strings.TrimSpace(pm[2]))
+		}
+		if len(inits) == 0 {
+			out = append(out, line)
+			continue
+		}
+
+		// The body's indentation, and where it starts after any docstring
+		body := i + 1
+		for body < len(lines) && strings.TrimSpace(lines[body]) == "" {
+			body++
+		}
+		if body >= len(lines) || len(leadingSpace(lines[body])) <= len(m[1]) {
+			out = append(out, line)
+			continue
+		}
+		indent := leadingSpace(lines[body])
+		if end := docstringEnd(lines, body); end >= 0 {
+			body = end + 1
+		}
+
+		fixed := strings.Replace(line, m[2], strings.Join(params, ","), 1)
+		fixes = append(fixes, Fix{Line: origLine(i), Rule: "mutable-default", Description: "default to None in " + strings.TrimSpace(fixed)})
+
+		out = append(out, fixed)
+		out = append(out, lines[i+1:body]...)
+		for _, init := range inits {
+			out = append(out, indent+init)
+		}
+		i = body - 1
+	}
+
+	return out, fixes
+}
+
+// splitParams splits a parameter list on the commas between parameters,
+// keeping each one's spacing so unchanged parameters rejoin as they were
+func splitParams(s string) []string {
+	var params []string
+	depth, start := 0, 0
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case r == ',' && depth == 0:
+			params = append(params, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(params, s[start:])
+}
+
+// docstringEnd returns the index of the line closing a docstring that opens
+// at lines[start], or -1 if lines[start] doesn't open one
+func docstringEnd(lines []string, start int) int {
+	trimmed := strings.TrimSpace(lines[start])
+	if !strings.HasPrefix(trimmed, `"""`) && !strings.HasPrefix(trimmed, `'''`) {
+		return -1
+	}
+	delim := trimmed[:3]
+	if strings.Contains(trimmed[3:], delim) {
+		return start
+	}
+	for i := start + 1; i < len(lines); i++ {
+		if strings.Contains(lines[i], delim) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,179 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// fixSource runs the checks and fixes on content written to a temp file
+func fixSource(t *testing.T, filename, content string) (string, []Fix) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, filename)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	issues := checkFileWithConfig(path, filename, cfg)
+	return ApplyFixes(content, languageFor(path, cfg), issues, cfg)
+}
+
+func TestApplyFixes_RemovesPrint(t *testing.T) {
+	got, fixes := fixSource(t, "app.py", "def run():\n    x = 1\n    print(x)\n    return x\n")
+	want := "def run():\n    x = 1\n    return x\n"
+	if got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+	if len(fixes) != 1 || fixes[0].Line != 3 || fixes[0].Rule != "ban-print" {
+		t.Errorf("unexpected fixes: %+v", fixes)
+	}
+}
+
+func TestApplyFixes_EmptyBlockGetsPass(t *testing.T) {
+	got, _ := fixSource(t, "app.py", "if debug:\n    print(\"state\", state)\nrun()\n")
+	want := "if debug:\n    pass\nrun()\n"
+	if got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestApplyFixes_LeavesPrintInExpressions(t *testing.T) {
+	src := "result = print(x) or y\nprint(\"a\",\n      \"b\")\n"
+	got, fixes := fixSource(t, "app.py", src)
+	if got != src || len(fixes) != 0 {
+		t.Errorf("expected no change, got:\n%s\nfixes: %+v", got, fixes)
+	}
+}
+
+func TestApplyFixes_LeavesStatementsAfterTheCall(t *testing.T) {
+	for _, tt := range []struct{ filename, src string }{
+		{"app.py", "print(\"a\"); save()\n"},
+		{"app.py", "print(\"(\"); save()\n"},
+		{"app.js", "console.log(x); go();\n"},
+	} {
+		got, fixes := fixSource(t, tt.filename, tt.src)
+		if got != tt.src || len(fixes) != 0 {
+			t.Errorf("%q: expected no change, got:\n%s\nfixes: %+v", tt.src, got, fixes)
+		}
+	}
+}
+
+func TestApplyFixes_RemovesConsoleLog(t *testing.T) {
+	got, fixes := fixSource(t, "app.js", "function run() {\n  console.log(\"here\");\n  return 1;\n}\n")
+	want := "function run() {\n  return 1;\n}\n"
+	if got != want || len(fixes) != 1 {
+		t.Errorf("expected:\n%s\ngot:\n%s\nfixes: %+v", want, got, fixes)
+	}
+}
+
+func TestApplyFixes_MutableDefault(t *testing.T) {
+	src := `def add(item, items=[], opts = {}, name="x"):
+    """Add an item."""
+    items.append(item)
+    return items
+`
+	want := `def add(item, items=None, opts=None, name="x"):
+    """Add an item."""
+    if items is None:
+        items = []
+    if opts is None:
+        opts = {}
+    items.append(item)
+    return items
+`
+	got, fixes := fixSource(t, "app.py", src)
+	if got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+	if len(fixes) != 1 || fixes[0].Rule != "mutable-default" || fixes[0].Line != 1 {
+		t.Errorf("unexpected fixes: %+v", fixes)
+	}
+}
+
+func TestApplyFixes_GoUntouched(t *testing.T) {
+	src := "package lib\n\nimport \"fmt\"\n\nfunc Run() {\n\tfmt.Println(\"x\")\n}\n"
+	got, fixes := fixSource(t, "lib.go", src)
+	if got != src || len(fixes) != 0 {
+		t.Errorf("Go files should not be fixed, got:\n%s", got)
+	}
+}
+
+func TestApplyFixes_DisabledRuleUntouched(t *testing.T) {
+	src := "# guardian: disable=ban-print\nprint(x)\n"
+	got, fixes := fixSource(t, "app.py", src)
+	if got != src || len(fixes) != 0 {
+		t.Errorf("disabled rule should not be fixed, got:\n%s", got)
+	}
+}
+
+func TestPreviewFix_DiffWithoutWriting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.py")
+	content := "def run():\n    x = 1\n    print(x)\n    return x\n"
+	os.WriteFile(path, []byte(content), 0644)
+
+	fixes, diff, err := PreviewFix(path, "app.py", config.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixes) != 1 || fixes[0].Rule != "ban-print" {
+		t.Errorf("expected one ban-print fix, got %+v", fixes)
+	}
+	want := "--- a/app.py\n+++ b/app.py\n@@ -1,4 +1,3 @@\n def run():\n     x = 1\n-    print(x)\n     return x\n"
+	if diff != want {
+		t.Errorf("expected diff:\n%s\ngot:\n%s", want, diff)
+	}
+	if got, _ := os.ReadFile(path); string(got) != content {
+		t.Errorf("preview must not write the file, got:\n%s", got)
+	}
+}
+
+func TestPreviewFix_NothingToFix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.py")
+	os.WriteFile(path, []byte("x = 1\n"), 0644)
+
+	fixes, diff, err := PreviewFix(path, "app.py", config.DefaultConfig())
+	if err != nil || len(fixes) != 0 || diff != "" {
+		t.Errorf("expected no fixes and no diff, got %+v %q %v", fixes, diff, err)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	var before []string
+	for i := 1; i <= 20; i++ {
+		before = append(before, fmt.Sprintf("line%d", i))
+	}
+	after := slices.Clone(before)
+	after[1] = "changed2"                            // Line 2
+	after = slices.Insert(after, 17, "new", "lines") // After line 17
+
+	got := UnifiedDiff("f.py", strings.Join(before, "\n")+"\n", strings.Join(after, "\n")+"\n")
+	want := `--- a/f.py
++++ b/f.py
+@@ -1,5 +1,5 @@
+ line1
+-line2
++changed2
+ line3
+ line4
+ line5
+@@ -15,6 +15,8 @@
+ line15
+ line16
+ line17
++new
++lines
+ line18
+ line19
+ line20
+`
+	if got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
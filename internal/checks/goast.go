@@ -0,0 +1,110 @@
+package checks
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// goPrintFuncs are the fmt functions ban-print flags
+var goPrintFuncs = map[string]bool{"Print": true, "Println": true, "Printf": true}
+
+// checkGoAST finds ban-print, library-panic and ignored-error in a Go file
+// from its syntax tree, so a call named in a string or comment is never
+// flagged and one split over lines is reported where it starts. parsed is
+// false when the file doesn't parse, e.g. mid-edit; the line-based checks
+// cover it then.
+func checkGoAST(relPath string, lines []string, cfg *config.Config, isTest bool) (issues []Issue, parsed bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, relPath, strings.Join(lines, "\n"), 0)
+	if err != nil {
+		return nil, false
+	}
+	isMain := file.Name.Name == "main"
+
+	// The names fmt is imported under
+	fmtNames := make(map[string]bool)
+	for _, imp := range file.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == "fmt" {
+			if imp.Name != nil {
+				fmtNames[imp.Name.Name] = true
+			} else {
+				fmtNames["fmt"] = true
+			}
+		}
+	}
+
+	add := func(node ast.Node, rule, message, severity string) {
+		issues = append(issues, Issue{
+			File:     relPath,
+			Line:     fset.Position(node.Pos()).Line,
+			Rule:     rule,
+			Message:  message,
+			Severity: severity,
+		})
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			switch fun := n.Fun.(type) {
+			case *ast.SelectorExpr:
+				// fmt.Println(...), unless fmt is a local variable here
+				pkg, ok := fun.X.(*ast.Ident)
+				if ok && pkg.Obj == nil && fmtNames[pkg.Name] && goPrintFuncs[fun.Sel.Name] &&
+					cfg.Quality.BanPrint && !isMain {
+					add(n, "ban-print", "Remove fmt."+fun.Sel.Name+"() - use a logger", "info")
+				}
+			case *ast.Ident:
+				// The builtins, unless the file declares its own
+				if fun.Obj != nil {
+					break
+				}
+				if (fun.Name == "print" || fun.Name == "println") && cfg.Quality.BanPrint && !isMain {
+					add(n, "ban-print", "Remove "+fun.Name+"() - use a logger", "info")
+				}
+				if fun.Name == "panic" && cfg.Quality.BanLibraryPanic && !isMain && !isTest {
+					add(n, "library-panic", "panic() in library code - return an error instead", "info")
+				}
+			}
+
+		case *ast.AssignStmt:
+			// _ = err and _ = f.Close()
+			if !cfg.Quality.BanIgnoredErrors || n.Tok != token.ASSIGN || len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+				break
+			}
+			if blank, ok := n.Lhs[0].(*ast.Ident); !ok || blank.Name != "_" {
+				break
+			}
+			switch rhs := n.Rhs[0].(type) {
+			case *ast.Ident:
+				if strings.HasPrefix(rhs.Name, "err") {
+					add(n, "ignored-error", "Error assigned to _ - handle or return it", "warning")
+				}
+			case *ast.CallExpr:
+				add(n, "ignored-error", "Result of "+goCallName(rhs)+"() assigned to _ - handle or return its error", "warning")
+			}
+		}
+		return true
+	})
+	return issues, true
+}
+
+// goCallName renders a call's function for messages: Close, f.Close,
+// os.Remove
+func goCallName(call *ast.CallExpr) string {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		if x, ok := fun.X.(*ast.Ident); ok {
+			return x.Name + "." + fun.Sel.Name
+		}
+		return fun.Sel.Name
+	}
+	return "call"
+}
@@ -0,0 +1,59 @@
+package checks
+
+import (
+	"regexp"
+	"strings"
+)
+
+// headerScanLines is how far into a file a guardian: header is looked for,
+// leaving room for a shebang, encoding line or license banner
+const headerScanLines = 5
+
+// A file-level switch in a comment: "# guardian: disable=ban-print,todo-marker"
+// or "// guardian: disable-all"
+var fileHeaderRe = regexp.MustCompile(`^(?:#|//|/\*|<!--)\s*guardian:\s*(disable-all\b|disable\s*=\s*([\w\s,-]+))`)
+
+// fileHeaderDisables reads the rules a file turns off for itself. all is
+// true for disable-all.
+func fileHeaderDisables(lines []string) (rules map[string]bool, all bool) {
+	for i, line := range lines {
+		if i >= headerScanLines {
+			break
+		}
+		m := fileHeaderRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		if m[1] == "disable-all" {
+			return nil, true
+		}
+		if rules == nil {
+			rules = make(map[string]bool)
+		}
+		for _, rule := range strings.Split(m[2], ",") {
+			if rule = strings.TrimSpace(rule); rule != "" {
+				rules[rule] = true
+			}
+		}
+	}
+	return rules, false
+}
+
+// applyFileHeader drops issues for rules the file's header disables
+func applyFileHeader(issues []Issue, lines []string) []Issue {
+	rules, all := fileHeaderDisables(lines)
+	if all {
+		return nil
+	}
+	if len(rules) == 0 {
+		return issues
+	}
+
+	var kept []Issue
+	for _, issue := range issues {
+		if !rules[issue.Rule] {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,173 @@
+package checks
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoryFile is where check summaries are appended, relative to the project root
+const HistoryFile = ".guardian/history.jsonl"
+
+// HistoryEntry is one recorded check run
+type HistoryEntry struct {
+	Time     time.Time `json:"time"`
+	Critical int       `json:"critical"`
+	Warning  int       `json:"warning"`
+	Info     int       `json:"info"`
+	Total    int       `json:"total"`
+}
+
+// Trend summarizes how issue counts moved across a set of runs
+type Trend struct {
+	Runs      int
+	First     HistoryEntry
+	Last      HistoryEntry
+	Delta     int    // Last.Total - First.Total
+	Direction string // "down", "up" or "flat"
+}
+
+// Summarize counts issues by severity for a history entry
+func Summarize(issues []Issue, at time.Time) HistoryEntry {
+	entry := HistoryEntry{Time: at.UTC(), Total: len(issues)}
+	for _, issue := range issues {
+		switch issue.Severity {
+		case "critical":
+			entry.Critical++
+		case "warning":
+			entry.Warning++
+		default:
+			entry.Info++
+		}
+	}
+	return entry
+}
+
+// AppendHistory adds one run to the project's history file
+func AppendHistory(dir string, entry HistoryEntry) error {
+	path := filepath.Join(dir, HistoryFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// LoadHistory reads recorded runs, oldest first. A missing file is not an error;
+// malformed lines are skipped so one bad write doesn't hide the rest.
+func LoadHistory(dir string) ([]HistoryEntry, error) {
+	f, err := os.Open(filepath.Join(dir, HistoryFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry HistoryEntry
+		if json.Unmarshal([]byte(line), &entry) != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// ParseSince parses a lookback window like "30d", "2w" or "12h"
+func ParseSince(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		count, err := strconv.Atoi(s[:n-1])
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		days := count
+		if s[n-1] == 'w' {
+			days *= 7
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	return d, nil
+}
+
+// FilterSince keeps entries recorded at or after the cutoff
+func FilterSince(entries []HistoryEntry, cutoff time.Time) []HistoryEntry {
+	var kept []HistoryEntry
+	for _, entry := range entries {
+		if !entry.Time.Before(cutoff) {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// ComputeTrend compares the first and last run in entries
+func ComputeTrend(entries []HistoryEntry) Trend {
+	trend := Trend{Runs: len(entries), Direction: "flat"}
+	if len(entries) == 0 {
+		return trend
+	}
+
+	trend.First = entries[0]
+	trend.Last = entries[len(entries)-1]
+	trend.Delta = trend.Last.Total - trend.First.Total
+	switch {
+	case trend.Delta < 0:
+		trend.Direction = "down"
+	case trend.Delta > 0:
+		trend.Direction = "up"
+	}
+	return trend
+}
+
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a row of block characters scaled to the max
+func Sparkline(values []int) string {
+	maxVal := 0
+	for _, v := range values {
+		if v > maxVal {
+			maxVal = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if maxVal > 0 && v > 0 {
+			idx = v * (len(sparkBars) - 1) / maxVal
+		}
+		b.WriteRune(sparkBars[idx])
+	}
+	return b.String()
+}
@@ -0,0 +1,162 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Helper to append a synthetic run with the given total (all warnings)
+func appendRun(t *testing.T, dir string, at time.Time, total int) {
+	t.Helper()
+	if err := AppendHistory(dir, HistoryEntry{Time: at, Warning: total, Total: total}); err != nil {
+		t.Fatalf("AppendHistory failed: %v", err)
+	}
+}
+
+// ============================================================================
+// HISTORY
+// ============================================================================
+
+func TestHistory_AppendAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	appendRun(t, dir, now.Add(-2*time.Hour), 5)
+	appendRun(t, dir, now.Add(-time.Hour), 3)
+
+	entries, err := LoadHistory(dir)
+	if err != nil {
+		t.Fatalf("LoadHistory failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Total != 5 || entries[1].Total != 3 {
+		t.Errorf("entries out of order or wrong: %+v", entries)
+	}
+	if !entries[1].Time.Equal(now.Add(-time.Hour)) {
+		t.Errorf("timestamp not preserved: %v", entries[1].Time)
+	}
+}
+
+func TestHistory_MissingFile(t *testing.T) {
+	entries, err := LoadHistory(t.TempDir())
+	if err != nil || len(entries) != 0 {
+		t.Errorf("expected no entries and no error, got %v, %v", entries, err)
+	}
+}
+
+func TestHistory_SkipsMalformedLines(t *testing.T) {
+	dir := t.TempDir()
+	appendRun(t, dir, time.Now(), 4)
+
+	f, _ := os.OpenFile(filepath.Join(dir, HistoryFile), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("not json\n")
+	f.Close()
+	appendRun(t, dir, time.Now(), 2)
+
+	entries, _ := LoadHistory(dir)
+	if len(entries) != 2 {
+		t.Errorf("expected malformed line to be skipped, got %d entries", len(entries))
+	}
+}
+
+func TestSummarize_CountsBySeverity(t *testing.T) {
+	issues := []Issue{
+		{Severity: "critical"},
+		{Severity: "warning"},
+		{Severity: "warning"},
+		{Severity: "info"},
+	}
+	entry := Summarize(issues, time.Now())
+	if entry.Critical != 1 || entry.Warning != 2 || entry.Info != 1 || entry.Total != 4 {
+		t.Errorf("unexpected counts: %+v", entry)
+	}
+}
+
+// ============================================================================
+// TREND
+// ============================================================================
+
+func TestComputeTrend_Down(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, total := range []int{10, 8, 8, 4} {
+		appendRun(t, dir, now.Add(time.Duration(i)*time.Hour), total)
+	}
+
+	entries, _ := LoadHistory(dir)
+	trend := ComputeTrend(entries)
+	if trend.Runs != 4 || trend.Delta != -6 || trend.Direction != "down" {
+		t.Errorf("unexpected trend: %+v", trend)
+	}
+}
+
+func TestComputeTrend_UpAndFlat(t *testing.T) {
+	up := ComputeTrend([]HistoryEntry{{Total: 1}, {Total: 3}})
+	if up.Direction != "up" || up.Delta != 2 {
+		t.Errorf("expected up by 2, got %+v", up)
+	}
+
+	flat := ComputeTrend(nil)
+	if flat.Direction != "flat" || flat.Runs != 0 {
+		t.Errorf("expected flat empty trend, got %+v", flat)
+	}
+}
+
+func TestFilterSince_DropsOldRuns(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	appendRun(t, dir, now.Add(-45*24*time.Hour), 20)
+	appendRun(t, dir, now.Add(-10*24*time.Hour), 12)
+	appendRun(t, dir, now.Add(-time.Hour), 9)
+
+	window, err := ParseSince("30d")
+	if err != nil {
+		t.Fatalf("ParseSince failed: %v", err)
+	}
+	entries, _ := LoadHistory(dir)
+	recent := FilterSince(entries, now.Add(-window))
+	if len(recent) != 2 {
+		t.Fatalf("expected 2 runs within 30d, got %d", len(recent))
+	}
+
+	trend := ComputeTrend(recent)
+	if trend.First.Total != 12 || trend.Delta != -3 {
+		t.Errorf("trend should start from the first run in the window: %+v", trend)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"30d", 30 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"12h", 12 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "d", "abc", "-3d"} {
+		if _, err := ParseSince(bad); err == nil {
+			t.Errorf("ParseSince(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]int{0, 4, 8}); got != "▁▄█" {
+		t.Errorf("Sparkline = %q", got)
+	}
+	if got := Sparkline([]int{0, 0}); got != "▁▁" {
+		t.Errorf("all-zero sparkline = %q", got)
+	}
+}
@@ -0,0 +1,90 @@
+package checks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// LastRunFile holds the issues from the most recent full check, relative to
+// the project root
+const LastRunFile = ".guardian/last-run.json"
+
+// SaveLastRun replaces the stored issues with this run's
+func SaveLastRun(dir string, issues []Issue) error {
+	return saveIssues(filepath.Join(dir, LastRunFile), issues)
+}
+
+// LoadLastRun reads the issues saved by the previous run. found is false
+// when no run has been saved yet.
+func LoadLastRun(dir string) (issues []Issue, found bool, err error) {
+	return loadIssues(filepath.Join(dir, LastRunFile))
+}
+
+// saveIssues writes issues to path as indented JSON
+func saveIssues(path string, issues []Issue) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	if issues == nil {
+		issues = []Issue{}
+	}
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// loadIssues reads issues written by saveIssues. found is false when path
+// doesn't exist.
+func loadIssues(path string) (issues []Issue, found bool, err error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return nil, false, err
+	}
+	return issues, true, nil
+}
+
+// issueKey identifies an issue across runs. Line numbers are left out so
+// that editing code above an issue doesn't make it look new.
+type issueKey struct {
+	File, Rule, Message string
+}
+
+// DiffIssues compares two runs. added are in cur but not prev; fixed are in
+// prev but not cur. Repeats of the same issue in a file are counted, so a
+// second copy of an existing problem still shows up as added.
+func DiffIssues(prev, cur []Issue) (added, fixed []Issue) {
+	remaining := make(map[issueKey]int)
+	for _, issue := range prev {
+		remaining[issueKey{issue.File, issue.Rule, issue.Message}]++
+	}
+
+	for _, issue := range cur {
+		key := issueKey{issue.File, issue.Rule, issue.Message}
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		added = append(added, issue)
+	}
+
+	// Whatever wasn't matched is gone; report the last copies of each
+	for i := len(prev) - 1; i >= 0; i-- {
+		key := issueKey{prev[i].File, prev[i].Rule, prev[i].Message}
+		if remaining[key] > 0 {
+			remaining[key]--
+			fixed = append([]Issue{prev[i]}, fixed...)
+		}
+	}
+
+	return added, fixed
+}
@@ -0,0 +1,165 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// LAST RUN
+// ============================================================================
+
+func TestLastRun_SaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, found, err := LoadLastRun(dir); found || err != nil {
+		t.Fatalf("expected no saved run yet, got found=%v err=%v", found, err)
+	}
+
+	issues := []Issue{
+		{File: "app.py", Line: 3, Rule: "ban-eval", Message: "Avoid eval() - security risk", Severity: "critical", Confidence: "high"},
+	}
+	if err := SaveLastRun(dir, issues); err != nil {
+		t.Fatalf("SaveLastRun failed: %v", err)
+	}
+
+	loaded, found, err := LoadLastRun(dir)
+	if err != nil || !found {
+		t.Fatalf("LoadLastRun failed: found=%v err=%v", found, err)
+	}
+	if len(loaded) != 1 || loaded[0] != issues[0] {
+		t.Errorf("round trip changed issues: %+v", loaded)
+	}
+}
+
+func TestLastRun_SaveEmpty(t *testing.T) {
+	dir := t.TempDir()
+	SaveLastRun(dir, nil)
+
+	loaded, found, err := LoadLastRun(dir)
+	if err != nil || !found || len(loaded) != 0 {
+		t.Errorf("expected an empty saved run, got %v, found=%v, err=%v", loaded, found, err)
+	}
+}
+
+func TestDiffIssues_NewAndFixed(t *testing.T) {
+	prev := []Issue{
+		{File: "a.py", Line: 1, Rule: "ban-eval", Message: "eval"},
+		{File: "a.py", Line: 5, Rule: "ban-print", Message: "print"},
+	}
+	cur := []Issue{
+		// Same issue, moved down two lines by an edit above it
+		{File: "a.py", Line: 3, Rule: "ban-eval", Message: "eval"},
+		{File: "b.py", Line: 2, Rule: "ban-star", Message: "star"},
+	}
+
+	added, fixed := DiffIssues(prev, cur)
+	if len(added) != 1 || added[0].File != "b.py" {
+		t.Errorf("expected b.py as the only new issue, got %+v", added)
+	}
+	if len(fixed) != 1 || fixed[0].Rule != "ban-print" {
+		t.Errorf("expected ban-print as the only fixed issue, got %+v", fixed)
+	}
+}
+
+func TestDiffIssues_CountsRepeats(t *testing.T) {
+	one := Issue{File: "a.py", Line: 1, Rule: "ban-print", Message: "print"}
+	two := Issue{File: "a.py", Line: 9, Rule: "ban-print", Message: "print"}
+
+	added, fixed := DiffIssues([]Issue{one}, []Issue{one, two})
+	if len(added) != 1 || added[0].Line != 9 || len(fixed) != 0 {
+		t.Errorf("a second copy should be new: added=%+v fixed=%+v", added, fixed)
+	}
+
+	added, fixed = DiffIssues([]Issue{one, two}, []Issue{one})
+	if len(added) != 0 || len(fixed) != 1 {
+		t.Errorf("removing one copy should fix one: added=%+v fixed=%+v", added, fixed)
+	}
+}
+
+// ============================================================================
+// BASELINE
+// ============================================================================
+
+func TestBaseline_FilterKeepsOnlyNewIssues(t *testing.T) {
+	dir := t.TempDir()
+	accepted := []Issue{{File: "a.py", Line: 1, Rule: "ban-eval", Message: "eval"}}
+	if err := SaveBaseline(dir, accepted, BaselineFuzzy); err != nil {
+		t.Fatalf("SaveBaseline failed: %v", err)
+	}
+
+	baseline, found, err := LoadBaseline(dir)
+	if err != nil || !found {
+		t.Fatalf("LoadBaseline failed: found=%v err=%v", found, err)
+	}
+
+	cur := []Issue{
+		{File: "a.py", Line: 4, Rule: "ban-eval", Message: "eval"}, // Moved, still accepted
+		{File: "a.py", Line: 9, Rule: "ban-print", Message: "print"},
+	}
+	kept := FilterBaseline(dir, cur, baseline)
+	if len(kept) != 1 || kept[0].Rule != "ban-print" {
+		t.Errorf("expected only the new ban-print, got %+v", kept)
+	}
+}
+
+func TestBaseline_StrictAndFuzzyFormats(t *testing.T) {
+	for _, tt := range []struct {
+		format      string
+		wantShifted bool // Whether the shifted issue is reported as new
+	}{
+		{BaselineStrict, true},
+		{BaselineFuzzy, false},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "a.py")
+			os.WriteFile(path, []byte("x = eval(data)\n"), 0644)
+			accepted := []Issue{{File: "a.py", Line: 1, Rule: "ban-eval", Message: "eval"}}
+			if err := SaveBaseline(dir, accepted, tt.format); err != nil {
+				t.Fatalf("SaveBaseline failed: %v", err)
+			}
+
+			// The format is recorded in the file's header
+			data, _ := os.ReadFile(filepath.Join(dir, BaselineFile))
+			if !strings.Contains(string(data), `"format": "`+tt.format+`"`) {
+				t.Errorf("expected format %s in the baseline header:\n%s", tt.format, data)
+			}
+			baseline, _, err := LoadBaseline(dir)
+			if err != nil || baseline.Format != tt.format {
+				t.Fatalf("LoadBaseline: got %+v, err=%v", baseline, err)
+			}
+
+			// Reformatting pushes the same line down and re-indents it
+			os.WriteFile(path, []byte("import os\n\nif True:\n    x = eval(data)\n"), 0644)
+			shifted := []Issue{{File: "a.py", Line: 4, Rule: "ban-eval", Message: "eval"}}
+			if kept := FilterBaseline(dir, shifted, baseline); (len(kept) == 1) != tt.wantShifted {
+				t.Errorf("shifted issue: got %+v, want reported=%v", kept, tt.wantShifted)
+			}
+
+			// A changed line is a new issue in either format
+			os.WriteFile(path, []byte("import os\n\nif True:\n    x = eval(other)\n"), 0644)
+			if kept := FilterBaseline(dir, shifted, baseline); len(kept) != 1 {
+				t.Errorf("expected the edited line to be reported, got %+v", kept)
+			}
+		})
+	}
+}
+
+func TestBaseline_LegacyListStillLoads(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".guardian"), 0755)
+	legacy := `[{"file": "a.py", "line": 1, "rule": "ban-eval", "message": "eval", "severity": "critical"}]`
+	os.WriteFile(filepath.Join(dir, BaselineFile), []byte(legacy), 0644)
+
+	baseline, found, err := LoadBaseline(dir)
+	if err != nil || !found || baseline.Format != "" || len(baseline.Issues) != 1 {
+		t.Fatalf("LoadBaseline: got %+v, found=%v, err=%v", baseline, found, err)
+	}
+	cur := []Issue{{File: "a.py", Line: 7, Rule: "ban-eval", Message: "eval"}}
+	if kept := FilterBaseline(dir, cur, baseline); len(kept) != 0 {
+		t.Errorf("expected a legacy baseline to match on file, rule and message, got %+v", kept)
+	}
+}
@@ -0,0 +1,121 @@
+package checks
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// A logging or print call: print(), console.log(), logger.info(),
+// logging.warning(), log.Printf(), slog.Info() and the like
+var logCallRe = regexp.MustCompile(`(?:^|[^\w.])(?:print|console\.(?:log|info|warn|error|debug)|` +
+	`(?:\w+\.)*(?:log|logger|logging|_logger|_log|LOG|LOGGER|slog)\.(?:debug|info|warning|warn|error|exception|critical|fatal|trace|` +
+	`Print\w*|Fatal\w*|Panic\w*|Debug\w*|Info\w*|Warn\w*|Error\w*))\s*\(`)
+
+// splitFieldWords turns the configured field names into word lists, so
+// "credit_card" matches credit_card, creditCard and CREDIT_CARD alike
+func splitFieldWords(fields []string) [][]string {
+	var words [][]string
+	for _, f := range fields {
+		if w := identifierWords(f); len(w) > 0 {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// identifierWords splits an identifier on underscores and camelCase humps,
+// lowercased: userEmail and user_email both give [user email]
+func identifierWords(id string) []string {
+	var words []string
+	var cur strings.Builder
+	runes := []rune(id)
+	flush := func() {
+		if cur.Len() > 0 {
+			words = append(words, strings.ToLower(cur.String()))
+			cur.Reset()
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))):
+			flush()
+		}
+		cur.WriteRune(r)
+	}
+	flush()
+	return words
+}
+
+// codeOfCall drops string literal text from a call, keeping interpolated
+// parts ({user.email} in f-strings, ${user.email} in template literals), so
+// a message that merely mentions "email" isn't treated as logging one
+func codeOfCall(call string) string {
+	var sb strings.Builder
+	var quote rune
+	depth := 0 // Brace depth inside a string
+	for _, r := range call {
+		switch {
+		case quote == 0:
+			if r == '"' || r == '\'' || r == '`' {
+				quote = r
+				sb.WriteRune(' ')
+				continue
+			}
+			sb.WriteRune(r)
+		case depth > 0:
+			if r == '}' {
+				depth--
+			} else if r == '{' {
+				depth++
+			}
+			sb.WriteRune(r)
+		case r == '{':
+			depth++
+			sb.WriteRune(' ')
+		case r == quote:
+			quote = 0
+			sb.WriteRune(' ')
+		default:
+			sb.WriteRune(' ')
+		}
+	}
+	return sb.String()
+}
+
+var identRe = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// loggedPIIField returns the first configured field a logging call refers
+// to, as written in the code
+func loggedPIIField(call string, fields [][]string) (string, bool) {
+	for _, id := range identRe.FindAllString(codeOfCall(call), -1) {
+		words := identifierWords(id)
+		for _, field := range fields {
+			if containsWords(words, field) {
+				return id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// containsWords reports whether want appears as a contiguous run in words
+func containsWords(words, want []string) bool {
+	for i := 0; i+len(want) <= len(words); i++ {
+		match := true
+		for j := range want {
+			if words[i+j] != want[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
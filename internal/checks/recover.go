@@ -0,0 +1,59 @@
+package checks
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// FileError is a file that was skipped: too large to scan, or a check
+// panicked on it. Its issues are missing from the run; every other file is
+// still checked.
+type FileError struct {
+	File  string
+	Err   string
+	Stack string // Set for panics
+}
+
+var (
+	fileErrorsMu sync.Mutex
+	fileErrors   []FileError
+)
+
+// checkFileFunc is the per-file checker, swapped out by tests
+var checkFileFunc = checkFileWithConfig
+
+// checkFileRecovered runs the builtin checks on one file, turning a panic in
+// any rule into a FileError so one bad file can't take down the whole run
+func checkFileRecovered(path, relPath string, cfg *config.Config) (issues []Issue) {
+	defer func() {
+		if r := recover(); r != nil {
+			recordFileError(FileError{
+				File:  relPath,
+				Err:   "check crashed: " + fmt.Sprint(r),
+				Stack: string(debug.Stack()),
+			})
+			issues = nil
+		}
+	}()
+	return checkFileFunc(path, relPath, cfg)
+}
+
+// recordFileError notes a skipped file for the next TakeFileErrors
+func recordFileError(fe FileError) {
+	fileErrorsMu.Lock()
+	fileErrors = append(fileErrors, fe)
+	fileErrorsMu.Unlock()
+}
+
+// TakeFileErrors returns the files that failed since the last call and
+// clears the list
+func TakeFileErrors() []FileError {
+	fileErrorsMu.Lock()
+	defer fileErrorsMu.Unlock()
+	errs := fileErrors
+	fileErrors = nil
+	return errs
+}
@@ -0,0 +1,93 @@
+package checks
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// @app.get("/items"), @router.post(...), @bp.route(...)
+	routeDecoratorRe = regexp.MustCompile(`^@\w+(?:\.\w+)*\.(?:get|post|put|patch|delete|route|api_route)\s*\(`)
+	pyDefNameRe      = regexp.MustCompile(`^(?:async\s+)?def\s+(\w+)\s*\(`)
+)
+
+// unprotectedRoute is a route handler with nothing from the auth allowlist
+// in its decorators or signature
+type unprotectedRoute struct {
+	line int // The def line
+	name string
+}
+
+// routeTracker collects each Python decorator stack, including decorators
+// spread over several lines, and judges it when the def it decorates
+// arrives
+type routeTracker struct {
+	markers []string
+	text    strings.Builder // Decorators seen since the last def
+	route   bool            // One of them registers a route
+	depth   int             // Open parentheses in the current decorator
+	open    bool            // Inside a decorator stack
+}
+
+func newRouteTracker(lang string, markers []string) *routeTracker {
+	if lang != langPython {
+		return nil
+	}
+	return &routeTracker{markers: markers}
+}
+
+// feed takes the next non-blank, non-comment line. On the def of a route
+// with no auth marker it returns the handler; lines is needed to read a
+// signature that spans lines.
+func (t *routeTracker) feed(lines []string, i int) *unprotectedRoute {
+	trimmed := strings.TrimSpace(lines[i])
+
+	switch {
+	case t.open && t.depth > 0:
+		// Continuation of a multi-line decorator
+	case strings.HasPrefix(trimmed, "@"):
+		if !t.open {
+			t.reset()
+			t.open = true
+		}
+		t.route = t.route || routeDecoratorRe.MatchString(trimmed)
+	case t.open:
+		t.open = false
+		m := pyDefNameRe.FindStringSubmatch(trimmed)
+		if m == nil || !t.route {
+			return nil
+		}
+		// FastAPI declares auth as a parameter: user = Depends(get_user)
+		col := strings.Index(lines[i], "(")
+		t.text.WriteString(callText(lines, i, col))
+		if t.hasMarker() {
+			return nil
+		}
+		return &unprotectedRoute{line: i + 1, name: m[1]}
+	default:
+		return nil
+	}
+
+	t.text.WriteString(trimmed)
+	t.text.WriteString("\n")
+	t.depth += strings.Count(trimmed, "(") - strings.Count(trimmed, ")")
+	return nil
+}
+
+func (t *routeTracker) reset() {
+	t.text.Reset()
+	t.route = false
+	t.depth = 0
+}
+
+// hasMarker reports whether the stack and signature mention anything from
+// the auth allowlist
+func (t *routeTracker) hasMarker() bool {
+	text := t.text.String()
+	for _, marker := range t.markers {
+		if marker != "" && strings.Contains(text, marker) {
+			return true
+		}
+	}
+	return false
+}
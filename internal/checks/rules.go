@@ -0,0 +1,109 @@
+package checks
+
+import (
+	"strings"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// RuleInfo describes a rule the builtin checks report
+type RuleInfo struct {
+	ID          string
+	Description string // What it flags, short enough for one About screen row
+	Severity    string // What its issues are reported as
+	Group       string // "quality" or "security", matching the config section
+}
+
+// Rules is every rule the builtin checks can report. The scripts in
+// .guardian/ also report func-size and mutable-default, which the Go engine
+// doesn't check.
+var Rules = []RuleInfo{
+	{"file-size", "Files over max_file_lines (500)", "warning", "quality"},
+	{"large-file", "Files over 5MB, committed binaries", "warning", "quality"},
+	{"mock-data", "test_, fake_, example@, placeholder", "warning", "quality"},
+	{"ban-print", "print(), fmt.Println() debug output", "info", "quality"},
+	{"ban-console", "console.log()", "info", "quality"},
+	{"ban-except", "Bare except: blocks", "warning", "quality"},
+	{"ban-eval", "eval(), exec()", "critical", "security"},
+	{"ban-star", "from x import *", "warning", "quality"},
+	{"todo-marker", "TODO, FIXME, HACK", "info", "quality"},
+	{"dangerous-cmd", "rm -rf, DROP TABLE, UPDATE without WHERE", "critical", "security"},
+	{"secret-pattern", "api_key=, password=, provider tokens", "critical", "security"},
+	{"subprocess-shell", "shell=True", "warning", "security"},
+	{"sql-injection", "f-strings in SQL", "critical", "security"},
+	{"no-timeout", "requests.get(url), fetch(url), no timeout", "info", "quality"},
+	{"hardcoded-path", "/Users/alice/..., C:\\Users\\...", "info", "quality"},
+	{"assert-validation", "assert user.is_admin outside tests", "warning", "security"},
+	{"insecure-cors", "Access-Control-Allow-Origin: *", "warning", "security"},
+	{"insecure-tls", "verify=False, InsecureSkipVerify", "critical", "security"},
+	{"insecure-deserialization", "pickle.loads(), unsafe yaml.load()", "critical", "security"},
+	{"pii-logging", "logger.info(user.email)", "warning", "security"},
+	{"permissive-chmod", "os.chmod(path, 0o777)", "warning", "security"},
+	{"unprotected-route", "@app.get() handler with no auth", "info", "security"},
+	{"library-panic", "panic() in non-main Go packages", "info", "quality"},
+	{"ignored-error", "_ = err in Go", "warning", "quality"},
+	{"curl-pipe-sh", "curl ... | sh in scripts, Dockerfiles", "warning", "security"},
+	{"remote-exec", "eval \"$(curl ...)\", chmod +x on downloads", "critical", "security"},
+	{"unpinned-dependency", "requests>=2 in requirements.txt", "info", "security"},
+	{"url-dependency", "git+https://, file: deps in package.json", "warning", "security"},
+	{"blocking-in-async", "time.sleep() in async def", "warning", "quality"},
+	{"unawaited-async", "fetch_user() without await", "warning", "quality"},
+	{"log-and-ignore", "except: logger.debug(e), carry on", "warning", "quality"},
+	{"commented-code", "4+ lines of commented-out code", "info", "quality"},
+	{"unreachable-code", "statements after return/raise/throw", "info", "quality"},
+	{"suppression-comment", "# type: ignore, # noqa, @ts-ignore, eslint-disable", "info", "quality"},
+	{"stub-implementation", "def f(): pass, throw new Error(\"not implemented\")", "info", "quality"},
+}
+
+// LookupRule returns the registered rule with the given id
+func LookupRule(id string) (RuleInfo, bool) {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return RuleInfo{}, false
+}
+
+// DocURL is where the rule is documented: base/<id>, or "" when base is
+// empty
+func (r RuleInfo) DocURL(base string) string {
+	if base == "" {
+		return ""
+	}
+	return strings.TrimRight(base, "/") + "/" + r.ID
+}
+
+// RuleDocURL is the doc link for a rule under cfg's docs_base_url. Custom
+// rules aren't documented there, so they get "".
+func RuleDocURL(id string, cfg *config.Config) string {
+	rule, ok := LookupRule(id)
+	if !ok {
+		return ""
+	}
+	return rule.DocURL(cfg.Project.DocsBaseURL)
+}
+
+// RuleGroups are the groups --group accepts
+var RuleGroups = []string{"quality", "security"}
+
+// FilterGroup keeps the issues from builtin rules in group. Custom rules
+// belong to no group, so they are dropped.
+func FilterGroup(issues []Issue, group string) []Issue {
+	var kept []Issue
+	for _, issue := range issues {
+		if rule, ok := LookupRule(issue.Rule); ok && rule.Group == group {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// EffectiveSeverity is what a rule will report as under cfg: its severity,
+// or "off" when the config disables it
+func EffectiveSeverity(id string, cfg *config.Config) string {
+	if !cfg.RuleEnabled(id) {
+		return "off"
+	}
+	return getSeverity(id)
+}
@@ -2,12 +2,23 @@ package checks
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/guardian-sh/guardian/internal/config"
 )
 
 // Pre-compiled regexes for performance (compiled once at package init)
@@ -36,6 +47,65 @@ var (
 	execRe      = regexp.MustCompile(`(?:^|[=(:,\s])exec\s*\(`)
 	starImportRe = regexp.MustCompile(`from\s+\S+\s+import\s+\*`)
 	sqlInjectionRe = regexp.MustCompile(`(?i)f["'](?:SELECT|INSERT|UPDATE|DELETE)`)
+	assertStmtRe   = regexp.MustCompile(`^assert\b`)
+
+	// CORS configured to accept any origin: FastAPI/Starlette, Express cors(),
+	// and the header set directly
+	wildcardCORSRes = []*regexp.Regexp{
+		regexp.MustCompile(`allow_origins\s*=\s*\[\s*["']\*["']\s*\]`),
+		regexp.MustCompile(`\bcors\s*\(\s*\{[^}]*\borigin\s*:\s*["']\*["']`),
+		regexp.MustCompile(`(?i)["']Access-Control-Allow-Origin["']\s*\]?\s*[,:=]\s*["']\*["']`),
+	}
+
+	// Certificate verification switched off: requests/httpx verify=False and
+	// unverified ssl contexts, Node's rejectUnauthorized, Go's InsecureSkipVerify
+	insecureTLSRes = []*regexp.Regexp{
+		regexp.MustCompile(`\bverify\s*=\s*False\b`),
+		regexp.MustCompile(`\bssl\._create_unverified_context\s*\(|\bverify_mode\s*=\s*ssl\.CERT_NONE\b`),
+		regexp.MustCompile(`\brejectUnauthorized["']?\s*:\s*false\b`),
+		regexp.MustCompile(`\bNODE_TLS_REJECT_UNAUTHORIZED["']?\s*\]?\s*=\s*["']?0\b`),
+		regexp.MustCompile(`\bInsecureSkipVerify\s*:\s*true\b`),
+	}
+
+	// Python deserializers that can run code from their input. yaml.load is
+	// only safe with an explicit safe Loader, which is checked separately.
+	unsafeDeserializeRe = regexp.MustCompile(`(?:^|[^\w.])((?:c?[Pp]ickle|dill|marshal)\.loads?|pickle\.Unpickler|yaml\.(?:unsafe_)?load(?:_all)?)\s*\(`)
+	yamlSafeLoaderRe    = regexp.MustCompile(`\bLoader\s*=\s*(?:yaml\.)?(?:C?SafeLoader|BaseLoader)\b`)
+
+	// Comment text that reads like code rather than prose (see looksLikeCommentedCode)
+	commentedCodeRes = []*regexp.Regexp{
+		regexp.MustCompile(`^(?:async\s+)?(?:def|class)\s+\w+.*:$`),
+		regexp.MustCompile(`^(?:if|elif|else|for|while|with|try|except|finally)\b.*:$`),
+		regexp.MustCompile(`^(?:return|raise|yield|await|import)\b\s*\S*`),
+		regexp.MustCompile(`^from\s+[\w.]+\s+import\s`),
+		regexp.MustCompile(`^(?:const|let|var)\s+\w+\s*=`),
+		regexp.MustCompile(`^(?:func|function)\s*\w*\s*\(`),
+		regexp.MustCompile(`^[\w.\[\]'"]+\s*(?:[-+*/%]?=|:=)\s*\S`),
+		regexp.MustCompile(`^[\w.]+\(.*\)$`),
+		regexp.MustCompile(`[;{}]$|^[})\]]`),
+		regexp.MustCompile(`^(?:pass|break|continue)$`),
+	}
+
+	// Go patterns - matched against goCode output, so string contents and comments are gone.
+	// print, panic and _ = err only run on files checkGoAST couldn't parse.
+	goPackageRe    = regexp.MustCompile(`^package\s+(\w+)`)
+	goPrintRe      = regexp.MustCompile(`(?:\bfmt\.Print(?:ln|f)?|(?:^|[^\w.])print(?:ln)?)\s*\(`)
+	goExecRe       = regexp.MustCompile(`\b(?:exec\.Command(?:Context)?|syscall\.Exec)\s*\(`)
+	goShellArgsRe  = regexp.MustCompile(`"(?:/usr)?(?:/bin/)?(?:sh|bash|zsh)"\s*,\s*"-c"|"cmd(?:\.exe)?"\s*,\s*"/[cC]"`)
+	goPanicRe      = regexp.MustCompile(`(?:^|[^\w.])panic\s*\(`)
+	goIgnoredErrRe = regexp.MustCompile(`^_\s*=\s*err\w*$`)
+
+	// HTTP client calls that should carry a timeout
+	pyHTTPCallRe = regexp.MustCompile(`\brequests\.(?:get|post|put|patch|delete|head|options|request)\s*\(`)
+	jsHTTPCallRe = regexp.MustCompile(`(?:^|[^\w.])(?:fetch|axios(?:\.(?:get|post|put|patch|delete|head|request))?)\s*\(`)
+	pyTimeoutRe  = regexp.MustCompile(`\btimeout\s*=`)
+
+	// A function or method named like an HTTP call, which defines it rather
+	// than calling it
+	jsHTTPDefRe = regexp.MustCompile(`\bfunction\s*\*?\s*(?:fetch|axios)\s*\(|^\s*(?:static\s+)?(?:async\s+)?(?:fetch|axios)\s*\([^)]*\)\s*\{`)
+
+	// Absolute home-directory paths inside string literals (POSIX and Windows)
+	hardcodedPathRe = regexp.MustCompile(`["'][^"']*?(?:\B/(?:Users|home)/[\w.-]+|\B/root/|\b[A-Za-z]:[\\/]+Users[\\/])`)
 
 	// Dangerous command patterns
 	dangerousPatternRegexes = []*regexp.Regexp{
@@ -46,15 +116,23 @@ var (
 		regexp.MustCompile(`(?i)TRUNCATE\s+TABLE`),
 	}
 
+	// UPDATE ... SET up to the first ";" on the line, if there is one
+	updateSetRe = regexp.MustCompile("(?i)\\bUPDATE\\s+[\"`\\[]?[\\w.]+[\"`\\]]?\\s+SET\\b([^;]*)(;?)")
+	sqlWhereRe  = regexp.MustCompile(`(?i)\bWHERE\b`)
+
 	// Secret patterns
 	secretPatternRegexes = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)api_key\s*=\s*["'][^"']+["']`),
-		regexp.MustCompile(`(?i)password\s*=\s*["'][^"']+["']`),
-		regexp.MustCompile(`(?i)secret\s*=\s*["'][^"']+["']`),
+		// :?= also covers Go's := and camelCase apiKey
+		regexp.MustCompile(`(?i)api_?key\s*:?=\s*["'][^"']+["']`),
+		regexp.MustCompile(`(?i)password\s*:?=\s*["'][^"']+["']`),
+		regexp.MustCompile(`(?i)secret\s*:?=\s*["'][^"']+["']`),
 		regexp.MustCompile(`(?i)AWS_SECRET`),
 		regexp.MustCompile(`(?i)PRIVATE_KEY`),
 	}
 
+	// Quoted string values, used to score secret-pattern matches
+	quotedValueRe = regexp.MustCompile(`["']([^"']+)["']`)
+
 	// Output parsing regexes
 	issueFormatRe = regexp.MustCompile(`^(.+):(\d+)\s+\[([^\]]+)\]\s+(.+)$`)
 	failFormatRe  = regexp.MustCompile(`^FAIL\s+(.+):(\d+)\s+-\s+([^:]+):\s+(.+)$`)
@@ -72,16 +150,19 @@ var (
 
 // Issue represents a single code issue
 type Issue struct {
-	File     string
-	Line     int
-	Rule     string
-	Message  string
-	Severity string // "critical", "warning", "info"
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Rule       string `json:"rule"`
+	Message    string `json:"message"`
+	Severity   string `json:"severity"`             // "critical", "warning", "info"
+	Confidence string `json:"confidence,omitempty"` // "high", "medium", "low" - how likely the match is real
+	Root       string `json:"root,omitempty"`       // Project root the issue was found under, set by RunRoot
 }
 
 // DryRunInfo contains info about what would be checked
 type DryRunInfo struct {
 	Files      []FileInfo
+	Dirs       []DirInfo // Files rolled up by top-level directory
 	Excluded   []string
 	FileCount  int
 	TotalLines int
@@ -93,42 +174,519 @@ type FileInfo struct {
 	Lines int
 }
 
+// DirInfo totals the files under one top-level directory. Files directly in
+// the checked directory are grouped under ".".
+type DirInfo struct {
+	Path  string
+	Files int
+	Lines int
+}
+
+// Progress is reported to a run's progress callback as files are checked
+type Progress struct {
+	Checked int    // Files checked so far
+	File    string // The file just checked
+}
+
 // RunAll runs all checks in the given directory
 func RunAll(dir string) []Issue {
+	issues, _ := RunAllContext(context.Background(), dir, nil)
+	return issues
+}
+
+// RunAllContext is RunAll that stops early when ctx is cancelled, returning
+// ctx's error, and calls progress (if not nil) after each file is checked.
+// progress is never called concurrently.
+func RunAllContext(ctx context.Context, dir string, progress func(Progress)) ([]Issue, error) {
+	cfg, err := config.Load(dir)
+	if err != nil {
+		// Malformed config - run with defaults rather than skipping checks
+		cfg = config.DefaultConfig()
+	}
+	return RunWithConfigContext(ctx, dir, cfg, progress)
+}
+
+// RunFiles runs the builtin checks on just the given files (e.g. the staged
+// files pre-commit passes as arguments). Paths are relative to dir; files
+// that don't exist, aren't a checked type, or sit in an excluded directory
+// are skipped.
+func RunFiles(dir string, files []string) []Issue {
+	cfg, err := config.Load(dir)
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	return RunFilesWithConfig(dir, files, cfg)
+}
+
+// RunFilesWithConfig is RunFiles with a pre-loaded config
+func RunFilesWithConfig(dir string, files []string, cfg *config.Config) []Issue {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+
+	var issues []Issue
+	overrides := config.NewOverrides(dir, cfg)
+	var strict strictConfigs
+	for _, file := range files {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, file)
+		}
+		if !IsCheckedFile(path, cfg) || inExcludedDir(file) {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		relPath := relativeTo(dir, file)
+		issues = append(issues, checkFileOverridden(path, relPath, strict.For(relPath, overrides.For(relPath)))...)
+	}
+	recordIgnoredOverrides(overrides)
+
+	return finishIssues(issues, cfg)
+}
+
+// IsCheckedFile reports whether the builtin checks handle this file type,
+// including extensions mapped in [project] include_ext
+func IsCheckedFile(path string, cfg *config.Config) bool {
+	return languageFor(path, cfg) != ""
+}
+
+// Languages the builtin checks know rules for
+const (
+	langPython = "python"
+	langJS     = "js"
+	langGo     = "go"
+	langShell  = "shell" // Shell scripts and Dockerfiles - infra rules only
+	langDeps   = "deps"  // requirements*.txt and package.json - dependency rules only
+)
+
+// builtinLanguages maps the file types checked out of the box to their rules
+var builtinLanguages = map[string]string{
+	".py":   langPython,
+	".js":   langJS,
+	".ts":   langJS,
+	".tsx":  langJS,
+	".go":   langGo,
+	".sh":   langShell,
+	".bash": langShell,
+	// Single-file components: JS rules on the <script> blocks (see sfc.go)
+	".vue":    langJS,
+	".svelte": langJS,
+}
+
+// languageAliases are the names accepted for include_ext / --include-ext
+var languageAliases = map[string]string{
+	"python":     langPython,
+	"py":         langPython,
+	"js":         langJS,
+	"javascript": langJS,
+	"ts":         langJS,
+	"typescript": langJS,
+	"go":         langGo,
+	"shell":      langShell,
+	"sh":         langShell,
+	"bash":       langShell,
+}
+
+// languageFor returns which language's rules apply to path, or "" if the
+// file isn't checked. include_ext and [languages.<name>] extensions
+// override the built-in mapping.
+func languageFor(path string, cfg *config.Config) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if cfg != nil {
+		for e, lang := range cfg.Project.IncludeExt {
+			if normalizeExt(e) == ext {
+				return languageAliases[strings.ToLower(lang)]
+			}
+		}
+		for lang, lc := range cfg.Languages {
+			for _, e := range lc.Extensions {
+				if normalizeExt(e) == ext {
+					return lang
+				}
+			}
+		}
+	}
+	if isDockerfile(path) || isCIConfig(path) {
+		return langShell
+	}
+	if isDependencyFile(path) {
+		return langDeps
+	}
+	return builtinLanguages[ext]
+}
+
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// ParseIncludeExt parses a --include-ext value like ".mjs=js,.pyi=python"
+func ParseIncludeExt(s string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		ext, lang, ok := strings.Cut(pair, "=")
+		ext = normalizeExt(ext)
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if !ok || ext == "" || ext == "." {
+			return nil, fmt.Errorf("invalid mapping %q (want .ext=language)", pair)
+		}
+		if languageAliases[lang] == "" {
+			return nil, fmt.Errorf("unknown language %q for %s (use python, js, go or shell)", lang, ext)
+		}
+		mapping[ext] = lang
+	}
+	return mapping, nil
+}
+
+// inExcludedDir reports whether any directory in path is on the shared exclusion list
+func inExcludedDir(path string) bool {
+	segments := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+	for _, seg := range segments[:len(segments)-1] {
+		if excludedDirs[seg] {
+			return true
+		}
+	}
+	return false
+}
+
+// RunRoot checks one root of a multi-root (monorepo) run with that root's own
+// config. Files are reported relative to the working directory rather than
+// the root, and every issue is tagged with the root it came from.
+func RunRoot(root string, cfg *config.Config) []Issue {
+	issues := RunWithConfig(root, cfg)
+	for i := range issues {
+		issues[i].File = filepath.Join(root, issues[i].File)
+		issues[i].Root = root
+	}
+	return issues
+}
+
+// RunWithConfig runs all checks in the given directory using a pre-loaded
+// config, so callers that check repeatedly don't re-read the TOML each time
+func RunWithConfig(dir string, cfg *config.Config) []Issue {
+	issues, _ := RunWithConfigContext(context.Background(), dir, cfg, nil)
+	return issues
+}
+
+// RunWithConfigContext is RunWithConfig with cancellation and progress, as
+// for RunAllContext
+func RunWithConfigContext(ctx context.Context, dir string, cfg *config.Config, progress func(Progress)) ([]Issue, error) {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+
+	issues, err := collectIssues(ctx, dir, cfg, progress)
+	if err != nil {
+		return nil, err
+	}
+	return finishIssues(issues, cfg), nil
+}
+
+// finishIssues is the last step of every run, whichever engine found the
+// issues: drop disabled rules, raise strict paths, dedupe, apply [messages]
+// and sort
+func finishIssues(issues []Issue, cfg *config.Config) []Issue {
+	return SortIssues(applyMessages(dedupeIssues(elevateStrict(dropDisabledRules(issues, cfg), cfg)), cfg))
+}
+
+// applyMessages replaces each issue's message with its rule's [messages]
+// template, if one is configured
+func applyMessages(issues []Issue, cfg *config.Config) []Issue {
+	if len(cfg.Messages) == 0 {
+		return issues
+	}
+	for i := range issues {
+		tmpl := cfg.Messages[issues[i].Rule]
+		if tmpl == "" {
+			continue
+		}
+		issues[i].Message = strings.NewReplacer(
+			"{file}", issues[i].File,
+			"{line}", strconv.Itoa(issues[i].Line),
+			"{rule}", issues[i].Rule,
+			"{message}", issues[i].Message,
+		).Replace(tmpl)
+	}
+	return issues
+}
+
+// dropDisabledRules removes issues for rules listed in rules.disabled,
+// snoozed, or left out of their language's [languages.<name>] rules. The
+// scripts don't know about either, so this runs on every engine's results.
+func dropDisabledRules(issues []Issue, cfg *config.Config) []Issue {
+	if len(cfg.Rules.Disabled) == 0 && len(cfg.Snooze) == 0 && len(cfg.Languages) == 0 {
+		return issues
+	}
+
+	var kept []Issue
+	for _, issue := range issues {
+		if !cfg.IsRuleDisabled(issue.Rule) && languageRuleEnabled(languageFor(issue.File, cfg), issue.Rule, cfg) {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// languageRuleEnabled reports whether rule runs on lang's files: true
+// unless [languages.<lang>] lists rules and neither rule nor its group is
+// among them
+func languageRuleEnabled(lang, rule string, cfg *config.Config) bool {
+	allowed := cfg.Languages[lang].Rules
+	if len(allowed) == 0 {
+		return true
+	}
+	if slices.Contains(allowed, rule) {
+		return true
+	}
+	info, ok := LookupRule(rule)
+	return ok && slices.Contains(allowed, info.Group)
+}
+
+// keepLanguageRules drops the issues lang's rule list leaves out
+func keepLanguageRules(issues []Issue, lang string, cfg *config.Config) []Issue {
+	if len(cfg.Languages[lang].Rules) == 0 {
+		return issues
+	}
+	var kept []Issue
+	for _, issue := range issues {
+		if languageRuleEnabled(lang, issue.Rule, cfg) {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// collectIssues runs guardian.py when present, falling back to the builtin
+// checks if it's missing or fails. The script reports no progress.
+func collectIssues(ctx context.Context, dir string, cfg *config.Config, progress func(Progress)) ([]Issue, error) {
 	var issues []Issue
 
 	// Check if guardian.py exists
 	guardianPath := filepath.Join(dir, ".guardian", "guardian.py")
 	if _, err := os.Stat(guardianPath); os.IsNotExist(err) {
 		// Try running individual checks
-		issues = append(issues, runBuiltinChecks(dir)...)
-		return issues
+		return runBuiltinChecks(ctx, dir, cfg, progress)
 	}
 
 	// Run the guardian.py script
-	cmd := exec.Command("python3", guardianPath)
-	cmd.Dir = dir
-	output, err := cmd.CombinedOutput()
-
+	scriptIssues, err := runGuardianScript(ctx, dir, false)
 	if err != nil {
 		// Python script failed - fall back to builtin checks
 		// This handles: python3 not installed, script errors, etc.
-		issues = append(issues, runBuiltinChecks(dir)...)
-		return issues
+		return runBuiltinChecks(ctx, dir, cfg, progress)
+	}
+	issues = append(issues, scriptIssues...)
+
+	// Scripts only look at source files and don't know custom rules, so walk
+	// for large files and custom rule matches separately
+	issues = append(issues, runCommittedFileChecks(dir, cfg)...)
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+	issues = append(issues, runCustomRuleChecks(dir, cfg)...)
+
+	return issues, ctx.Err()
+}
 
-	// Parse output
-	issues = append(issues, parseGuardianOutput(string(output))...)
+// Engine selects which implementation RunWithEngine uses
+type Engine int
 
-	return issues
+const (
+	EngineAuto    Engine = iota // .guardian/guardian.py if installed, else builtin (what RunAll does)
+	EngineScript                // .guardian/guardian.py only, no builtin fallback
+	EngineBuiltin               // Go-native checks only, even if scripts are installed
+	EngineBoth                  // .guardian/guardian.py and the builtin checks, merged
+)
+
+// RunWithEngine runs checks with a specific engine, so the script and builtin
+// implementations can be compared. Unlike RunAll, a script failure is returned
+// as an error instead of falling back.
+func RunWithEngine(dir string, cfg *config.Config, engine Engine) ([]Issue, error) {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+
+	var issues []Issue
+	switch engine {
+	case EngineScript:
+		scriptIssues, err := runGuardianScript(context.Background(), dir, true)
+		if err != nil {
+			return nil, err
+		}
+		issues = scriptIssues
+	case EngineBuiltin:
+		issues, _ = runBuiltinChecks(context.Background(), dir, cfg, nil)
+	case EngineBoth:
+		scriptIssues, err := runGuardianScript(context.Background(), dir, true)
+		if err != nil {
+			return nil, err
+		}
+		builtinIssues, _ := runBuiltinChecks(context.Background(), dir, cfg, nil)
+		issues = mergeEngineIssues(builtinIssues, scriptIssues)
+	default:
+		issues, _ = collectIssues(context.Background(), dir, cfg, nil)
+	}
+
+	return finishIssues(issues, cfg), nil
 }
 
-// runBuiltinChecks runs checks without external scripts
-func runBuiltinChecks(dir string) []Issue {
+// runGuardianScript runs .guardian/guardian.py and parses its output. The
+// scripts exit 1 when they find issues; with allowIssueExit that exit status
+// is accepted instead of being treated as a failure. Cancelling ctx kills
+// the script.
+func runGuardianScript(ctx context.Context, dir string, allowIssueExit bool) ([]Issue, error) {
+	guardianPath := filepath.Join(dir, ".guardian", "guardian.py")
+	if _, err := os.Stat(guardianPath); os.IsNotExist(err) {
+		return nil, errors.New(".guardian/guardian.py is not installed (run guardian add python)")
+	} else if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, "python3", guardianPath)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !allowIssueExit || !ok || exitErr.ExitCode() != 1 {
+			return nil, fmt.Errorf("guardian.py failed: %w", err)
+		}
+	}
+
+	// Parse output - scripts may print absolute or ./-prefixed paths
 	var issues []Issue
+	for _, issue := range parseGuardianOutput(string(output)) {
+		issue.File = relativeTo(dir, issue.File)
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// mergeEngineIssues adds the script's issues to the builtin ones. The two
+// engines word their messages differently, so an issue both found is
+// matched on file, line and rule, and the builtin copy is kept.
+func mergeEngineIssues(builtin, script []Issue) []Issue {
+	type issueKey struct {
+		file string
+		line int
+		rule string
+	}
+
+	unmatched := make(map[issueKey]int, len(builtin))
+	for _, issue := range builtin {
+		unmatched[issueKey{issue.File, issue.Line, issue.Rule}]++
+	}
+
+	merged := append([]Issue(nil), builtin...)
+	for _, issue := range script {
+		k := issueKey{issue.File, issue.Line, issue.Rule}
+		if unmatched[k] > 0 {
+			unmatched[k]--
+			continue
+		}
+		merged = append(merged, issue)
+	}
+	return merged
+}
+
+// dedupeIssues drops repeats of the same (File, Line, Rule, Message),
+// keeping the first occurrence and the original order
+func dedupeIssues(issues []Issue) []Issue {
+	type issueKey struct {
+		file    string
+		line    int
+		rule    string
+		message string
+	}
+
+	seen := make(map[issueKey]bool, len(issues))
+	unique := make([]Issue, 0, len(issues))
+	for _, issue := range issues {
+		k := issueKey{issue.File, issue.Line, issue.Rule, issue.Message}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		unique = append(unique, issue)
+	}
+	return unique
+}
+
+// SortIssues orders issues by file, then line, then rule, so output is the
+// same from run to run whatever order the files were checked in. It sorts in
+// place and returns the slice for chaining.
+func SortIssues(issues []Issue) []Issue {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Rule < b.Rule
+	})
+	return issues
+}
+
+// relativeTo returns file relative to root when possible, in slash form so
+// CI logs look the same on every platform
+func relativeTo(root, file string) string {
+	if filepath.IsAbs(file) {
+		if absRoot, err := filepath.Abs(root); err == nil {
+			if rel, err := filepath.Rel(absRoot, file); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(file))
+}
+
+// runBuiltinChecks runs checks without external scripts. Files are checked
+// in parallel (limits.concurrency at a time), and issues are returned in
+// walk order whatever order the checks finish in. Once ctx is cancelled no
+// new files are started, and ctx's error is returned after the files already
+// being checked finish.
+func runBuiltinChecks(ctx context.Context, dir string, cfg *config.Config, progress func(Progress)) ([]Issue, error) {
+	// One result per file; each worker only writes its own
+	var perFile []*[]Issue
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, checkConcurrency(cfg))
+	overrides := config.NewOverrides(dir, cfg)
+	var strict strictConfigs
+
+	// Workers report one at a time, with a running count
+	var progressMu sync.Mutex
+	checked := 0
+	report := func(relPath string) {
+		if progress == nil {
+			return
+		}
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		checked++
+		progress(Progress{Checked: checked, File: relPath})
+	}
+
+	// Symlinks that aren't followed are listed with the skipped files
+	skippedLink := func(path, reason string) {
+		relPath, _ := filepath.Rel(dir, path)
+		recordFileError(FileError{File: filepath.ToSlash(relPath), Err: reason})
+	}
 
 	// Walk directory
-	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	walkErr := walkSource(dir, cfg, skippedLink, func(path string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil {
 			return nil
 		}
@@ -141,55 +699,393 @@ func runBuiltinChecks(dir string) []Issue {
 			return nil
 		}
 
-		// Only check Python and JS/TS files
-		ext := filepath.Ext(path)
-		if ext != ".py" && ext != ".js" && ext != ".ts" && ext != ".tsx" {
+		// Report paths relative to the scan root (same as DryRun)
+		relPath, _ := filepath.Rel(dir, path)
+		relPath = filepath.ToSlash(relPath)
+
+		// Large files and binaries, whatever their type
+		fileCfg := strict.For(relPath, overrides.For(relPath))
+		result := dropDisabledRules(checkCommittedFile(path, relPath, info, fileCfg), fileCfg)
+		perFile = append(perFile, &result)
+
+		// Only check Python, JS/TS and Go files (plus any include_ext mappings)
+		if !IsCheckedFile(path, cfg) {
 			return nil
 		}
 
-		// Run checks on file
-		fileIssues := checkFile(path)
-		issues = append(issues, fileIssues...)
+		// Run checks on file, once a worker is free
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result = append(result, checkFileOverridden(path, relPath, fileCfg)...)
+			report(relPath)
+		}()
 
 		return nil
 	})
+	wg.Wait()
+	recordIgnoredOverrides(overrides)
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	var issues []Issue
+	for _, result := range perFile {
+		issues = append(issues, *result...)
+	}
+	return issues, nil
+}
+
+// checkFileOverridden checks one file with the config that applies to it,
+// .guardian.toml overrides included, and drops the rules that config turns
+// off. The run's own config is still applied to every issue afterwards.
+func checkFileOverridden(path, relPath string, fileCfg *config.Config) []Issue {
+	return dropDisabledRules(checkFileRecovered(path, relPath, fileCfg), fileCfg)
+}
+
+// recordIgnoredOverrides reports the .guardian.toml files that were skipped
+// for being invalid; their subtrees were checked with the parent's config
+func recordIgnoredOverrides(overrides *config.Overrides) {
+	for _, ignored := range overrides.Ignored() {
+		recordFileError(FileError{File: ignored.Path, Err: "invalid override: " + ignored.Err.Error()})
+	}
+}
+
+// checkConcurrency is how many files runBuiltinChecks checks at once
+func checkConcurrency(cfg *config.Config) int {
+	if cfg.Limits.Concurrency > 0 {
+		return cfg.Limits.Concurrency
+	}
+	return runtime.NumCPU()
+}
+
+// runCommittedFileChecks walks dir for large files and binaries only
+func runCommittedFileChecks(dir string, cfg *config.Config) []Issue {
+	var issues []Issue
+
+	walkSource(dir, cfg, nil, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if excludedDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(dir, path)
+		issues = append(issues, checkCommittedFile(path, filepath.ToSlash(relPath), info, cfg)...)
+		return nil
+	})
 
 	return issues
 }
 
-// checkFile runs builtin checks on a single file
+// binaryAssetExtensions are binary types that normally belong in a repo
+var binaryAssetExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".ico": true, ".webp": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+	".pdf": true,
+}
+
+// binarySniffBytes is how much of a file is read to decide if it's binary
+const binarySniffBytes = 8000
+
+// checkCommittedFile flags files that shouldn't be in the tree at all: anything
+// over max_file_bytes, and binaries (model weights, databases, archives) that
+// aren't ordinary assets like images or fonts
+func checkCommittedFile(path, relPath string, info os.FileInfo, cfg *config.Config) []Issue {
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	maxBytes := cfg.Limits.MaxFileBytes
+	if maxBytes > 0 && info.Size() > maxBytes {
+		return []Issue{{
+			File:     relPath,
+			Line:     1,
+			Rule:     "large-file",
+			Message:  "File is " + formatBytes(info.Size()) + " (max " + formatBytes(maxBytes) + ") - keep large files out of git",
+			Severity: "warning",
+		}}
+	}
+
+	if binaryAssetExtensions[strings.ToLower(filepath.Ext(path))] || !isBinaryFile(path) {
+		return nil
+	}
+	return []Issue{{
+		File:     relPath,
+		Line:     1,
+		Rule:     "large-file",
+		Message:  "Binary file in the source tree - keep build output and data out of git",
+		Severity: "warning",
+	}}
+}
+
+// isBinaryFile reports whether the start of the file contains a NUL byte,
+// the same heuristic git uses
+func isBinaryFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	buf := make([]byte, binarySniffBytes)
+	n, _ := f.Read(buf)
+	return bytes.IndexByte(buf[:n], 0) >= 0
+}
+
+// streamThresholdBytes is the size above which source is read line by line
+// instead of in one piece, so a big file isn't held in memory twice
+const streamThresholdBytes = 1024 * 1024
+
+// maxLineBytes is the longest line read when streaming; minified bundles can
+// be one very long line
+const maxLineBytes = 4 * 1024 * 1024
+
+// readSourceLines reads a file split on "\n", the same as strings.Split on
+// its contents, decoded by decodeSource. For single-file components only
+// the <script> blocks are kept. Files over max_scan_bytes, or with a line too long to read,
+// are recorded as skipped and return nil.
+func readSourceLines(path, relPath string, cfg *config.Config) []string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	if maxBytes := cfg.Limits.MaxScanBytes; maxBytes > 0 && info.Size() > maxBytes {
+		recordFileError(FileError{
+			File: relPath,
+			Err:  "too large to scan (" + formatBytes(info.Size()) + ", max_scan_bytes is " + formatBytes(maxBytes) + ")",
+		})
+		return nil
+	}
+
+	if info.Size() <= streamThresholdBytes {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		lines := strings.Split(decodeSource(string(content), cfg), "\n")
+		if isSFC(path) {
+			return sfcScriptLines(lines)
+		}
+		return lines
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	// Split on "\n" only, keeping any "\r", and remember whether the last
+	// line was terminated so a trailing "" can be added like Split does
+	endsWithNewline := false
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineBytes)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			endsWithNewline = true
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			endsWithNewline = false
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, decodeSource(scanner.Text(), cfg))
+	}
+	if err := scanner.Err(); err != nil {
+		recordFileError(FileError{File: relPath, Err: "could not read: " + err.Error()})
+		return nil
+	}
+	if endsWithNewline || len(lines) == 0 {
+		lines = append(lines, "")
+	}
+	if isSFC(path) {
+		return sfcScriptLines(lines)
+	}
+	return lines
+}
+
+// formatBytes renders a size like "5.0 MB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return strconv.FormatFloat(float64(n)/float64(div), 'f', 1, 64) + " " + string("KMGT"[exp]) + "B"
+}
+
+// checkFile runs builtin checks on a single file using the default config
 func checkFile(path string) []Issue {
+	return checkFileWithConfig(path, path, config.DefaultConfig())
+}
+
+// checkFileWithConfig runs builtin checks on a single file, honouring the
+// rule toggles and limits in cfg. relPath is what gets reported in Issue.File
+// and is used for path-based config matching.
+func checkFileWithConfig(path, relPath string, cfg *config.Config) []Issue {
 	var issues []Issue
 
-	content, err := os.ReadFile(path)
-	if err != nil {
+	lines := readSourceLines(path, relPath, cfg)
+	if lines == nil {
 		return issues
 	}
-
-	lines := strings.Split(string(content), "\n")
 	// Fix off-by-one: if file ends with newline, Split adds empty element
 	// A 500-line file with trailing newline has 501 elements but is still 500 lines
 	lineCount := len(lines)
 	if lineCount > 0 && lines[lineCount-1] == "" {
 		lineCount--
 	}
-	relPath := path
+
+	// Manifests aren't code; only the dependency rules apply
+	lang := languageFor(path, cfg)
+	if lang == langDeps {
+		issues = checkDependencyFile(relPath, lines, cfg)
+		for i := range issues {
+			issues[i].Confidence = getConfidence(issues[i].Rule)
+		}
+		return issues
+	}
 
 	// File size check
-	if lineCount > 500 {
+	maxLines := maxFileLines(relPath, cfg)
+	if maxLines > 0 && lineCount > maxLines {
 		issues = append(issues, Issue{
 			File:     relPath,
 			Line:     1,
 			Rule:     "file-size",
-			Message:  "File has " + strconv.Itoa(lineCount) + " lines (max 500)",
+			Message:  "File has " + strconv.Itoa(lineCount) + " lines (max " + strconv.Itoa(maxLines) + ")",
 			Severity: "warning",
 		})
 	}
 
+	if lang == langShell {
+		issues = append(issues, checkShellFile(relPath, lines, cfg)...)
+		issues = append(issues, checkCustomRules(relPath, lang, lines, cfg)...)
+		for i := range issues {
+			if issues[i].Confidence == "" {
+				issues[i].Confidence = getConfidence(issues[i].Rule)
+			}
+		}
+		return applyFileHeader(keepLanguageRules(issues, lang, cfg), lines)
+	}
+	isJS := lang == langJS
+	isTest := isTestFile(relPath)
+	isGo := lang == langGo
+
+	// Go: CLI output and panics are fine in package main, not in libraries
+	isGoMain := false
+	if isGo {
+		for _, l := range lines {
+			if m := goPackageRe.FindStringSubmatch(strings.TrimSpace(l)); m != nil {
+				isGoMain = m[1] == "main"
+				break
+			}
+		}
+	}
+	goState := goStateCode
+
+	// Go: prints, panics and ignored errors come from the syntax tree when
+	// the file parses; the regexes below are the fallback
+	goParsed := false
+	if isGo {
+		var astIssues []Issue
+		astIssues, goParsed = checkGoAST(relPath, lines, cfg, isTest)
+		issues = append(issues, astIssues...)
+	}
+
+	// Calls exempt from ban-eval, matched as whole call expressions
+	evalAllowRe := evalAllowlistRe(cfg.Security.EvalAllowlist)
+
+	// Personal data field names for pii-logging, split into words
+	var piiFields [][]string
+	if cfg.Security.BanPIILogging {
+		piiFields = splitFieldWords(cfg.Security.PIIFields)
+	}
+
+	// Blocking calls inside async functions
+	var asyncFns *asyncTracker
+	var blockingRe *regexp.Regexp
+	if cfg.Quality.BanBlockingInAsync {
+		asyncFns = newAsyncTracker(lang)
+		blockingRe = blockingCallRe(cfg.Quality.BlockingCalls)
+	}
+
+	// Calls to this file's async functions whose result is dropped. In JS
+	// only calls inside async functions count: a bare main() at the top
+	// level is the usual way to start a script.
+	var asyncNames map[string]bool
+	var awaitScope *asyncTracker
+	if cfg.Quality.BanUnawaitedAsync {
+		asyncNames = asyncFunctionNames(lines, lang)
+		if isJS && asyncNames != nil {
+			awaitScope = newAsyncTracker(lang)
+		}
+	}
+
+	// Route handlers with no auth decorator or dependency
+	var routes *routeTracker
+	if cfg.Security.BanUnprotectedRoutes {
+		routes = newRouteTracker(lang, cfg.Security.AuthMarkers)
+	}
+
+	// Statements left after a return, raise or throw
+	var exits *unreachableTracker
+	if cfg.Quality.BanUnreachableCode {
+		exits = newUnreachableTracker(lang)
+	}
+
+	// try/except and try/catch bodies, classified as each one ends
+	var handlers *exceptionTracker
+	if cfg.Quality.BanLogAndIgnore {
+		handlers = newExceptionTracker(lang, relPath)
+	}
+
+	// Runs of consecutive comment lines that look like code
+	codeRunStart, codeRunLen := 0, 0
+	flushCodeRun := func() {
+		if cfg.Quality.BanCommentedCode && codeRunLen >= cfg.Quality.CommentedCodeMinLines && codeRunLen > 0 {
+			issues = append(issues, Issue{
+				File:     relPath,
+				Line:     codeRunStart,
+				Rule:     "commented-code",
+				Message:  strconv.Itoa(codeRunLen) + " lines of commented-out code - delete it, git remembers",
+				Severity: "info",
+			})
+		}
+		codeRunLen = 0
+	}
+
 	// Track docstring state for multi-line strings
 	inDocstring := false
 	docstringDelim := ""
 
+	// Comments, apart from strings, for the rules that only look in them
+	var scopes []lineScopes
+	if cfg.Quality.BanTodoMarkers {
+		scopes = splitScopes(lines, lang)
+	}
+
 	// Line-by-line checks
 	for i, line := range lines {
 		lineNum := i + 1
@@ -197,11 +1093,21 @@ func checkFile(path string) []Issue {
 
 		// Skip empty lines
 		if trimmed == "" {
+			flushCodeRun()
 			continue
 		}
 
+		// Go: blank out strings and comments, tracking raw strings and
+		// block comments that span lines
+		code := ""
+		if isGo {
+			code, goState = goCode(line, goState)
+		}
+
 		// Track multi-line docstrings (Python)
-		if !inDocstring {
+		if isGo {
+			// No docstrings in Go
+		} else if !inDocstring {
 			if strings.HasPrefix(trimmed, `"""`) || strings.HasPrefix(trimmed, `'''`) {
 				docstringDelim = trimmed[:3]
 				// Check if docstring ends on same line
@@ -221,24 +1127,96 @@ func checkFile(path string) []Issue {
 
 		// Skip comment lines (Python #, JS/TS //)
 		isComment := strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//")
+		if isGo {
+			isComment = strings.TrimSpace(code) == ""
+		}
 
-		// Mock data patterns (using pre-compiled regexes)
-		lowerLine := strings.ToLower(line)
-		for _, re := range mockPatternRegexes {
-			if re.MatchString(lowerLine) {
+		// Commented-out code: count consecutive code-like comment lines; any
+		// prose or real code line ends the run
+		if isComment && looksLikeCommentedCode(trimmed, lang) {
+			if codeRunLen == 0 {
+				codeRunStart = lineNum
+			}
+			codeRunLen++
+		} else {
+			flushCodeRun()
+		}
+
+		if handlers != nil && !isComment {
+			handlers.feed(lineNum, line)
+		}
+
+		if asyncFns != nil && !isComment && asyncFns.feed(line) && blockingRe != nil {
+			if m := blockingRe.FindStringSubmatch(line); m != nil {
 				issues = append(issues, Issue{
 					File:     relPath,
 					Line:     lineNum,
-					Rule:     "mock-data",
-					Message:  "Possible test/mock data detected",
+					Rule:     "blocking-in-async",
+					Message:  m[1] + "() blocks the event loop in an async function - use an async equivalent or run it in a thread",
 					Severity: "warning",
 				})
-				break
+			}
+		}
+
+		if asyncNames != nil && !isComment {
+			canAwait := true
+			if awaitScope != nil {
+				canAwait = awaitScope.feed(line)
+			}
+			if name := unawaitedCall(trimmed, asyncNames); name != "" && canAwait {
+				issues = append(issues, Issue{
+					File:     relPath,
+					Line:     lineNum,
+					Rule:     "unawaited-async",
+					Message:  name + "() is async but isn't awaited - it may not run, and its errors are lost",
+					Severity: "warning",
+				})
+			}
+		}
+
+		if exits != nil && !isComment {
+			if exit, exitLine := exits.feed(lineNum, line); exit != "" {
+				issues = append(issues, Issue{
+					File:     relPath,
+					Line:     lineNum,
+					Rule:     "unreachable-code",
+					Message:  "Unreachable: this follows the " + exit + " on line " + strconv.Itoa(exitLine) + " - delete it or move it above",
+					Severity: "info",
+				})
+			}
+		}
+
+		if routes != nil && !isComment {
+			if route := routes.feed(lines, i); route != nil {
+				issues = append(issues, Issue{
+					File:     relPath,
+					Line:     route.line,
+					Rule:     "unprotected-route",
+					Message:  route.name + "() is a route with no auth decorator or dependency - check it's meant to be public",
+					Severity: "info",
+				})
+			}
+		}
+
+		// Mock data patterns (using pre-compiled regexes)
+		lowerLine := strings.ToLower(line)
+		if cfg.Quality.BanMockData {
+			for _, re := range mockPatternRegexes {
+				if re.MatchString(lowerLine) {
+					issues = append(issues, Issue{
+						File:     relPath,
+						Line:     lineNum,
+						Rule:     "mock-data",
+						Message:  "Possible test/mock data detected",
+						Severity: "warning",
+					})
+					break
+				}
 			}
 		}
 
 		// Print statements (Python) - use word boundary to avoid "blueprint", "fingerprint"
-		if !isComment && printRe.MatchString(line) {
+		if cfg.Quality.BanPrint && !isComment && !isGo && printRe.MatchString(line) {
 			issues = append(issues, Issue{
 				File:     relPath,
 				Line:     lineNum,
@@ -249,7 +1227,7 @@ func checkFile(path string) []Issue {
 		}
 
 		// Console.log (JS/TS)
-		if !isComment && strings.Contains(line, "console.log(") {
+		if cfg.Quality.BanPrint && !isComment && !isGo && strings.Contains(line, "console.log(") {
 			issues = append(issues, Issue{
 				File:     relPath,
 				Line:     lineNum,
@@ -260,7 +1238,7 @@ func checkFile(path string) []Issue {
 		}
 
 		// Bare except (Python)
-		if !isComment && bareExceptRe.MatchString(line) {
+		if cfg.Quality.BanBareExcept && !isComment && bareExceptRe.MatchString(line) {
 			issues = append(issues, Issue{
 				File:     relPath,
 				Line:     lineNum,
@@ -271,12 +1249,19 @@ func checkFile(path string) []Issue {
 		}
 
 		// eval/exec - only flag actual function calls, not strings/comments
-		if !isComment {
+		if cfg.Security.BanEvalExec && !isComment && !isGo {
+			// Blank out allowlisted safe calls first (same length, so quote counting still works)
+			callLine := line
+			if evalAllowRe != nil {
+				callLine = evalAllowRe.ReplaceAllStringFunc(callLine, blankCall)
+			}
+			callTrimmed := strings.TrimSpace(callLine)
+
 			// Only match if eval/exec is preceded by = ( , : or start of line
 			// This avoids matching "eval(" inside strings like "don't use eval()"
-			if evalRe.MatchString(trimmed) {
+			if evalRe.MatchString(callTrimmed) {
 				// Check if inside a string by counting unescaped quotes before eval
-				beforeEval := strings.Split(line, "eval")[0]
+				beforeEval := strings.Split(callLine, "eval")[0]
 				// Count only unescaped quotes by removing escaped ones first
 				cleaned := strings.ReplaceAll(beforeEval, `\"`, "")
 				cleaned = strings.ReplaceAll(cleaned, `\'`, "")
@@ -287,78 +1272,211 @@ func checkFile(path string) []Issue {
 					issues = append(issues, Issue{
 						File:     relPath,
 						Line:     lineNum,
-						Rule:     "ban-eval",
-						Message:  "Avoid eval() - security risk",
-						Severity: "critical",
+						Rule:     "ban-eval",
+						Message:  "Avoid eval() - security risk",
+						Severity: "critical",
+					})
+				}
+			}
+			if execRe.MatchString(callTrimmed) {
+				beforeExec := strings.Split(callLine, "exec")[0]
+				cleaned := strings.ReplaceAll(beforeExec, `\"`, "")
+				cleaned = strings.ReplaceAll(cleaned, `\'`, "")
+				doubleQuotes := strings.Count(cleaned, `"`)
+				singleQuotes := strings.Count(cleaned, `'`)
+				if doubleQuotes%2 == 0 && singleQuotes%2 == 0 {
+					issues = append(issues, Issue{
+						File:     relPath,
+						Line:     lineNum,
+						Rule:     "ban-eval",
+						Message:  "Avoid exec() - security risk",
+						Severity: "critical",
+					})
+				}
+			}
+		}
+
+		// Star imports
+		if cfg.Quality.BanStarImports && !isComment && starImportRe.MatchString(line) {
+			issues = append(issues, Issue{
+				File:     relPath,
+				Line:     lineNum,
+				Rule:     "ban-star",
+				Message:  "Avoid wildcard imports - import specific names",
+				Severity: "warning",
+			})
+		}
+
+		// TODO/FIXME markers, only in comments - not strings, URLs or names
+		// like todos
+		if cfg.Quality.BanTodoMarkers && todoMarkerRe.MatchString(scopes[i].comment) {
+			issues = append(issues, Issue{
+				File:     relPath,
+				Line:     lineNum,
+				Rule:     "todo-marker",
+				Message:  "Resolve TODO/FIXME before committing",
+				Severity: "info",
+			})
+		}
+
+		// Dangerous commands (using pre-compiled regexes)
+		if cfg.Security.BanDangerousCommands && !isComment {
+			for _, re := range dangerousPatternRegexes {
+				if re.MatchString(line) {
+					issues = append(issues, Issue{
+						File:     relPath,
+						Line:     lineNum,
+						Rule:     "dangerous-cmd",
+						Message:  "Dangerous command detected - review carefully",
+						Severity: "critical",
+					})
+					break
+				}
+			}
+			if updateWithoutWhere(line) {
+				issues = append(issues, Issue{
+					File:     relPath,
+					Line:     lineNum,
+					Rule:     "dangerous-cmd",
+					Message:  "UPDATE without WHERE changes every row - review carefully",
+					Severity: "critical",
+				})
+			}
+		}
+
+		// Known provider tokens - precise enough to flag anywhere, even in comments
+		if provider, ok := MatchProviderSecret(line); ok {
+			issues = append(issues, Issue{
+				File:       relPath,
+				Line:       lineNum,
+				Rule:       "secret-pattern",
+				Message:    provider + " found - revoke it and load from environment variables",
+				Severity:   "critical",
+				Confidence: "high",
+			})
+		} else if !isComment {
+			// Generic name = "value" secret patterns (using pre-compiled regexes)
+			for _, re := range secretPatternRegexes {
+				if re.MatchString(line) {
+					issues = append(issues, Issue{
+						File:       relPath,
+						Line:       lineNum,
+						Rule:       "secret-pattern",
+						Message:    "Possible hardcoded secret - use environment variables",
+						Severity:   "critical",
+						Confidence: secretConfidence(line),
+					})
+					break
+				}
+			}
+		}
+
+		// SQL injection (f-strings in queries) - case insensitive
+		if !isComment && sqlInjectionRe.MatchString(line) {
+			issues = append(issues, Issue{
+				File:     relPath,
+				Line:     lineNum,
+				Rule:     "sql-injection",
+				Message:  "f-string in SQL query - use parameterized queries",
+				Severity: "critical",
+			})
+		}
+
+		// HTTP calls without a timeout - the kwargs may span several lines
+		if cfg.Quality.RequireTimeouts && !isComment {
+			if loc := pyHTTPCallRe.FindStringIndex(line); loc != nil {
+				if !pyTimeoutRe.MatchString(callText(lines, i, loc[0])) {
+					issues = append(issues, Issue{
+						File:     relPath,
+						Line:     lineNum,
+						Rule:     "no-timeout",
+						Message:  "HTTP call without timeout= - can hang forever",
+						Severity: "info",
+					})
+				}
+			} else if loc := jsHTTPCallRe.FindStringIndex(line); loc != nil && isJS && !jsHTTPDefRe.MatchString(line) {
+				call := callText(lines, i, loc[0])
+				if !strings.Contains(call, "timeout") && !strings.Contains(call, "signal") {
+					issues = append(issues, Issue{
+						File:     relPath,
+						Line:     lineNum,
+						Rule:     "no-timeout",
+						Message:  "HTTP call without timeout or AbortSignal - can hang forever",
+						Severity: "info",
 					})
 				}
 			}
-			if execRe.MatchString(trimmed) {
-				beforeExec := strings.Split(line, "exec")[0]
-				cleaned := strings.ReplaceAll(beforeExec, `\"`, "")
-				cleaned = strings.ReplaceAll(cleaned, `\'`, "")
-				doubleQuotes := strings.Count(cleaned, `"`)
-				singleQuotes := strings.Count(cleaned, `'`)
-				if doubleQuotes%2 == 0 && singleQuotes%2 == 0 {
+		}
+
+		// Logging calls that include personal data. In Go the call must also
+		// be there with strings blanked, so a format string can't look like one.
+		if len(piiFields) > 0 && !isComment && (!isGo || logCallRe.MatchString(code)) {
+			if loc := logCallRe.FindStringIndex(line); loc != nil {
+				if field, ok := loggedPIIField(callText(lines, i, loc[0]), piiFields); ok {
 					issues = append(issues, Issue{
 						File:     relPath,
 						Line:     lineNum,
-						Rule:     "ban-eval",
-						Message:  "Avoid exec() - security risk",
-						Severity: "critical",
+						Rule:     "pii-logging",
+						Message:  "Logs " + field + " - mask or drop personal data before logging",
+						Severity: "warning",
 					})
 				}
 			}
 		}
 
-		// Star imports
-		if !isComment && starImportRe.MatchString(line) {
+		// Hardcoded absolute home-directory paths
+		if cfg.Quality.BanHardcodedPaths && !isComment && hardcodedPathRe.MatchString(line) {
 			issues = append(issues, Issue{
 				File:     relPath,
 				Line:     lineNum,
-				Rule:     "ban-star",
-				Message:  "Avoid wildcard imports - import specific names",
-				Severity: "warning",
+				Rule:     "hardcoded-path",
+				Message:  "Hardcoded absolute path - won't work on other machines",
+				Severity: "info",
 			})
 		}
 
-		// TODO/FIXME markers
-		upperLine := strings.ToUpper(line)
-		if strings.Contains(upperLine, "TODO") || strings.Contains(upperLine, "FIXME") || strings.Contains(upperLine, "HACK") {
+		// assert as a runtime check (Python) - removed entirely under python -O.
+		// Matching on the trimmed line start skips comments and string literals.
+		if cfg.Security.BanAssertValidation && lang == langPython && !isTest && assertStmtRe.MatchString(trimmed) {
 			issues = append(issues, Issue{
 				File:     relPath,
 				Line:     lineNum,
-				Rule:     "todo-marker",
-				Message:  "Resolve TODO/FIXME before committing",
-				Severity: "info",
+				Rule:     "assert-validation",
+				Message:  "assert is stripped under python -O - raise an exception instead",
+				Severity: "warning",
 			})
 		}
 
-		// Dangerous commands (using pre-compiled regexes)
-		if !isComment {
-			for _, re := range dangerousPatternRegexes {
+		// Wildcard CORS - matched on the raw line since the "*" is a string literal
+		if cfg.Security.BanWildcardCORS && !isComment {
+			for _, re := range wildcardCORSRes {
 				if re.MatchString(line) {
 					issues = append(issues, Issue{
 						File:     relPath,
 						Line:     lineNum,
-						Rule:     "dangerous-cmd",
-						Message:  "Dangerous command detected - review carefully",
-						Severity: "critical",
+						Rule:     "insecure-cors",
+						Message:  "CORS allows any origin (*) - list the allowed origins instead",
+						Severity: "warning",
 					})
 					break
 				}
 			}
 		}
 
-		// Secret patterns (using pre-compiled regexes)
-		if !isComment {
-			for _, re := range secretPatternRegexes {
-				if re.MatchString(line) {
+		// Disabled TLS verification. Go is matched with strings and comments
+		// blanked; elsewhere "0" in NODE_TLS_REJECT_UNAUTHORIZED is a string
+		if cfg.Security.BanInsecureTLS && !isComment {
+			tlsLine := line
+			if isGo {
+				tlsLine = code
+			}
+			for _, re := range insecureTLSRes {
+				if re.MatchString(tlsLine) {
 					issues = append(issues, Issue{
 						File:     relPath,
 						Line:     lineNum,
-						Rule:     "secret-pattern",
-						Message:  "Possible hardcoded secret - use environment variables",
+						Rule:     "insecure-tls",
+						Message:  "TLS certificate verification is disabled - traffic can be intercepted",
 						Severity: "critical",
 					})
 					break
@@ -366,19 +1484,98 @@ func checkFile(path string) []Issue {
 			}
 		}
 
-		// SQL injection (f-strings in queries) - case insensitive
-		if !isComment && sqlInjectionRe.MatchString(line) {
-			issues = append(issues, Issue{
-				File:     relPath,
-				Line:     lineNum,
-				Rule:     "sql-injection",
-				Message:  "f-string in SQL query - use parameterized queries",
-				Severity: "critical",
-			})
+		// pickle, marshal and yaml.load without a safe Loader run code
+		// hidden in the data they load
+		if cfg.Security.BanInsecureDeserialization && lang == langPython && !isComment {
+			if m := unsafeDeserializeRe.FindStringSubmatchIndex(line); m != nil {
+				call := line[m[2]:m[3]]
+				safeYAML := strings.HasPrefix(call, "yaml.load") && yamlSafeLoaderRe.MatchString(callText(lines, i, m[2]))
+				if !safeYAML {
+					message := call + "() can run arbitrary code from its input - only load data you trust"
+					if strings.HasPrefix(call, "yaml.") {
+						message = call + "() without SafeLoader can run arbitrary code - use yaml.safe_load()"
+					}
+					issues = append(issues, Issue{
+						File:     relPath,
+						Line:     lineNum,
+						Rule:     "insecure-deserialization",
+						Message:  message,
+						Severity: "critical",
+					})
+				}
+			}
+		}
+
+		// chmod to a mode looser than max_chmod_mode (0o755 by default)
+		if cfg.Security.BanPermissiveChmod && !isComment {
+			search := line
+			if isGo {
+				search = code
+			}
+			if loc := chmodCallRe.FindStringIndex(search); loc != nil {
+				if mode, ok := permissiveChmod(callText(lines, i, loc[0]), cfg.Security.MaxChmodMode); ok {
+					issues = append(issues, Issue{
+						File:     relPath,
+						Line:     lineNum,
+						Rule:     "permissive-chmod",
+						Message:  "chmod to " + describeMode(mode) + " - grant only the access it needs",
+						Severity: "warning",
+					})
+				}
+			}
+		}
+
+		if isGo && !isComment {
+			trimmedCode := strings.TrimSpace(code)
+
+			// fmt.Print*/println debug output outside package main
+			if cfg.Quality.BanPrint && !goParsed && !isGoMain && goPrintRe.MatchString(code) {
+				issues = append(issues, Issue{
+					File:     relPath,
+					Line:     lineNum,
+					Rule:     "ban-print",
+					Message:  "Remove fmt.Println() - use a logger",
+					Severity: "info",
+				})
+			}
+
+			// exec.Command("sh", "-c", ...) runs a shell string. The call must be
+			// real code; the shell arguments are read from the original line.
+			if cfg.Security.BanDangerousCommands && goExecRe.MatchString(code) && goShellArgsRe.MatchString(line) {
+				issues = append(issues, Issue{
+					File:     relPath,
+					Line:     lineNum,
+					Rule:     "dangerous-cmd",
+					Message:  "Shell command string via exec - pass the program and args directly",
+					Severity: "critical",
+				})
+			}
+
+			// panic in library code - return an error instead
+			if cfg.Quality.BanLibraryPanic && !goParsed && !isGoMain && !isTest && goPanicRe.MatchString(code) {
+				issues = append(issues, Issue{
+					File:     relPath,
+					Line:     lineNum,
+					Rule:     "library-panic",
+					Message:  "panic() in library code - return an error instead",
+					Severity: "info",
+				})
+			}
+
+			// _ = err silently discards an error
+			if cfg.Quality.BanIgnoredErrors && !goParsed && goIgnoredErrRe.MatchString(trimmedCode) {
+				issues = append(issues, Issue{
+					File:     relPath,
+					Line:     lineNum,
+					Rule:     "ignored-error",
+					Message:  "Error assigned to _ - handle or return it",
+					Severity: "warning",
+				})
+			}
 		}
 
 		// subprocess with shell=True
-		if !isComment && strings.Contains(line, "shell=True") {
+		if cfg.Security.BanSubprocessShell && !isComment && strings.Contains(line, "shell=True") {
 			issues = append(issues, Issue{
 				File:     relPath,
 				Line:     lineNum,
@@ -387,9 +1584,416 @@ func checkFile(path string) []Issue {
 				Severity: "warning",
 			})
 		}
+
+		// ["bash", "-c", f"rm {x}"] is shell=True by another name
+		if cfg.Security.BanSubprocessShell && !isComment && !isGo {
+			if loc := argvCallRe.FindStringIndex(line); loc != nil {
+				if shell, arg, ok := shellCommandArg(callText(lines, i, loc[0])); ok {
+					static := isStaticCommand(arg)
+					if !static || cfg.Security.BanStaticShellCommands {
+						message := shell + " -c with a built command string - pass the program and args as a list"
+						if static {
+							message = shell + " -c runs the command through a shell - pass the program and args as a list"
+						}
+						issues = append(issues, Issue{
+							File:     relPath,
+							Line:     lineNum,
+							Rule:     "subprocess-shell",
+							Message:  message,
+							Severity: "warning",
+						})
+					}
+				}
+			}
+		}
 	}
 
-	return issues
+	flushCodeRun()
+	if handlers != nil {
+		issues = append(issues, handlers.finish()...)
+	}
+	issues = append(issues, checkSuppressionComments(relPath, lines, lang, cfg)...)
+	issues = append(issues, checkStubs(relPath, lines, lang, cfg)...)
+
+	issues = append(issues, checkCustomRules(relPath, lang, lines, cfg)...)
+
+	if isTest {
+		issues = relaxTestFileIssues(issues, cfg)
+	}
+
+	for i := range issues {
+		if issues[i].Confidence == "" {
+			issues[i].Confidence = getConfidence(issues[i].Rule)
+		}
+	}
+
+	// "# guardian: disable=..." at the top of the file
+	return applyFileHeader(keepLanguageRules(issues, lang, cfg), lines)
+}
+
+// evalAllowlistRes caches compiled eval_allowlist patterns across files
+var evalAllowlistRes sync.Map // calls joined by NUL -> *regexp.Regexp
+
+// evalAllowlistRe matches a call to any of eval_allowlist as a whole call
+// expression, compiled once per allowlist. nil if the list is empty.
+func evalAllowlistRe(calls []string) *regexp.Regexp {
+	if len(calls) == 0 {
+		return nil
+	}
+	key := strings.Join(calls, "\x00")
+	if re, ok := evalAllowlistRes.Load(key); ok {
+		return re.(*regexp.Regexp)
+	}
+	quoted := make([]string, len(calls))
+	for i, call := range calls {
+		quoted[i] = regexp.QuoteMeta(call)
+	}
+	re := regexp.MustCompile(`(?:^|[^\w.])(?:` + strings.Join(quoted, "|") + `)\s*\(`)
+	evalAllowlistRes.Store(key, re)
+	return re
+}
+
+// blankCall replaces an allowlisted call match with spaces, keeping the
+// delimiter matched before it (which may be a quote)
+func blankCall(m string) string {
+	if c := m[0]; !(c == '_' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z') {
+		return m[:1] + strings.Repeat(" ", len(m)-1)
+	}
+	return strings.Repeat(" ", len(m))
+}
+
+// todoMarkerRe finds a marker at the start of a comment, as split out by
+// splitScopes: # TODO, // FIXME, /* HACK, or * TODO inside a block comment
+var todoMarkerRe = regexp.MustCompile(`(?im)^\s*\*?\s*(?:TODO|FIXME|HACK)\b`)
+
+// looksLikeCommentedCode reports whether a line comment (# in Python, //
+// otherwise) reads like code. It errs towards prose: sentences ending in "."
+// and lines with no code shape at all never count.
+func looksLikeCommentedCode(trimmed, lang string) bool {
+	prefix := "//"
+	if lang == langPython {
+		prefix = "#"
+	}
+	if !strings.HasPrefix(trimmed, prefix) {
+		return false
+	}
+	text := strings.TrimSpace(trimmed[len(prefix):])
+	if text == "" || strings.HasSuffix(text, ".") {
+		return false
+	}
+
+	for _, re := range commentedCodeRes {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lexer states carried between lines by goCode
+const (
+	goStateCode = iota
+	goStateRawString
+	goStateBlockComment
+)
+
+// goCode returns line with the contents of string and rune literals removed
+// (quotes kept) and comments dropped, so Go rules only see code. state is
+// where the previous line left off; the returned state is where this one ends.
+func goCode(line string, state int) (string, int) {
+	var b strings.Builder
+	var quote byte
+	if state == goStateRawString {
+		quote = '`'
+		state = goStateCode
+	}
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		if state == goStateBlockComment {
+			if c == '*' && i+1 < len(line) && line[i+1] == '/' {
+				state = goStateCode
+				i++
+			}
+			continue
+		}
+
+		if quote != 0 {
+			if c == '\\' && quote != '`' {
+				i++
+				continue
+			}
+			if c == quote {
+				quote = 0
+				b.WriteByte(c)
+			}
+			continue
+		}
+
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+			b.WriteByte(c)
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return b.String(), goStateCode
+		case c == '/' && i+1 < len(line) && line[i+1] == '*':
+			state = goStateBlockComment
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	if quote == '`' {
+		return b.String(), goStateRawString
+	}
+	return b.String(), state
+}
+
+// maxCallLines bounds how far callText looks for the closing paren
+const maxCallLines = 20
+
+// callText returns the source of a call starting at lines[start][col],
+// following it across lines until its parentheses balance
+func callText(lines []string, start, col int) string {
+	var sb strings.Builder
+	depth := 0
+	opened := false
+
+	for i := start; i < len(lines) && i < start+maxCallLines; i++ {
+		text := lines[i]
+		if i == start {
+			text = text[col:]
+		}
+		for j, ch := range text {
+			switch ch {
+			case '(':
+				depth++
+				opened = true
+			case ')':
+				depth--
+			}
+			if opened && depth == 0 {
+				sb.WriteString(text[:j+1])
+				return sb.String()
+			}
+		}
+		sb.WriteString(text)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// testDirNames are directory names whose contents are treated as test code
+var testDirNames = map[string]bool{
+	"test":      true,
+	"tests":     true,
+	"__tests__": true,
+	"spec":      true,
+	"specs":     true,
+}
+
+// isTestFile reports whether path looks like test scaffolding - either inside
+// a test directory or named like a test (test_x.py, x_test.go, x.spec.ts)
+func isTestFile(path string) bool {
+	segments := strings.Split(filepath.ToSlash(path), "/")
+	for _, seg := range segments[:len(segments)-1] {
+		if testDirNames[strings.ToLower(seg)] {
+			return true
+		}
+	}
+
+	name := strings.ToLower(segments[len(segments)-1])
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.HasPrefix(name, "test_") ||
+		strings.HasSuffix(stem, "_test") ||
+		strings.HasSuffix(stem, ".test") ||
+		strings.HasSuffix(stem, ".spec") ||
+		name == "conftest.py"
+}
+
+// relaxTestFileIssues drops or downgrades issues from the rules listed in
+// test_file_rules, according to test_file_mode
+func relaxTestFileIssues(issues []Issue, cfg *config.Config) []Issue {
+	if cfg.Quality.TestFileMode == "report" || len(cfg.Quality.TestFileRules) == 0 {
+		return issues
+	}
+
+	relaxed := make(map[string]bool, len(cfg.Quality.TestFileRules))
+	for _, rule := range cfg.Quality.TestFileRules {
+		relaxed[rule] = true
+	}
+
+	kept := issues[:0]
+	for _, issue := range issues {
+		if relaxed[issue.Rule] {
+			if cfg.Quality.TestFileMode == "downgrade" {
+				issue.Severity = "info"
+			} else {
+				continue
+			}
+		}
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
+// maxFileLines returns the line limit for a file, preferring an entry in
+// custom_file_limits over the global max_file_lines. Exact keys win over
+// globs; among globs the most specific pattern (most literal characters) wins.
+func maxFileLines(path string, cfg *config.Config) int {
+	slashed := filepath.ToSlash(path)
+
+	bestPattern, bestLimit, bestScore := "", 0, -1
+	for pattern, limit := range cfg.Limits.CustomFileLimits {
+		if !strings.ContainsAny(pattern, "*?[") {
+			if slashed == pattern || strings.HasSuffix(slashed, "/"+pattern) {
+				return limit
+			}
+			continue
+		}
+		if !globMatch(pattern, slashed) {
+			continue
+		}
+		score := globSpecificity(pattern)
+		// Map order is random, so break ties on the pattern itself
+		if score > bestScore || (score == bestScore && pattern < bestPattern) {
+			bestPattern, bestLimit, bestScore = pattern, limit, score
+		}
+	}
+	if bestScore >= 0 {
+		return bestLimit
+	}
+	return cfg.Limits.MaxFileLines
+}
+
+// globMatch matches a slash-separated path against a pattern where each
+// segment follows filepath.Match and "**" spans any number of segments. Like
+// exact keys, a pattern without a leading "/" may match a trailing part of
+// the path, so "migrations/*.py" also covers "app/migrations/0001.py".
+func globMatch(pattern, p string) bool {
+	patSegs := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	pathSegs := strings.Split(p, "/")
+	if strings.HasPrefix(pattern, "/") {
+		return matchSegments(patSegs, pathSegs)
+	}
+	for i := range pathSegs {
+		if matchSegments(patSegs, pathSegs[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(pat, segs []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			for i := 0; i <= len(segs); i++ {
+				if matchSegments(pat[1:], segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, _ := filepath.Match(pat[0], segs[0]); !ok {
+			return false
+		}
+		pat, segs = pat[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
+
+// globSpecificity counts the literal characters in a pattern
+func globSpecificity(pattern string) int {
+	score := 0
+	for _, r := range pattern {
+		if !strings.ContainsRune("*?[]", r) {
+			score++
+		}
+	}
+	return score
+}
+
+// updateWithoutWhere reports whether line holds a complete UPDATE statement
+// with no WHERE clause. A statement that carries on past the line, like one
+// built from concatenated strings, is left alone since its WHERE may follow.
+func updateWithoutWhere(line string) bool {
+	m := updateSetRe.FindStringSubmatchIndex(line)
+	if m == nil {
+		return false
+	}
+	rest := line[m[2]:m[3]]
+	if m[5] > m[4] {
+		// Ends with ";"
+		return !sqlWhereRe.MatchString(rest)
+	}
+
+	// Otherwise the string holding it must close into a call argument:
+	// "UPDATE t SET a = 1") rather than "UPDATE t SET a = 1 " + ...
+	quote := strings.LastIndexAny(line[:m[0]], "\"'`")
+	if quote < 0 {
+		return false
+	}
+	for i := 0; i < len(rest); i++ {
+		if rest[i] != line[quote] {
+			continue
+		}
+		after := strings.TrimLeft(rest[i+1:], " \t")
+		if strings.HasPrefix(after, ")") || strings.HasPrefix(after, ",") {
+			return !sqlWhereRe.MatchString(rest[:i])
+		}
+	}
+	return false
+}
+
+// FormatIssueLine renders an issue in the canonical guardian.py form,
+// "file:line [rule] message", which parseGuardianOutput reads back
+func FormatIssueLine(issue Issue) string {
+	return fmt.Sprintf("%s:%d [%s] %s", issue.File, issue.Line, issue.Rule, issue.Message)
+}
+
+// FormatPorcelain renders an issue as one tab-separated record,
+// "severity\trule\tfile\tline\tmessage". The field order is fixed so scripts
+// can rely on it; tabs and newlines in the message become spaces.
+func FormatPorcelain(issue Issue) string {
+	message := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(issue.Message)
+	return fmt.Sprintf("%s\t%s\t%s\t%d\t%s", issue.Severity, issue.Rule, issue.File, issue.Line, message)
+}
+
+// githubCommands maps severity to the GitHub Actions workflow command that
+// annotates the diff at that level
+var githubCommands = map[string]string{
+	"critical": "error",
+	"warning":  "warning",
+	"info":     "notice",
+}
+
+// FormatGitHubAnnotation renders an issue as a GitHub Actions workflow
+// command, "::error file=app.py,line=3,title=ban-eval::message", which shows
+// up inline on the pull request diff
+func FormatGitHubAnnotation(issue Issue) string {
+	command, ok := githubCommands[issue.Severity]
+	if !ok {
+		command = "notice"
+	}
+	return fmt.Sprintf("::%s file=%s,line=%d,title=%s::%s", command,
+		escapeGitHubProperty(issue.File), issue.Line, escapeGitHubProperty(issue.Rule), escapeGitHubData(issue.Message))
+}
+
+// escapeGitHubData escapes a workflow command's message
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a workflow command property value, which
+// also can't contain the ":" and "," that separate properties
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
 }
 
 // parseGuardianOutput parses output from guardian.py
@@ -420,11 +2024,12 @@ func parseIssueLine(line string) Issue {
 	if len(matches) == 5 {
 		lineNum, _ := strconv.Atoi(matches[2])
 		return Issue{
-			File:     matches[1],
-			Line:     lineNum,
-			Rule:     matches[3],
-			Message:  matches[4],
-			Severity: getSeverity(matches[3]),
+			File:       matches[1],
+			Line:       lineNum,
+			Rule:       matches[3],
+			Message:    matches[4],
+			Severity:   getSeverity(matches[3]),
+			Confidence: getConfidence(matches[3]),
 		}
 	}
 
@@ -434,49 +2039,177 @@ func parseIssueLine(line string) Issue {
 	if len(matches2) == 5 {
 		lineNum, _ := strconv.Atoi(matches2[2])
 		return Issue{
-			File:     matches2[1],
-			Line:     lineNum,
-			Rule:     matches2[3],
-			Message:  matches2[4],
-			Severity: getSeverity(matches2[3]),
+			File:       matches2[1],
+			Line:       lineNum,
+			Rule:       matches2[3],
+			Message:    matches2[4],
+			Severity:   getSeverity(matches2[3]),
+			Confidence: getConfidence(matches2[3]),
 		}
 	}
 
 	return Issue{}
 }
 
+// RuleSeverity returns the severity a builtin rule reports at; unknown
+// rules are warnings
+func RuleSeverity(rule string) string {
+	return getSeverity(rule)
+}
+
 func getSeverity(rule string) string {
-	criticalRules := map[string]bool{
-		"ban-eval":       true,
-		"dangerous-cmd":  true,
-		"secret-pattern": true,
-		"sql-injection":  true,
+	if info, ok := LookupRule(rule); ok {
+		return info.Severity
+	}
+	return "warning"
+}
+
+// Rules whose matches are name/substring heuristics, by how often they're
+// wrong; every other rule matches exact constructs
+var (
+	lowConfidenceRules = map[string]bool{
+		"mock-data":         true,
+		"unprotected-route": true,
+	}
+	mediumConfidenceRules = map[string]bool{
+		"pii-logging":         true,
+		"secret-pattern":      true,
+		"sql-injection":       true,
+		"hardcoded-path":      true,
+		"no-timeout":          true,
+		"unawaited-async":     true,
+		"stub-implementation": true,
+	}
+)
+
+// getConfidence returns how reliable a rule's matches usually are. Exact
+// constructs are high; name/substring heuristics are lower.
+func getConfidence(rule string) string {
+	if lowConfidenceRules[rule] {
+		return "low"
+	}
+	if mediumConfidenceRules[rule] {
+		return "medium"
 	}
+	return "high"
+}
+
+// confidenceRank orders confidence levels for filtering
+var confidenceRank = map[string]int{
+	"low":    0,
+	"medium": 1,
+	"high":   2,
+}
 
-	if criticalRules[rule] {
-		return "critical"
+// FilterByConfidence drops issues below min ("low", "medium" or "high").
+// Issues without a confidence are kept.
+func FilterByConfidence(issues []Issue, min string) []Issue {
+	minRank, ok := confidenceRank[min]
+	if !ok || minRank == 0 {
+		return issues
 	}
 
-	infoRules := map[string]bool{
-		"ban-print":   true,
-		"ban-console": true,
-		"todo-marker": true,
+	var kept []Issue
+	for _, issue := range issues {
+		rank, ok := confidenceRank[issue.Confidence]
+		if !ok || rank >= minRank {
+			kept = append(kept, issue)
+		}
 	}
+	return kept
+}
+
+// SecretProvider is a token format issued by a known provider. The prefixes
+// and lengths are specific enough that a match is almost always a real key.
+type SecretProvider struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
 
-	if infoRules[rule] {
-		return "info"
+// SecretProviders are matched on every line regardless of variable naming
+var SecretProviders = []SecretProvider{
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"GitHub fine-grained token", regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{22,}\b`)},
+	{"Stripe secret key", regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[baprs]-[A-Za-z0-9-]{10,}\b`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"AWS access key ID", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"OpenAI API key", regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{20,}\b`)},
+	// Header and payload are base64url JSON objects, so both start "eyJ"
+	{"JSON Web Token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	// A literal token, not "Bearer " + token or f"Bearer {token}"
+	{"Bearer token", regexp.MustCompile(`\bBearer\s+[A-Za-z0-9._~+/-]{20,}=*`)},
+}
+
+// MatchProviderSecret returns the provider name of the first known token
+// format found in line
+func MatchProviderSecret(line string) (string, bool) {
+	for _, p := range SecretProviders {
+		if p.Pattern.MatchString(line) {
+			return p.Name, true
+		}
 	}
+	return "", false
+}
 
-	return "warning"
+// secretConfidence scores a secret-pattern match by the quoted value: long
+// high-entropy strings look like real keys, a bare name reference does not
+func secretConfidence(line string) string {
+	best := 0.0
+	longest := 0
+	for _, m := range quotedValueRe.FindAllStringSubmatch(line, -1) {
+		if e := shannonEntropy(m[1]); e > best {
+			best = e
+		}
+		if len(m[1]) > longest {
+			longest = len(m[1])
+		}
+	}
+
+	switch {
+	case longest >= 16 && best >= 3.5:
+		return "high"
+	case longest > 0:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// shannonEntropy returns the bits of entropy per character of s
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	n := float64(len([]rune(s)))
+	entropy := 0.0
+	for _, c := range counts {
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
 }
 
 // DryRun returns info about what would be checked
 func DryRun(dir string) *DryRunInfo {
+	cfg, err := config.Load(dir)
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	return DryRunWithConfig(dir, cfg)
+}
+
+// DryRunWithConfig is DryRun with a pre-loaded config
+func DryRunWithConfig(dir string, cfg *config.Config) *DryRunInfo {
 	info := &DryRunInfo{
 		Excluded: []string{},
 	}
 
-	filepath.Walk(dir, func(path string, fileInfo os.FileInfo, err error) error {
+	walkSource(dir, cfg, nil, func(path string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
@@ -490,9 +2223,8 @@ func DryRun(dir string) *DryRunInfo {
 			return nil
 		}
 
-		ext := filepath.Ext(path)
 		// Match the same file types as runBuiltinChecks
-		if ext != ".py" && ext != ".js" && ext != ".ts" && ext != ".tsx" {
+		if !IsCheckedFile(path, cfg) {
 			return nil
 		}
 
@@ -530,6 +2262,29 @@ func DryRun(dir string) *DryRunInfo {
 		}
 	}
 	info.Excluded = uniqueExcl
+	info.Dirs = rollupDirs(info.Files)
 
 	return info
 }
+
+// rollupDirs totals files by their first path segment, sorted by directory
+func rollupDirs(files []FileInfo) []DirInfo {
+	index := make(map[string]int)
+	var dirs []DirInfo
+	for _, file := range files {
+		top := "."
+		if first, _, ok := strings.Cut(filepath.ToSlash(file.Path), "/"); ok {
+			top = first
+		}
+		i, ok := index[top]
+		if !ok {
+			i = len(dirs)
+			index[top] = i
+			dirs = append(dirs, DirInfo{Path: top})
+		}
+		dirs[i].Files++
+		dirs[i].Lines += file.Lines
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Path < dirs[j].Path })
+	return dirs
+}
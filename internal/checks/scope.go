@@ -0,0 +1,120 @@
+package checks
+
+import "strings"
+
+// lineScopes is one line split by what its text is: code, the contents of
+// comments, and the contents of string literals. Each comment on the line
+// starts a new line of comment, so ^ in (?m) finds where one begins.
+type lineScopes struct {
+	code, comment, str string
+}
+
+// scopeText returns the part of a line a custom rule's scope matches
+// against; "" scope means the whole line
+func (s lineScopes) scopeText(scope, line string) string {
+	switch scope {
+	case "code":
+		return s.code
+	case "comment":
+		return s.comment
+	case "string":
+		return s.str
+	}
+	return line
+}
+
+// splitScopes splits each line of a file into code, comments and strings.
+// Block comments, Python triple-quoted strings and JS/Go backtick strings
+// carry over between lines. Other languages are all code.
+func splitScopes(lines []string, lang string) []lineScopes {
+	var lineComment, blockComment bool
+	quotes := `"'`
+	switch lang {
+	case langPython, langShell:
+		lineComment = true
+	case langJS, langGo:
+		blockComment = true
+		quotes = "\"'`"
+	default:
+		scopes := make([]lineScopes, len(lines))
+		for i, line := range lines {
+			scopes[i].code = line
+		}
+		return scopes
+	}
+
+	scopes := make([]lineScopes, len(lines))
+	var quote string // The open string's delimiter, kept across lines
+	inBlock := false
+	for n, line := range lines {
+		var code, comment, str strings.Builder
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			switch {
+			case inBlock:
+				if strings.HasPrefix(line[i:], "*/") {
+					inBlock = false
+					i++
+					continue
+				}
+				comment.WriteByte(c)
+
+			case quote != "":
+				if c == '\\' && quote != "`" && !(lang == langShell && quote == "'") {
+					if i+1 < len(line) {
+						str.WriteString(line[i : i+2])
+					}
+					i++
+					continue
+				}
+				if strings.HasPrefix(line[i:], quote) {
+					code.WriteString(quote)
+					i += len(quote) - 1
+					quote = ""
+					str.WriteByte(' ')
+					continue
+				}
+				str.WriteByte(c)
+
+			case lineComment && c == '#' && (lang == langPython || i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+				newComment(&comment)
+				comment.WriteString(line[i+1:])
+				i = len(line)
+
+			case blockComment && strings.HasPrefix(line[i:], "//"):
+				newComment(&comment)
+				comment.WriteString(line[i+2:])
+				i = len(line)
+
+			case blockComment && strings.HasPrefix(line[i:], "/*"):
+				inBlock = true
+				newComment(&comment)
+				i++
+
+			case strings.IndexByte(quotes, c) >= 0:
+				quote = string(c)
+				if lang == langPython && (strings.HasPrefix(line[i:], `"""`) || strings.HasPrefix(line[i:], `'''`)) {
+					quote = line[i : i+3]
+				}
+				code.WriteString(quote)
+				i += len(quote) - 1
+
+			default:
+				code.WriteByte(c)
+			}
+		}
+		// Only multi-line strings stay open past the end of a line
+		if quote != "" && len(quote) == 1 && quote != "`" {
+			quote = ""
+		}
+		scopes[n] = lineScopes{code: code.String(), comment: comment.String(), str: str.String()}
+	}
+	return scopes
+}
+
+// newComment separates a comment from any earlier one on the same line
+func newComment(comment *strings.Builder) {
+	if comment.Len() > 0 {
+		comment.WriteByte('\n')
+	}
+}
@@ -0,0 +1,40 @@
+package checks
+
+import "math"
+
+// ScoreWeights is how much one issue of each severity costs
+type ScoreWeights struct {
+	Critical float64
+	Warning  float64
+	Info     float64
+}
+
+// DefaultScoreWeights makes one critical issue cost as much as ten info ones
+var DefaultScoreWeights = ScoreWeights{Critical: 10, Warning: 3, Info: 1}
+
+// scoreHalfDensity is the weighted issues per 1000 lines at which the score
+// drops to 50. The curve never goes below 0, however dirty the code.
+const scoreHalfDensity = 25.0
+
+// Score turns issue counts into a 0-100 cleanliness score. Issues are
+// weighted by severity and divided by lines of code, so a large codebase
+// isn't punished for having more of the same problems than a small one.
+func Score(issues []Issue, totalLines int, weights ScoreWeights) int {
+	weighted := 0.0
+	for _, issue := range issues {
+		switch issue.Severity {
+		case "critical":
+			weighted += weights.Critical
+		case "warning":
+			weighted += weights.Warning
+		default:
+			weighted += weights.Info
+		}
+	}
+	if weighted == 0 {
+		return 100
+	}
+
+	density := weighted * 1000 / float64(max(totalLines, 1))
+	return int(math.Round(100 / (1 + density/scoreHalfDensity)))
+}
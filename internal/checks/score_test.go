@@ -0,0 +1,70 @@
+package checks
+
+import "testing"
+
+func issuesOf(severities ...string) []Issue {
+	var issues []Issue
+	for _, s := range severities {
+		issues = append(issues, Issue{Severity: s})
+	}
+	return issues
+}
+
+func TestScore_CleanRepo(t *testing.T) {
+	if got := Score(nil, 5000, DefaultScoreWeights); got != 100 {
+		t.Errorf("expected 100 for no issues, got %d", got)
+	}
+	if got := Score(nil, 0, DefaultScoreWeights); got != 100 {
+		t.Errorf("expected 100 for an empty repo, got %d", got)
+	}
+}
+
+func TestScore_MostlyClean(t *testing.T) {
+	// 3 info + 1 warning = 6 weighted over 10k lines: 0.6 per 1000
+	got := Score(issuesOf("info", "info", "info", "warning"), 10000, DefaultScoreWeights)
+	if got < 95 {
+		t.Errorf("expected a high score, got %d", got)
+	}
+}
+
+func TestScore_DirtyRepo(t *testing.T) {
+	issues := issuesOf("critical", "critical", "critical", "critical", "critical", "warning", "warning")
+	got := Score(issues, 500, DefaultScoreWeights)
+	if got > 20 {
+		t.Errorf("expected a low score, got %d", got)
+	}
+}
+
+func TestScore_HalfDensityIsFifty(t *testing.T) {
+	// 25 weighted issues per 1000 lines
+	issues := issuesOf("critical", "critical", "warning", "warning", "info", "info")
+	weights := ScoreWeights{Critical: 10, Warning: 2, Info: 0.5}
+	if got := Score(issues, 1000, weights); got != 50 {
+		t.Errorf("expected 50, got %d", got)
+	}
+}
+
+func TestScore_NormalizedByLines(t *testing.T) {
+	issues := issuesOf("critical", "warning")
+
+	small := Score(issues, 200, DefaultScoreWeights)
+	large := Score(issues, 20000, DefaultScoreWeights)
+	if small >= large {
+		t.Errorf("same issues in more code should score higher: %d lines=%d, %d lines=%d", 200, small, 20000, large)
+	}
+
+	// Doubling both code and issues keeps the density, so the score
+	doubled := Score(append(issues, issues...), 400, DefaultScoreWeights)
+	if doubled != small {
+		t.Errorf("same density should give the same score: %d vs %d", small, doubled)
+	}
+}
+
+func TestScore_CriticalWeighsMost(t *testing.T) {
+	critical := Score(issuesOf("critical"), 1000, DefaultScoreWeights)
+	warning := Score(issuesOf("warning"), 1000, DefaultScoreWeights)
+	info := Score(issuesOf("info"), 1000, DefaultScoreWeights)
+	if !(critical < warning && warning < info) {
+		t.Errorf("expected critical < warning < info, got %d, %d, %d", critical, warning, info)
+	}
+}
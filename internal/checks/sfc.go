@@ -0,0 +1,55 @@
+package checks
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Single-file components (.vue, .svelte) are checked with the JS rules, but
+// only inside their <script> blocks; the template and styles are blanked.
+var (
+	sfcExtensions = map[string]bool{".vue": true, ".svelte": true}
+	scriptOpenRe  = regexp.MustCompile(`(?i)<script\b[^>]*>`)
+	scriptCloseRe = regexp.MustCompile(`(?i)</script\s*>`)
+)
+
+// isSFC reports whether path is a single-file component
+func isSFC(path string) bool {
+	return sfcExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// sfcScriptLines keeps only the <script> contents of a component. Every
+// other character becomes a space, or is dropped at the end of a line, so
+// issues keep the component's own line numbers and columns.
+func sfcScriptLines(lines []string) []string {
+	out := make([]string, len(lines))
+	inScript := false
+
+	for i, line := range lines {
+		kept := []byte(strings.Repeat(" ", len(line)))
+		pos := 0
+		for pos < len(line) {
+			if !inScript {
+				loc := scriptOpenRe.FindStringIndex(line[pos:])
+				if loc == nil {
+					break
+				}
+				pos += loc[1]
+				inScript = true
+				continue
+			}
+
+			end := len(line)
+			next := end
+			if loc := scriptCloseRe.FindStringIndex(line[pos:]); loc != nil {
+				end, next = pos+loc[0], pos+loc[1]
+				inScript = false
+			}
+			copy(kept[pos:end], line[pos:end])
+			pos = next
+		}
+		out[i] = strings.TrimRight(string(kept), " ")
+	}
+	return out
+}
@@ -0,0 +1,211 @@
+package checks
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+var (
+	// A download piped straight into a shell: curl ... | sh, wget -qO- ... | sudo bash
+	curlPipeShRe = regexp.MustCompile(`\b(?:curl|wget)\b[^|]*\|\s*(?:sudo\s+(?:-\S+\s+)*)?(?:ba|z|da|k)?sh\b`)
+
+	// Downloaded code run without touching disk: eval "$(curl ...)",
+	// sh -c "$(wget -qO- ...)", bash <(curl ...), source <(curl ...)
+	remoteExecRe = regexp.MustCompile(`\beval\s+["']?(?:\$\(|` + "`" + `)\s*(?:curl|wget)\b` +
+		`|\b(?:ba|z|da|k)?sh\s+-c\s+["']?(?:\$\(|` + "`" + `)\s*(?:curl|wget)\b` +
+		`|(?:\b(?:ba|z|da|k)?sh|\bsource|(?:^|[;&|]\s*)\.)\s+<\(\s*(?:curl|wget)\b`)
+
+	// Where a download is saved: curl -o FILE, curl --output FILE, wget -O FILE
+	downloadTargetRe = regexp.MustCompile(`\bcurl\b.*?\s(?:-\w*o\s*|--output[\s=]+)(\S+)` +
+		`|\bwget\b.*?\s(?:-\w*O\s*|--output-document[\s=]+)(\S+)`)
+
+	// chmod that makes a file executable: +x, u+x, a+rx, 755, 0700
+	chmodExecRe = regexp.MustCompile(`\bchmod\s+(?:-\S+\s+)*(?:[ugoa]*\+[rwX]*x[rwX]*|0?[1357][0-7]{2})\s+(.+)`)
+
+	// Commands that check a download before it's trusted
+	checksumRe = regexp.MustCompile(`\b(?:sha(?:1|224|256|384|512)sum|shasum|md5sum|b2sum|gpg\s+--verify|cosign\s+verify\S*)\b`)
+
+	// Unquoted assignments as written in shell and Dockerfiles: export TOKEN=abc,
+	// ENV DB_PASSWORD=hunter2. Values starting with $ are references, not secrets.
+	shellSecretRe = regexp.MustCompile(`(?i)^(?:(?:export|ENV|ARG)\s+)?\w*(?:api_?key|password|passwd|secret(?:_key)?|token)=["']?[^\s"'$]{4,}` +
+		`|^ENV\s+\w*(?:api_?key|password|passwd|secret(?:_key)?|token)\s+["']?[^\s"'$]{4,}`)
+)
+
+// isDockerfile matches Dockerfile, Dockerfile.prod and api.dockerfile
+func isDockerfile(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	return name == "dockerfile" || strings.HasPrefix(name, "dockerfile.") || strings.HasSuffix(name, ".dockerfile")
+}
+
+// isCIConfig matches CI configs whose jobs are shell commands:
+// .gitlab-ci.yml and GitHub Actions workflows
+func isCIConfig(file string) bool {
+	slashed := filepath.ToSlash(file)
+	if strings.EqualFold(path.Base(slashed), ".gitlab-ci.yml") {
+		return true
+	}
+	ext := path.Ext(slashed)
+	return (ext == ".yml" || ext == ".yaml") && strings.HasSuffix(path.Dir(slashed), ".github/workflows")
+}
+
+// shellLine is one logical command, with backslash continuations joined
+type shellLine struct {
+	num  int // Line the command starts on
+	text string
+}
+
+// joinContinuations folds lines ending in "\" into the following line, so a
+// pipe on the next line is still seen as part of the same command
+func joinContinuations(lines []string) []shellLine {
+	var joined []shellLine
+	var cur strings.Builder
+	start := 0
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if cur.Len() == 0 {
+			start = i + 1
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+		}
+
+		if strings.HasSuffix(trimmed, `\`) {
+			cur.WriteString(strings.TrimSuffix(trimmed, `\`) + " ")
+			continue
+		}
+		cur.WriteString(trimmed)
+		joined = append(joined, shellLine{num: start, text: cur.String()})
+		cur.Reset()
+	}
+	if cur.Len() > 0 {
+		joined = append(joined, shellLine{num: start, text: cur.String()})
+	}
+	return joined
+}
+
+// checkShellFile runs the infra rules over a shell script, Dockerfile or CI
+// config: dangerous-cmd, secret-pattern, curl-pipe-sh and remote-exec
+func checkShellFile(relPath string, lines []string, cfg *config.Config) []Issue {
+	var issues []Issue
+
+	// Files this script downloads, until a checksum command mentions them
+	downloaded := make(map[string]bool)
+
+	for _, line := range joinContinuations(lines) {
+		if cfg.Security.BanDangerousCommands {
+			for _, re := range dangerousPatternRegexes {
+				if re.MatchString(line.text) {
+					issues = append(issues, Issue{
+						File:     relPath,
+						Line:     line.num,
+						Rule:     "dangerous-cmd",
+						Message:  "Dangerous command detected - review carefully",
+						Severity: "critical",
+					})
+					break
+				}
+			}
+		}
+
+		if provider, ok := MatchProviderSecret(line.text); ok {
+			issues = append(issues, Issue{
+				File:       relPath,
+				Line:       line.num,
+				Rule:       "secret-pattern",
+				Message:    provider + " found - revoke it and load from environment variables",
+				Severity:   "critical",
+				Confidence: "high",
+			})
+		} else if shellSecretRe.MatchString(line.text) {
+			issues = append(issues, Issue{
+				File:     relPath,
+				Line:     line.num,
+				Rule:     "secret-pattern",
+				Message:  "Possible hardcoded secret - pass it in at runtime (env var, build secret)",
+				Severity: "critical",
+			})
+		}
+
+		if cfg.Security.BanCurlPipeShell && curlPipeShRe.MatchString(line.text) {
+			issues = append(issues, Issue{
+				File:     relPath,
+				Line:     line.num,
+				Rule:     "curl-pipe-sh",
+				Message:  "Downloaded script piped straight into a shell - download, verify a checksum, then run",
+				Severity: "warning",
+			})
+		}
+
+		if cfg.Security.BanRemoteExec {
+			issues = append(issues, checkRemoteExec(relPath, line, downloaded)...)
+		}
+	}
+
+	return issues
+}
+
+// checkRemoteExec flags downloaded code that runs unchecked: a download
+// eval'd or fed to a shell directly, or saved and made executable with no
+// checksum verified in between. downloaded carries saved files from line
+// to line. A pipe into a shell is curl-pipe-sh's.
+func checkRemoteExec(relPath string, line shellLine, downloaded map[string]bool) []Issue {
+	if remoteExecRe.MatchString(line.text) {
+		return []Issue{{
+			File:     relPath,
+			Line:     line.num,
+			Rule:     "remote-exec",
+			Message:  "Downloaded code run without being saved or checked - download it, verify a checksum, then run it",
+			Severity: "critical",
+		}}
+	}
+
+	// curl -o tool https://... && chmod +x tool is one line, so take each
+	// command in order
+	var issues []Issue
+	for _, cmd := range shellCommandSplitRe.Split(line.text, -1) {
+		if m := downloadTargetRe.FindStringSubmatch(cmd); m != nil {
+			if target := shellWord(m[1] + m[2]); target != "" && target != "-" {
+				downloaded[target] = true
+			}
+			continue
+		}
+		if checksumRe.MatchString(cmd) {
+			for target := range downloaded {
+				if strings.Contains(line.text, target) {
+					delete(downloaded, target)
+				}
+			}
+			continue
+		}
+		m := chmodExecRe.FindStringSubmatch(cmd)
+		if m == nil {
+			continue
+		}
+		for _, arg := range strings.Fields(m[1]) {
+			if target := shellWord(arg); downloaded[target] {
+				issues = append(issues, Issue{
+					File:     relPath,
+					Line:     line.num,
+					Rule:     "remote-exec",
+					Message:  target + " is downloaded and made executable without a checksum check - verify it first (sha256sum -c)",
+					Severity: "critical",
+				})
+				break
+			}
+		}
+	}
+	return issues
+}
+
+// shellCommandSplitRe splits a line into commands at &&, ||, ; and |
+var shellCommandSplitRe = regexp.MustCompile(`\s*(?:&&|\|\||;|\|)\s*`)
+
+// shellWord strips quotes and a leading ./ from a file argument
+func shellWord(word string) string {
+	return strings.TrimPrefix(strings.Trim(word, `"'`), "./")
+}
@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Calls that take a program and its arguments: Python's subprocess and
+	// asyncio, Node's child_process and execa
+	argvCallRe = regexp.MustCompile(`(?:^|[^\w.])(?:subprocess\.\w+|Popen|check_output|check_call|create_subprocess_exec|spawn|spawnSync|execFile|execFileSync|execa)\s*\(`)
+
+	// "bash", "-c", or "cmd", "/c", then the command string. Node's
+	// spawn("sh", ["-c", ...]) opens the argument list between them.
+	shellCFlagRe = regexp.MustCompile(`["'` + "`" + `]((?:/(?:usr/)?bin/)?(?:ba|z|da|k)?sh|cmd(?:\.exe)?)["'` + "`" + `]\s*,\s*\[?\s*["'` + "`" + `](?:-[a-z]*c|/[cC])["'` + "`" + `]\s*,\s*`)
+
+	// A plain string literal, with any Python prefix; f-strings are
+	// checked for placeholders separately
+	staticStringRe = regexp.MustCompile(`^([rRbBuUfF]{0,2})(?:"[^"]*"|'[^']*')$|^` + "`[^`]*`$")
+)
+
+// shellCommandArg finds sh -c / cmd /c in a call's argument list and returns
+// the shell and the command string argument's source text
+func shellCommandArg(call string) (shell, arg string, ok bool) {
+	loc := shellCFlagRe.FindStringSubmatchIndex(call)
+	if loc == nil {
+		return "", "", false
+	}
+	shell = call[loc[2]:loc[3]]
+	if i := strings.LastIndex(shell, "/"); i >= 0 {
+		shell = shell[i+1:]
+	}
+
+	// The argument runs to the next comma or bracket outside strings and
+	// brackets
+	rest := call[loc[1]:]
+	depth := 0
+	var quote byte
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case (c == ')' || c == ']' || c == '}') && depth > 0:
+			depth--
+		case (c == ',' || c == ')' || c == ']') && depth == 0:
+			return shell, strings.TrimSpace(rest[:i]), true
+		}
+	}
+	return shell, strings.TrimSpace(rest), true
+}
+
+// isStaticCommand reports whether a command string argument is a constant,
+// not an f-string, template literal, format call, concatenation or variable
+func isStaticCommand(arg string) bool {
+	m := staticStringRe.FindStringSubmatch(arg)
+	if m == nil {
+		return false
+	}
+	if strings.HasPrefix(arg, "`") {
+		return !strings.Contains(arg, "${")
+	}
+	return !strings.ContainsAny(m[1], "fF") || !strings.Contains(arg, "{")
+}
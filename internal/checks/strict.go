@@ -0,0 +1,68 @@
+package checks
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// elevatedSeverity is one level up from severity
+var elevatedSeverity = map[string]string{
+	"info":    "warning",
+	"warning": "critical",
+}
+
+// isStrictPath reports whether relPath falls under one of strict.paths. A
+// pattern without glob characters names a file or a whole directory.
+func isStrictPath(relPath string, cfg *config.Config) bool {
+	for _, pattern := range cfg.Strict.Paths {
+		pattern = strings.TrimSuffix(pattern, "/")
+		if !strings.ContainsAny(pattern, "*?[") {
+			pattern += "/**"
+		}
+		if globMatch(pattern, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// elevateStrict raises the severity of each issue under strict.paths by one
+// level: info to warning, warning to critical
+func elevateStrict(issues []Issue, cfg *config.Config) []Issue {
+	if len(cfg.Strict.Paths) == 0 {
+		return issues
+	}
+	for i := range issues {
+		if up, ok := elevatedSeverity[issues[i].Severity]; ok && isStrictPath(issues[i].File, cfg) {
+			issues[i].Severity = up
+		}
+	}
+	return issues
+}
+
+// strictConfigs hands out the config a file is checked with: its own, or
+// for a strict path the same with strict.rules turned on. Each strict
+// variant is built once per run and shared.
+type strictConfigs struct {
+	mu     sync.Mutex
+	strict map[*config.Config]*config.Config
+}
+
+func (s *strictConfigs) For(relPath string, fileCfg *config.Config) *config.Config {
+	if len(fileCfg.Strict.Rules) == 0 || !isStrictPath(relPath, fileCfg) {
+		return fileCfg
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.strict == nil {
+		s.strict = make(map[*config.Config]*config.Config)
+	}
+	if cfg, ok := s.strict[fileCfg]; ok {
+		return cfg
+	}
+	cfg := fileCfg.WithStrictRules()
+	s.strict[fileCfg] = cfg
+	return cfg
+}
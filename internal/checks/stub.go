@@ -0,0 +1,309 @@
+package checks
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+var (
+	pyStubDefRe = regexp.MustCompile(`^\s*(?:async\s+)?def\s+(\w+)`)
+	pyClassRe   = regexp.MustCompile(`^class\s+(\w+)(?:\s*\(([^)]*)\))?`)
+
+	// Bases and decorators that mean an empty body is the point
+	pyAbstractBaseRe      = regexp.MustCompile(`\b(?:Protocol|ABC|ABCMeta|Interface)\b`)
+	pyAbstractDecoratorRe = regexp.MustCompile(`^@(?:abc\.)?abstract\w*|^@(?:typing\.)?overload\b`)
+
+	// A statement that stands in for a body, with any trailing comment
+	pyStubStmtRe = regexp.MustCompile(`^(pass|\.\.\.|raise\s+NotImplementedError\b[^#]*?)\s*(?:#.*)?$`)
+
+	// throw new Error("Not implemented"), "not yet implemented", "unimplemented"
+	jsNotImplementedRe = regexp.MustCompile("(?i)^throw\\s+new\\s+Error\\(\\s*[\"'`][^\"'`]*\\b(?:not\\s+(?:yet\\s+)?implemented|unimplemented)\\b[^\"'`]*[\"'`]\\s*\\)\\s*;?$")
+
+	// A whole function on one line: header { statement }
+	jsInlineBodyRe = regexp.MustCompile(`^(.*)\{\s*([^{}]*?)\s*\}\s*;?$`)
+
+	// What comes before a function's {: function f(), (a) =>, or a method
+	jsFunctionHeaderRe = regexp.MustCompile(`\bfunction\b|=>\s*$` +
+		`|^(?:(?:public|private|protected|static|async|override|get|set)\s+)*[\w$#]+\s*\([^)]*\)\s*(?::\s*[^{]+)?$`)
+	jsControlRe = regexp.MustCompile(`^(?:if|for|while|switch|catch|with|else|do|try)\b`)
+
+	// The function's name, from whichever header form it is
+	jsFunctionNameRe = regexp.MustCompile(`function\s*\*?\s*([\w$]+)` +
+		`|([\w$]+)\s*[=:]\s*(?:async\s+)?(?:function\b|\()` +
+		`|^(?:(?:public|private|protected|static|async|override|get|set)\s+)*([\w$#]+)\s*\(`)
+)
+
+// checkStubs flags functions left as placeholders: Python bodies that are
+// only pass, ... or raise NotImplementedError, and JS functions that only
+// throw a "not implemented" error. Abstract methods, overloads and
+// Protocol/ABC classes are skipped, since there an empty body is the point,
+// and so are base class methods that raise NotImplementedError with a
+// docstring or in a class something in the file extends.
+func checkStubs(relPath string, lines []string, lang string, cfg *config.Config) []Issue {
+	if !cfg.Quality.BanStubImplementations {
+		return nil
+	}
+	switch lang {
+	case langPython:
+		if filepath.Ext(relPath) == ".pyi" {
+			return nil // Type stubs are all stubs
+		}
+		return pythonStubs(relPath, lines)
+	case langJS:
+		return jsStubs(relPath, lines)
+	}
+	return nil
+}
+
+func stubIssue(relPath string, line int, name, body string) Issue {
+	return Issue{
+		File:     relPath,
+		Line:     line,
+		Rule:     "stub-implementation",
+		Message:  name + "() is a stub (" + body + ") - implement it or remove it",
+		Severity: "info",
+	}
+}
+
+func pythonStubs(relPath string, lines []string) []Issue {
+	type class struct {
+		indent   int
+		abstract bool
+		base     bool // Subclassed in this file
+	}
+	var classes []class
+	var issues []Issue
+	bases := pythonBaseClasses(lines)
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(leadingSpace(line))
+		for len(classes) > 0 && indent <= classes[len(classes)-1].indent {
+			classes = classes[:len(classes)-1]
+		}
+		if cm := pyClassRe.FindStringSubmatch(trimmed); cm != nil {
+			abstract := pyAbstractBaseRe.MatchString(trimmed)
+			if len(classes) > 0 && classes[len(classes)-1].abstract {
+				abstract = true
+			}
+			classes = append(classes, class{indent: indent, abstract: abstract, base: bases[cm[1]]})
+			continue
+		}
+
+		m := pyStubDefRe.FindStringSubmatch(line)
+		if m == nil || (len(classes) > 0 && classes[len(classes)-1].abstract) || pyAbstractDecorated(lines, i) {
+			continue
+		}
+		body, documented := pythonFunctionBody(lines, i, indent)
+		if len(body) == 0 {
+			continue // Docstring only, or a body we couldn't read
+		}
+		stub := ""
+		for _, stmt := range body {
+			sm := pyStubStmtRe.FindStringSubmatch(stmt)
+			if sm == nil {
+				stub = ""
+				break
+			}
+			if stub == "" {
+				stub = sm[1]
+			}
+		}
+		switch {
+		case stub == "":
+		case strings.HasPrefix(stub, "raise"):
+			// A documented or overridden method is a base class's hook
+			if len(classes) > 0 && (documented || classes[len(classes)-1].base) {
+				continue
+			}
+			issues = append(issues, stubIssue(relPath, i+1, m[1], "only raises NotImplementedError"))
+		default:
+			issues = append(issues, stubIssue(relPath, i+1, m[1], "only "+stub))
+		}
+	}
+	return issues
+}
+
+// pyAbstractDecorated reports whether the def on lines[i] has an
+// @abstractmethod or @overload decorator
+func pyAbstractDecorated(lines []string, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		trimmed := strings.TrimSpace(lines[j])
+		if !strings.HasPrefix(trimmed, "@") {
+			return false
+		}
+		if pyAbstractDecoratorRe.MatchString(trimmed) {
+			return true
+		}
+	}
+	return false
+}
+
+// pythonBaseClasses returns the names the file's classes inherit from. A
+// dotted base counts by its last part, as in class Child(models.Base).
+func pythonBaseClasses(lines []string) map[string]bool {
+	bases := make(map[string]bool)
+	for _, line := range lines {
+		m := pyClassRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		for _, base := range strings.Split(m[2], ",") {
+			base = strings.TrimSpace(base)
+			if base == "" || strings.Contains(base, "=") {
+				continue // metaclass= and other keywords
+			}
+			bases[base[strings.LastIndex(base, ".")+1:]] = true
+		}
+	}
+	return bases
+}
+
+// pythonFunctionBody returns the statements of the function defined on
+// lines[i], one per line, without comments or a leading docstring, and
+// whether there was a docstring
+func pythonFunctionBody(lines []string, i, indent int) (body []string, documented bool) {
+	// The signature can span lines; the body starts after the ":" that
+	// ends it
+	depth := 0
+	j := i
+	for ; j < len(lines); j++ {
+		depth += bracketDepth(lines[j])
+		if depth <= 0 {
+			break
+		}
+	}
+	if j == len(lines) {
+		return nil, false
+	}
+
+	if inline := pyInlineBody(lines[j]); inline != "" {
+		body = append(body, inline)
+	}
+	for k := j + 1; k < len(lines); k++ {
+		trimmed := strings.TrimSpace(lines[k])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if len(leadingSpace(lines[k])) <= indent {
+			break
+		}
+		body = append(body, trimmed)
+	}
+
+	// Drop a docstring, which may span several of the lines
+	if len(body) > 0 {
+		first := strings.TrimLeft(body[0], "rRuUbB")
+		for _, delim := range []string{`"""`, `'''`, `"`, `'`} {
+			if !strings.HasPrefix(first, delim) {
+				continue
+			}
+			end := 0
+			if len(delim) == 3 && strings.Count(first, delim) < 2 {
+				end = 1
+				for end < len(body) && !strings.Contains(body[end], delim) {
+					end++
+				}
+			}
+			if end >= len(body) {
+				return nil, true
+			}
+			body = body[end+1:]
+			documented = true
+			break
+		}
+	}
+	return body, documented
+}
+
+// pyInlineBody returns what follows the colon ending a def line, as in
+// def f(): pass, or "" when the body is on the lines below
+func pyInlineBody(line string) string {
+	code := strings.TrimSpace(line)
+	if i := strings.Index(code, " #"); i >= 0 {
+		code = strings.TrimSpace(code[:i])
+	}
+	if strings.HasSuffix(code, ":") {
+		return ""
+	}
+	// The first ":" outside brackets after the parameters
+	depth := 0
+	for i, c := range code {
+		switch c {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ':':
+			if depth == 0 && strings.Contains(code[:i], ")") {
+				return strings.TrimSpace(code[i+1:])
+			}
+		}
+	}
+	return ""
+}
+
+func jsStubs(relPath string, lines []string) []Issue {
+	var issues []Issue
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// function f() { throw new Error("not implemented"); }
+		if m := jsInlineBodyRe.FindStringSubmatch(trimmed); m != nil && jsNotImplementedRe.MatchString(m[2]) {
+			if name, ok := jsFunctionName(m[1]); ok {
+				issues = append(issues, stubIssue(relPath, i+1, name, "only throws a not implemented error"))
+			}
+			continue
+		}
+
+		// The throw alone between a function's { and }
+		if !jsNotImplementedRe.MatchString(trimmed) {
+			continue
+		}
+		prev := jsSignificantLine(lines, i, -1)
+		next := jsSignificantLine(lines, i, 1)
+		if prev < 0 || next < 0 || !strings.HasPrefix(strings.TrimSpace(lines[next]), "}") {
+			continue
+		}
+		header := strings.TrimSpace(lines[prev])
+		if !strings.HasSuffix(header, "{") {
+			continue
+		}
+		if name, ok := jsFunctionName(strings.TrimSuffix(header, "{")); ok {
+			issues = append(issues, stubIssue(relPath, prev+1, name, "only throws a not implemented error"))
+		}
+	}
+	return issues
+}
+
+// jsFunctionName returns the name of the function whose header comes before
+// a {, and false when the { opens something else or an abstract method
+func jsFunctionName(header string) (string, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || jsControlRe.MatchString(header) || strings.Contains(header, "abstract ") ||
+		!jsFunctionHeaderRe.MatchString(header) {
+		return "", false
+	}
+	if m := jsFunctionNameRe.FindStringSubmatch(header); m != nil {
+		return m[1] + m[2] + m[3], true
+	}
+	return "function", true
+}
+
+// jsSignificantLine finds the nearest line from i in direction step that
+// isn't blank or a // comment, or -1
+func jsSignificantLine(lines []string, i, step int) int {
+	for j := i + step; j >= 0 && j < len(lines); j += step {
+		trimmed := strings.TrimSpace(lines[j])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "//") {
+			return j
+		}
+	}
+	return -1
+}
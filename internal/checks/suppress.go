@@ -0,0 +1,67 @@
+package checks
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+var (
+	// # type: ignore[...] and # noqa: E501, anywhere in a line
+	pySuppressionRe = regexp.MustCompile(`(?i)#\s*(type:\s*ignore|noqa)\b`)
+
+	// // @ts-ignore, // eslint-disable-next-line, /* eslint-disable */.
+	// "://" is a URL, not a comment.
+	jsSuppressionRe = regexp.MustCompile(`(?:(?:^|[^:])//|/\*)\s*(@ts-ignore|eslint-disable)`)
+)
+
+// checkSuppressionComments flags comments that silence another tool's
+// checks. Every one is reported once a file has more than
+// max_suppression_comments of them, so a file with a couple of deliberate
+// ones can stay clean.
+func checkSuppressionComments(relPath string, lines []string, lang string, cfg *config.Config) []Issue {
+	if !cfg.Quality.BanSuppressionComments {
+		return nil
+	}
+	re := pySuppressionRe
+	switch lang {
+	case langPython:
+	case langJS:
+		re = jsSuppressionRe
+	default:
+		return nil
+	}
+
+	var issues []Issue
+	for i, line := range lines {
+		m := re.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		issues = append(issues, Issue{
+			File:     relPath,
+			Line:     i + 1,
+			Rule:     "suppression-comment",
+			Message:  suppressionName(m[1]) + " hides a problem from another tool - fix it instead",
+			Severity: "info",
+		})
+	}
+	if len(issues) <= cfg.Quality.MaxSuppressionComments {
+		return nil
+	}
+	return issues
+}
+
+// suppressionName renders a matched suppression the usual way, whatever
+// its spacing or case
+func suppressionName(match string) string {
+	lower := strings.ToLower(match)
+	switch {
+	case strings.HasPrefix(lower, "type"):
+		return "# type: ignore"
+	case lower == "noqa":
+		return "# noqa"
+	}
+	return match
+}
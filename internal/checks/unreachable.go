@@ -0,0 +1,97 @@
+package checks
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// A statement that leaves the block: return, raise/throw, break, continue
+	pyExitRe = regexp.MustCompile(`^(return|raise|break|continue)\b`)
+	jsExitRe = regexp.MustCompile(`^(return|throw|break|continue)\b`)
+
+	// Lines that can follow an exit at the same indentation and still run
+	pyReachableRe = regexp.MustCompile(`^(?:elif|else|except|finally|case)\b`)
+	jsReachableRe = regexp.MustCompile(`^(?:[})\]]|case\b|default\b|(?:async\s+)?function\b)`) // Function declarations are hoisted
+)
+
+// unreachableTracker remembers the last exit statement so the line after it
+// can be judged. It only flags a line at exactly the exit's indentation;
+// anything it can't be sure of, like a return that opens a string, is
+// skipped.
+type unreachableTracker struct {
+	exitRe, reachableRe *regexp.Regexp
+	js                  bool
+
+	exit   string // Keyword of the pending exit, "" if none
+	line   int    // Its line number
+	indent int
+	depth  int // Brackets still open in a multi-line exit
+}
+
+func newUnreachableTracker(lang string) *unreachableTracker {
+	switch lang {
+	case langPython:
+		return &unreachableTracker{exitRe: pyExitRe, reachableRe: pyReachableRe}
+	case langJS:
+		return &unreachableTracker{exitRe: jsExitRe, reachableRe: jsReachableRe, js: true}
+	}
+	return nil
+}
+
+// feed takes the next non-blank, non-comment line. When the line can't be
+// reached it returns the exit keyword and the line it's on.
+func (t *unreachableTracker) feed(lineNum int, line string) (exit string, exitLine int) {
+	trimmed := strings.TrimSpace(line)
+	if t.js && (strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*")) {
+		return "", 0
+	}
+
+	// The rest of a return (...) spread over lines
+	if t.exit != "" && t.depth > 0 {
+		t.depth += bracketDepth(trimmed)
+		if t.depth < 0 {
+			t.exit = ""
+		}
+		return "", 0
+	}
+
+	if t.exit != "" {
+		if len(leadingSpace(line)) == t.indent && !t.reachableRe.MatchString(trimmed) {
+			exit, exitLine = t.exit, t.line
+		}
+		t.exit = ""
+	}
+
+	if m := t.exitRe.FindStringSubmatch(trimmed); m != nil && t.complete(trimmed) {
+		t.exit, t.line, t.indent = m[1], lineNum, len(leadingSpace(line))
+		t.depth = bracketDepth(trimmed)
+	}
+	return exit, exitLine
+}
+
+// complete reports whether an exit line is a statement this tracker can
+// follow: no open multi-line string or trailing continuation, and in JS
+// either ended by a semicolon or left open by a bracket
+func (t *unreachableTracker) complete(trimmed string) bool {
+	if strings.Count(trimmed, `"""`)%2 == 1 || strings.Count(trimmed, `'''`)%2 == 1 || strings.Count(trimmed, "`")%2 == 1 {
+		return false
+	}
+	if strings.HasSuffix(trimmed, `\`) || strings.HasSuffix(trimmed, ":") {
+		return false
+	}
+	depth := bracketDepth(trimmed)
+	if depth < 0 {
+		return false // return x; } closes the block itself
+	}
+	if t.js {
+		return strings.HasSuffix(trimmed, ";") || depth > 0 || t.exitRe.FindString(trimmed) == trimmed
+	}
+	return true
+}
+
+// bracketDepth counts the brackets a line opens minus those it closes
+func bracketDepth(s string) int {
+	return strings.Count(s, "(") + strings.Count(s, "[") + strings.Count(s, "{") -
+		strings.Count(s, ")") - strings.Count(s, "]") - strings.Count(s, "}")
+}
@@ -0,0 +1,109 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// walkSource walks dir like filepath.Walk, handling symlinks by
+// project.follow_symlinks. Followed, a symlinked directory is walked as if
+// it were under the link, and a symlinked file is passed with its target's
+// info. A link to a directory already being walked, including one that
+// loops back to an ancestor, is skipped. Unfollowed, links to directories
+// and checked files are skipped. skipped (if not nil) hears about each.
+func walkSource(dir string, cfg *config.Config, skipped func(path, reason string), fn filepath.WalkFunc) error {
+	w := &sourceWalker{cfg: cfg, skipped: skipped, fn: fn}
+	if real, err := realPath(dir); err == nil {
+		w.roots = append(w.roots, real)
+	}
+	return filepath.Walk(dir, w.visit)
+}
+
+type sourceWalker struct {
+	cfg     *config.Config
+	skipped func(path, reason string)
+	fn      filepath.WalkFunc
+
+	// Real paths of the trees walked so far: the root and each followed
+	// link's target. Directories have no hard links, so a real path
+	// identifies a directory the way its inode would.
+	roots []string
+}
+
+func (w *sourceWalker) visit(path string, info os.FileInfo, err error) error {
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return w.fn(path, info, err)
+	}
+
+	// os.Stat follows the link but keeps its name, so excluded directory
+	// names still apply to links
+	target, statErr := os.Stat(path)
+	if !w.cfg.Project.FollowSymlinks {
+		if statErr == nil && (target.IsDir() || IsCheckedFile(path, w.cfg)) {
+			w.skip(path, "symlink not followed (use --follow-symlinks to check it)")
+		}
+		return nil
+	}
+	if statErr != nil {
+		w.skip(path, "broken symlink")
+		return nil
+	}
+	if !target.IsDir() {
+		return w.fn(path, target, nil)
+	}
+
+	real, err := realPath(path)
+	if err != nil {
+		w.skip(path, "broken symlink")
+		return nil
+	}
+	if w.walked(real) {
+		w.skip(path, "symlink to a directory that's already checked")
+		return nil
+	}
+
+	// The link is a file to the outer walk, where SkipDir would skip the
+	// rest of its parent directory
+	if err := w.fn(path, target, nil); err != nil {
+		if err == filepath.SkipDir {
+			return nil
+		}
+		return err
+	}
+	w.roots = append(w.roots, real)
+	return filepath.Walk(real, func(p string, info os.FileInfo, err error) error {
+		if p == real {
+			return nil // Visited above, under the link's name
+		}
+		rel, _ := filepath.Rel(real, p)
+		return w.visit(filepath.Join(path, rel), info, err)
+	})
+}
+
+// realPath is the absolute path of p with every symlink resolved
+func realPath(p string) (string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
+// walked reports whether real is, or is inside, a tree already walked
+func (w *sourceWalker) walked(real string) bool {
+	for _, root := range w.roots {
+		if real == root || strings.HasPrefix(real, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *sourceWalker) skip(path, reason string) {
+	if w.skipped != nil {
+		w.skipped(path, reason)
+	}
+}
@@ -0,0 +1,114 @@
+package checks
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// Watcher notices which checked files changed between polls and re-checks
+// them, optionally applying the safe fixes first
+type Watcher struct {
+	Dir    string
+	Cfg    *config.Config
+	Fix    bool // Apply FixableRules fixes to changed files
+	DryRun bool // With Fix, only work out each fix's diff; files aren't written
+
+	seen map[string][sha256.Size]byte // relPath -> content hash
+}
+
+// WatchResult is what handling one changed file did
+type WatchResult struct {
+	File   string
+	Fixes  []Fix
+	Diff   string // Unified diff of Fixes, set in DryRun mode
+	Issues []Issue
+	Err    error
+}
+
+// NewWatcher records the current state of dir, so only later edits count
+func NewWatcher(dir string, cfg *config.Config, fix bool) *Watcher {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	w := &Watcher{Dir: dir, Cfg: cfg, Fix: fix}
+	w.seen = w.snapshot()
+	return w
+}
+
+// snapshot hashes every checked file under Dir
+func (w *Watcher) snapshot() map[string][sha256.Size]byte {
+	hashes := make(map[string][sha256.Size]byte)
+	filepath.Walk(w.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if excludedDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !IsCheckedFile(path, w.Cfg) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		relPath, _ := filepath.Rel(w.Dir, path)
+		hashes[filepath.ToSlash(relPath)] = sha256.Sum256(content)
+		return nil
+	})
+	return hashes
+}
+
+// Changed returns the files added or edited since the last call, sorted
+func (w *Watcher) Changed() []string {
+	current := w.snapshot()
+	var changed []string
+	for relPath, hash := range current {
+		if prev, ok := w.seen[relPath]; !ok || prev != hash {
+			changed = append(changed, relPath)
+		}
+	}
+	w.seen = current
+	sort.Strings(changed)
+	return changed
+}
+
+// Handle fixes (with Fix set) and re-checks one changed file. The hash of
+// the fixed file is recorded, so the watcher's own write isn't seen as a
+// new change and fixed again on the next poll. In DryRun mode the fixes
+// are only previewed, so the issues they'd remove are still reported.
+func (w *Watcher) Handle(relPath string) WatchResult {
+	result := WatchResult{File: relPath}
+	path := filepath.Join(w.Dir, relPath)
+
+	// Fix by the config the checks use for this file, .guardian.toml
+	// overrides and strict paths included, so a rule turned off there
+	// isn't fixed either. Resolved per call to pick up edited overrides.
+	var strict strictConfigs
+	fileCfg := strict.For(relPath, config.NewOverrides(w.Dir, w.Cfg).For(relPath))
+
+	if w.Fix && w.DryRun {
+		result.Fixes, result.Diff, result.Err = PreviewFix(path, relPath, fileCfg)
+		if result.Err != nil {
+			return result
+		}
+	} else if w.Fix {
+		result.Fixes, result.Err = FixFile(path, relPath, fileCfg)
+		if result.Err != nil {
+			return result
+		}
+		if content, err := os.ReadFile(path); err == nil {
+			w.seen[relPath] = sha256.Sum256(content)
+		}
+	}
+
+	result.Issues = RunFilesWithConfig(w.Dir, []string{relPath}, w.Cfg)
+	return result
+}
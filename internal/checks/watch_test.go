@@ -0,0 +1,109 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+func TestWatcher_FixOnSave(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.py")
+	os.WriteFile(path, []byte("x = 1\n"), 0644)
+
+	w := NewWatcher(dir, config.DefaultConfig(), true)
+	if changed := w.Changed(); len(changed) != 0 {
+		t.Fatalf("nothing saved yet, got %v", changed)
+	}
+
+	// Save a file with a print in it
+	os.WriteFile(path, []byte("x = 1\nprint(x)\n"), 0644)
+	changed := w.Changed()
+	if len(changed) != 1 || changed[0] != "app.py" {
+		t.Fatalf("expected app.py to change, got %v", changed)
+	}
+
+	result := w.Handle("app.py")
+	if result.Err != nil {
+		t.Fatalf("handle failed: %v", result.Err)
+	}
+	if len(result.Fixes) != 1 || result.Fixes[0].Rule != "ban-print" {
+		t.Errorf("expected one ban-print fix, got %+v", result.Fixes)
+	}
+	assertNoRule(t, result.Issues, "ban-print", "fixed before re-checking")
+
+	content, _ := os.ReadFile(path)
+	if strings.Contains(string(content), "print(") {
+		t.Errorf("print() should be removed, file is:\n%s", content)
+	}
+
+	// The watcher's own write isn't a new change
+	if changed := w.Changed(); len(changed) != 0 {
+		t.Errorf("fix should not retrigger the watcher, got %v", changed)
+	}
+}
+
+func TestWatcher_NoFixOnlyChecks(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWatcher(dir, config.DefaultConfig(), false)
+
+	path := filepath.Join(dir, "app.py")
+	os.WriteFile(path, []byte("print(1)\n"), 0644)
+	changed := w.Changed()
+	if len(changed) != 1 {
+		t.Fatalf("expected a new file to count as changed, got %v", changed)
+	}
+
+	result := w.Handle(changed[0])
+	assertHasRule(t, result.Issues, "ban-print", "reported, not fixed")
+	if content, _ := os.ReadFile(path); string(content) != "print(1)\n" {
+		t.Errorf("file should be untouched without fix, got:\n%s", content)
+	}
+}
+
+func TestWatcher_DryRunOnlyDiffs(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWatcher(dir, config.DefaultConfig(), true)
+	w.DryRun = true
+
+	path := filepath.Join(dir, "app.js")
+	os.WriteFile(path, []byte("run();\nconsole.log(state);\n"), 0644)
+	result := w.Handle(w.Changed()[0])
+	if result.Err != nil {
+		t.Fatalf("handle failed: %v", result.Err)
+	}
+	if len(result.Fixes) != 1 || !strings.Contains(result.Diff, "-console.log(state);\n") {
+		t.Errorf("expected a ban-console fix and its diff, got %+v\n%s", result.Fixes, result.Diff)
+	}
+	assertHasRule(t, result.Issues, "ban-console", "still there after a dry run")
+	if content, _ := os.ReadFile(path); string(content) != "run();\nconsole.log(state);\n" {
+		t.Errorf("dry run should not write, got:\n%s", content)
+	}
+}
+
+func TestWatcher_FixHonoursOverrides(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "scripts"), 0755)
+	os.WriteFile(filepath.Join(dir, "scripts", config.OverrideFile), []byte("[quality]\nban_print = false\n"), 0644)
+	path := filepath.Join(dir, "scripts", "a.py")
+	os.WriteFile(path, []byte("x = 1\n"), 0644)
+
+	w := NewWatcher(dir, config.DefaultConfig(), true)
+	os.WriteFile(path, []byte("x = 1\nprint(x)\n"), 0644)
+	w.Changed()
+
+	result := w.Handle("scripts/a.py")
+	if result.Err != nil {
+		t.Fatalf("handle failed: %v", result.Err)
+	}
+	if len(result.Fixes) != 0 {
+		t.Errorf("ban-print is off for scripts/, expected no fixes, got %+v", result.Fixes)
+	}
+	assertNoRule(t, result.Issues, "ban-print", "disabled by the override")
+	if content, _ := os.ReadFile(path); !strings.Contains(string(content), "print(x)") {
+		t.Errorf("print() should be kept, file is:\n%s", content)
+	}
+}
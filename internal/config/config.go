@@ -1,51 +1,227 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Config represents the guardian configuration
 type Config struct {
-	Project  ProjectConfig  `toml:"project"`
-	Limits   LimitsConfig   `toml:"limits"`
-	Quality  QualityConfig  `toml:"quality"`
-	Security SecurityConfig `toml:"security"`
+	Project  ProjectConfig  `toml:"project" yaml:"project" json:"project"`
+	Limits   LimitsConfig   `toml:"limits" yaml:"limits" json:"limits"`
+	Quality  QualityConfig  `toml:"quality" yaml:"quality" json:"quality"`
+	Security SecurityConfig `toml:"security" yaml:"security" json:"security"`
+	Rules    RulesConfig    `toml:"rules" yaml:"rules" json:"rules"`
+	// Messages maps a rule id to a template that replaces its built-in message.
+	// Placeholders: {file}, {line}, {rule}, {message} (the built-in text).
+	Messages    map[string]string `toml:"messages" yaml:"messages" json:"messages"`
+	CustomRules []CustomRule      `toml:"custom_rules" yaml:"custom_rules" json:"custom_rules"`
+	Snooze      []Snooze          `toml:"snooze" yaml:"snooze" json:"snooze"`
+	// Languages tunes each language ("python", "js", "go", "shell"): extra
+	// extensions to check as it, and which rules run on its files
+	Languages map[string]LanguageConfig `toml:"languages" yaml:"languages" json:"languages"`
+	Strict    StrictConfig              `toml:"strict" yaml:"strict" json:"strict"`
+}
+
+// StrictConfig is the [strict] table: paths held to a higher standard, such
+// as auth/ or payments/
+type StrictConfig struct {
+	Paths []string `toml:"paths" yaml:"paths" json:"paths"` // Globs as in custom_file_limits; a plain name covers a whole directory. Issues there are raised one severity level.
+	Rules []string `toml:"rules" yaml:"rules" json:"rules"` // Rules to turn on under those paths only
+}
+
+// LanguageConfig is the [languages.<name>] table
+type LanguageConfig struct {
+	Extensions []string `toml:"extensions" yaml:"extensions" json:"extensions"` // Checked as this language, like include_ext
+	Rules      []string `toml:"rules" yaml:"rules" json:"rules"`                // Rule ids or groups ("quality", "security") to run; empty means all
+}
+
+// CustomRule is a user-defined rule that flags lines matching a regex
+type CustomRule struct {
+	ID        string   `toml:"id" yaml:"id" json:"id"`
+	Pattern   string   `toml:"pattern" yaml:"pattern" json:"pattern"` // Go regexp syntax, matched per line
+	Message   string   `toml:"message" yaml:"message" json:"message"`
+	Severity  string   `toml:"severity" yaml:"severity" json:"severity"`    // "critical", "warning" (default) or "info"
+	Languages []string `toml:"languages" yaml:"languages" json:"languages"` // "python", "js", "go", "shell"; empty means all
+	Scope     string   `toml:"scope" yaml:"scope" json:"scope"`             // "code", "comment" or "string"; empty matches the whole line
 }
 
 // ProjectConfig holds project settings
 type ProjectConfig struct {
-	SrcRoot     string   `toml:"src_root"`
-	ExcludeDirs []string `toml:"exclude_dirs"`
+	SrcRoot     string            `toml:"src_root" yaml:"src_root" json:"src_root"`
+	ExcludeDirs []string          `toml:"exclude_dirs" yaml:"exclude_dirs" json:"exclude_dirs"`
+	IncludeExt  map[string]string `toml:"include_ext" yaml:"include_ext" json:"include_ext"`       // Extra extension -> language ("python", "js", "go", "shell")
+	Encoding    string            `toml:"encoding" yaml:"encoding" json:"encoding"`                // "utf-8" (default) or "latin-1" for files that aren't valid UTF-8
+	DocsBaseURL string            `toml:"docs_base_url" yaml:"docs_base_url" json:"docs_base_url"` // Rule docs live at <docs_base_url>/<rule>; "" hides the links
+	// FollowSymlinks checks what symlinked files and directories point to.
+	// Off, they're skipped and listed on stderr.
+	FollowSymlinks bool `toml:"follow_symlinks" yaml:"follow_symlinks" json:"follow_symlinks"`
 }
 
 // LimitsConfig holds size limits
 type LimitsConfig struct {
-	MaxFileLines       int            `toml:"max_file_lines"`
-	MaxFunctionLines   int            `toml:"max_function_lines"`
-	CustomFileLimits   map[string]int `toml:"custom_file_limits"`
+	MaxFileLines     int            `toml:"max_file_lines" yaml:"max_file_lines" json:"max_file_lines"`
+	MaxFunctionLines int            `toml:"max_function_lines" yaml:"max_function_lines" json:"max_function_lines"`
+	CustomFileLimits map[string]int `toml:"custom_file_limits" yaml:"custom_file_limits" json:"custom_file_limits"`
+	MaxFileBytes     int64          `toml:"max_file_bytes" yaml:"max_file_bytes" json:"max_file_bytes"` // Any file type; 0 disables
+	MaxScanBytes     int64          `toml:"max_scan_bytes" yaml:"max_scan_bytes" json:"max_scan_bytes"` // Source files above this aren't read at all; 0 disables
+	Concurrency      int            `toml:"concurrency" yaml:"concurrency" json:"concurrency"`          // Files checked in parallel; 0 means one per CPU
 }
 
 // QualityConfig holds quality rules
 type QualityConfig struct {
-	BanPrint           bool     `toml:"ban_print"`
-	BanBareExcept      bool     `toml:"ban_bare_except"`
-	BanMutableDefaults bool     `toml:"ban_mutable_defaults"`
-	BanStarImports     bool     `toml:"ban_star_imports"`
-	BanTodoMarkers     bool     `toml:"ban_todo_markers"`
-	BanMockData        bool     `toml:"ban_mock_data"`
-	MockPatterns       []string `toml:"mock_patterns"`
+	BanPrint               bool     `toml:"ban_print" yaml:"ban_print" json:"ban_print"`
+	BanBareExcept          bool     `toml:"ban_bare_except" yaml:"ban_bare_except" json:"ban_bare_except"`
+	BanMutableDefaults     bool     `toml:"ban_mutable_defaults" yaml:"ban_mutable_defaults" json:"ban_mutable_defaults"`
+	BanStarImports         bool     `toml:"ban_star_imports" yaml:"ban_star_imports" json:"ban_star_imports"`
+	BanTodoMarkers         bool     `toml:"ban_todo_markers" yaml:"ban_todo_markers" json:"ban_todo_markers"`
+	BanMockData            bool     `toml:"ban_mock_data" yaml:"ban_mock_data" json:"ban_mock_data"`
+	MockPatterns           []string `toml:"mock_patterns" yaml:"mock_patterns" json:"mock_patterns"`
+	BanHardcodedPaths      bool     `toml:"ban_hardcoded_paths" yaml:"ban_hardcoded_paths" json:"ban_hardcoded_paths"`
+	RequireTimeouts        bool     `toml:"require_timeouts" yaml:"require_timeouts" json:"require_timeouts"`
+	BanLibraryPanic        bool     `toml:"ban_library_panic" yaml:"ban_library_panic" json:"ban_library_panic"`    // Go: panic() outside package main
+	BanIgnoredErrors       bool     `toml:"ban_ignored_errors" yaml:"ban_ignored_errors" json:"ban_ignored_errors"` // Go: _ = err
+	BanLogAndIgnore        bool     `toml:"ban_log_and_ignore" yaml:"ban_log_and_ignore" json:"ban_log_and_ignore"` // except/catch that only logs at debug level
+	BanBlockingInAsync     bool     `toml:"ban_blocking_in_async" yaml:"ban_blocking_in_async" json:"ban_blocking_in_async"`
+	BanUnawaitedAsync      bool     `toml:"ban_unawaited_async" yaml:"ban_unawaited_async" json:"ban_unawaited_async"`    // Calls to this file's async functions without await
+	BlockingCalls          []string `toml:"blocking_calls" yaml:"blocking_calls" json:"blocking_calls"`                   // Calls blocking-in-async flags inside async functions
+	BanUnreachableCode     bool     `toml:"ban_unreachable_code" yaml:"ban_unreachable_code" json:"ban_unreachable_code"` // Statements after return/raise/throw in the same block
+	BanCommentedCode       bool     `toml:"ban_commented_code" yaml:"ban_commented_code" json:"ban_commented_code"`
+	CommentedCodeMinLines  int      `toml:"commented_code_min_lines" yaml:"commented_code_min_lines" json:"commented_code_min_lines"` // Consecutive code-like comment lines before flagging
+	BanSuppressionComments bool     `toml:"ban_suppression_comments" yaml:"ban_suppression_comments" json:"ban_suppression_comments"` // # type: ignore, # noqa, @ts-ignore, eslint-disable
+	BanStubImplementations bool     `toml:"ban_stub_implementations" yaml:"ban_stub_implementations" json:"ban_stub_implementations"` // Functions that are only pass / raise NotImplementedError / throw "not implemented"
+	MaxSuppressionComments int      `toml:"max_suppression_comments" yaml:"max_suppression_comments" json:"max_suppression_comments"` // Suppressions a file may have before each is flagged; 0 flags every one
+	TestFileRules          []string `toml:"test_file_rules" yaml:"test_file_rules" json:"test_file_rules"`                            // Rules relaxed inside test files
+	TestFileMode           string   `toml:"test_file_mode" yaml:"test_file_mode" json:"test_file_mode"`                               // "skip", "downgrade" or "report"
 }
 
 // SecurityConfig holds security rules
 type SecurityConfig struct {
-	BanEvalExec          bool     `toml:"ban_eval_exec"`
-	BanSubprocessShell   bool     `toml:"ban_subprocess_shell"`
-	BanDangerousCommands bool     `toml:"ban_dangerous_commands"`
-	DangerousPatterns    []string `toml:"dangerous_patterns"`
-	SecretPatterns       []string `toml:"secret_patterns"`
+	BanEvalExec                bool     `toml:"ban_eval_exec" yaml:"ban_eval_exec" json:"ban_eval_exec"`
+	EvalAllowlist              []string `toml:"eval_allowlist" yaml:"eval_allowlist" json:"eval_allowlist"` // Safe eval-like calls ban-eval ignores, e.g. "ast.literal_eval"
+	BanSubprocessShell         bool     `toml:"ban_subprocess_shell" yaml:"ban_subprocess_shell" json:"ban_subprocess_shell"`
+	BanStaticShellCommands     bool     `toml:"ban_static_shell_commands" yaml:"ban_static_shell_commands" json:"ban_static_shell_commands"` // Also flag ["bash", "-c", "ls"] with a constant command
+	BanDangerousCommands       bool     `toml:"ban_dangerous_commands" yaml:"ban_dangerous_commands" json:"ban_dangerous_commands"`
+	DangerousPatterns          []string `toml:"dangerous_patterns" yaml:"dangerous_patterns" json:"dangerous_patterns"`
+	SecretPatterns             []string `toml:"secret_patterns" yaml:"secret_patterns" json:"secret_patterns"`
+	BanAssertValidation        bool     `toml:"ban_assert_validation" yaml:"ban_assert_validation" json:"ban_assert_validation"` // assert is stripped under python -O
+	BanWildcardCORS            bool     `toml:"ban_wildcard_cors" yaml:"ban_wildcard_cors" json:"ban_wildcard_cors"`
+	BanInsecureTLS             bool     `toml:"ban_insecure_tls" yaml:"ban_insecure_tls" json:"ban_insecure_tls"`
+	BanInsecureDeserialization bool     `toml:"ban_insecure_deserialization" yaml:"ban_insecure_deserialization" json:"ban_insecure_deserialization"` // pickle, marshal, yaml.load
+	BanCurlPipeShell           bool     `toml:"ban_curl_pipe_sh" yaml:"ban_curl_pipe_sh" json:"ban_curl_pipe_sh"`                                     // Shell scripts and Dockerfiles
+	BanRemoteExec              bool     `toml:"ban_remote_exec" yaml:"ban_remote_exec" json:"ban_remote_exec"`                                        // eval "$(curl ...)", chmod +x on unverified downloads
+	BanPIILogging              bool     `toml:"ban_pii_logging" yaml:"ban_pii_logging" json:"ban_pii_logging"`
+	PIIFields                  []string `toml:"pii_fields" yaml:"pii_fields" json:"pii_fields"`                                     // Field names pii-logging looks for; credit_card also matches creditCard
+	BanUnprotectedRoutes       bool     `toml:"ban_unprotected_routes" yaml:"ban_unprotected_routes" json:"ban_unprotected_routes"` // FastAPI/Flask routes with no auth
+	AuthMarkers                []string `toml:"auth_markers" yaml:"auth_markers" json:"auth_markers"`                               // Decorator or signature text that counts as auth
+	BanPermissiveChmod         bool     `toml:"ban_permissive_chmod" yaml:"ban_permissive_chmod" json:"ban_permissive_chmod"`
+	MaxChmodMode               int      `toml:"max_chmod_mode" yaml:"max_chmod_mode" json:"max_chmod_mode"`                                  // Loosest mode chmod may set, e.g. 0o755; JSON needs decimal (493)
+	BanUnpinnedDependencies    bool     `toml:"ban_unpinned_dependencies" yaml:"ban_unpinned_dependencies" json:"ban_unpinned_dependencies"` // requirements*.txt entries without ==
+	BanURLDependencies         bool     `toml:"ban_url_dependencies" yaml:"ban_url_dependencies" json:"ban_url_dependencies"`                // package.json deps on git, URLs or local paths
+}
+
+// RulesConfig holds per-rule settings that apply to every rule by name
+type RulesConfig struct {
+	Disabled []string `toml:"disabled" yaml:"disabled" json:"disabled"`
+	File     string   `toml:"file" yaml:"file" json:"file"` // Shared [[custom_rules]] file, relative to the config
+}
+
+// ruleFlags maps rule names to the toggle that controls them
+func (c *Config) ruleFlags() map[string]*bool {
+	return map[string]*bool{
+		"ban-print":                &c.Quality.BanPrint,
+		"ban-except":               &c.Quality.BanBareExcept,
+		"ban-star":                 &c.Quality.BanStarImports,
+		"mutable-default":          &c.Quality.BanMutableDefaults,
+		"todo-marker":              &c.Quality.BanTodoMarkers,
+		"mock-data":                &c.Quality.BanMockData,
+		"hardcoded-path":           &c.Quality.BanHardcodedPaths,
+		"no-timeout":               &c.Quality.RequireTimeouts,
+		"library-panic":            &c.Quality.BanLibraryPanic,
+		"ignored-error":            &c.Quality.BanIgnoredErrors,
+		"commented-code":           &c.Quality.BanCommentedCode,
+		"unreachable-code":         &c.Quality.BanUnreachableCode,
+		"suppression-comment":      &c.Quality.BanSuppressionComments,
+		"stub-implementation":      &c.Quality.BanStubImplementations,
+		"log-and-ignore":           &c.Quality.BanLogAndIgnore,
+		"blocking-in-async":        &c.Quality.BanBlockingInAsync,
+		"unawaited-async":          &c.Quality.BanUnawaitedAsync,
+		"ban-eval":                 &c.Security.BanEvalExec,
+		"subprocess-shell":         &c.Security.BanSubprocessShell,
+		"assert-validation":        &c.Security.BanAssertValidation,
+		"insecure-cors":            &c.Security.BanWildcardCORS,
+		"insecure-tls":             &c.Security.BanInsecureTLS,
+		"insecure-deserialization": &c.Security.BanInsecureDeserialization,
+		"curl-pipe-sh":             &c.Security.BanCurlPipeShell,
+		"remote-exec":              &c.Security.BanRemoteExec,
+		"pii-logging":              &c.Security.BanPIILogging,
+		"unprotected-route":        &c.Security.BanUnprotectedRoutes,
+		"permissive-chmod":         &c.Security.BanPermissiveChmod,
+		"unpinned-dependency":      &c.Security.BanUnpinnedDependencies,
+		"url-dependency":           &c.Security.BanURLDependencies,
+		"dangerous-cmd":            &c.Security.BanDangerousCommands,
+	}
+}
+
+// DisableRule turns a rule off - by flipping its toggle when it has one,
+// otherwise by adding it to rules.disabled
+func (c *Config) DisableRule(rule string) {
+	if flag, ok := c.ruleFlags()[rule]; ok {
+		*flag = false
+		return
+	}
+	if !c.IsRuleDisabled(rule) {
+		c.Rules.Disabled = append(c.Rules.Disabled, rule)
+	}
+}
+
+// EnableRule turns a rule back on - its toggle if it has one, and removes it
+// from rules.disabled
+func (c *Config) EnableRule(rule string) {
+	if flag, ok := c.ruleFlags()[rule]; ok {
+		*flag = true
+	}
+	kept := c.Rules.Disabled[:0]
+	for _, r := range c.Rules.Disabled {
+		if r != rule {
+			kept = append(kept, r)
+		}
+	}
+	c.Rules.Disabled = kept
+}
+
+// RuleEnabled reports whether rule will run: its toggle, if it has one, is
+// on and it isn't listed in rules.disabled
+func (c *Config) RuleEnabled(rule string) bool {
+	toggle := rule
+	if rule == "ban-console" {
+		// console.log shares ban_print
+		toggle = "ban-print"
+	}
+	if flag, ok := c.ruleFlags()[toggle]; ok && !*flag {
+		return false
+	}
+	return !c.IsRuleDisabled(rule)
+}
+
+// IsRuleDisabled reports whether rule is listed in rules.disabled or
+// snoozed
+func (c *Config) IsRuleDisabled(rule string) bool {
+	for _, r := range c.Rules.Disabled {
+		if r == rule {
+			return true
+		}
+	}
+	return c.IsRuleSnoozed(rule)
 }
 
 // DefaultConfig returns a config with sensible defaults
@@ -54,11 +230,14 @@ func DefaultConfig() *Config {
 		Project: ProjectConfig{
 			SrcRoot:     "src",
 			ExcludeDirs: []string{"tests", "__pycache__", "node_modules", ".venv", "venv"},
+			DocsBaseURL: "https://guardian.sh/rules",
 		},
 		Limits: LimitsConfig{
 			MaxFileLines:     500,
 			MaxFunctionLines: 50,
 			CustomFileLimits: make(map[string]int),
+			MaxFileBytes:     5 * 1024 * 1024,
+			MaxScanBytes:     10 * 1024 * 1024,
 		},
 		Quality: QualityConfig{
 			BanPrint:           true,
@@ -75,11 +254,46 @@ func DefaultConfig() *Config {
 				"changeme", "replace_me", "your_", "xxx",
 				"lorem ipsum", "foo_bar", "asdf",
 			},
+			BanHardcodedPaths:      true,
+			RequireTimeouts:        true,
+			BanLibraryPanic:        true,
+			BanIgnoredErrors:       true,
+			BanLogAndIgnore:        true,
+			BanBlockingInAsync:     true,
+			BanUnawaitedAsync:      true,
+			BanUnreachableCode:     true,
+			BanCommentedCode:       true,
+			CommentedCodeMinLines:  4,
+			BanSuppressionComments: true,
+			BanStubImplementations: true,
+			TestFileRules:          []string{"mock-data"},
+			TestFileMode:           "skip",
+			BlockingCalls: []string{
+				"time.sleep", "open", "input",
+				"requests.get", "requests.post", "requests.put", "requests.patch", "requests.delete", "requests.request",
+				"urllib.request.urlopen", "subprocess.run", "subprocess.call", "subprocess.check_output",
+				"fs.readFileSync", "fs.writeFileSync", "fs.appendFileSync", "fs.existsSync", "fs.readdirSync",
+				"execSync", "spawnSync",
+			},
 		},
 		Security: SecurityConfig{
-			BanEvalExec:          true,
-			BanSubprocessShell:   true,
-			BanDangerousCommands: true,
+			BanEvalExec:                true,
+			BanSubprocessShell:         true,
+			BanDangerousCommands:       true,
+			BanAssertValidation:        true,
+			BanWildcardCORS:            true,
+			BanInsecureTLS:             true,
+			BanInsecureDeserialization: true,
+			BanCurlPipeShell:           true,
+			BanRemoteExec:              true,
+			BanPIILogging:              true,
+			BanUnprotectedRoutes:       true,
+			BanPermissiveChmod:         true,
+			MaxChmodMode:               0o755,
+			BanUnpinnedDependencies:    true,
+			BanURLDependencies:         true,
+			EvalAllowlist:              []string{"ast.literal_eval", "literal_eval"},
+			PIIFields:                  []string{"email", "ssn", "phone", "credit_card", "dob"},
 			DangerousPatterns: []string{
 				"rm -rf",
 				"DROP TABLE",
@@ -93,13 +307,135 @@ func DefaultConfig() *Config {
 				"private_key", "privatekey",
 				"access_token", "auth_token",
 			},
+			AuthMarkers: []string{
+				"Depends(", "Security(",
+				"login_required", "jwt_required", "auth_required",
+				"permission_required", "requires_auth", "roles_required",
+			},
 		},
 	}
 }
 
-// Load loads configuration from guardian_config.toml
+// FileNames lists the supported config files in precedence order - when
+// several exist, the first one found wins
+var FileNames = []string{
+	"guardian_config.toml",
+	"guardian_config.yaml",
+	"guardian_config.yml",
+	"guardian_config.json",
+}
+
+// Load loads configuration from the first config file found in dir
+// (see FileNames), falling back to defaults when there is none, merges in the
+// shared rules from rules.file if set, then applies GUARDIAN_* environment
+// overrides. Precedence is env > file > defaults.
 func Load(dir string) (*Config, error) {
-	configPath := filepath.Join(dir, "guardian_config.toml")
+	config, err := LoadFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if config.Rules.File != "" {
+		path := config.Rules.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		rules, err := LoadRulesFile(path)
+		if err != nil {
+			return nil, err
+		}
+		config.MergeCustomRules(rules)
+	}
+	if err := config.ApplyEnv(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Sources lists what Load(dir) merged into c, in the order it was applied:
+// the defaults, the config file, the rules file and each GUARDIAN_*
+// variable that is set
+func Sources(dir string, c *Config) []string {
+	sources := []string{"defaults"}
+	if Exists(dir) {
+		sources = append(sources, GetConfigPath(dir))
+	}
+	if c.Rules.File != "" {
+		sources = append(sources, "rules.file "+c.Rules.File)
+	}
+	for _, name := range EnvVars {
+		if s := strings.TrimSpace(os.Getenv(name)); s != "" {
+			sources = append(sources, name+"="+s)
+		}
+	}
+	return sources
+}
+
+// Dump encodes the config as "toml" or "json", for showing the effective
+// settings rather than saving them
+func Dump(c *Config, format string) ([]byte, error) {
+	switch format {
+	case "toml":
+		return toml.Marshal(c)
+	case "json":
+		return json.MarshalIndent(c, "", "  ")
+	}
+	return nil, fmt.Errorf("unsupported format %q (use toml or json)", format)
+}
+
+// validate checks the settings Load can't leave to the checks to ignore
+func (c *Config) validate() error {
+	if err := validateCustomRules(c.CustomRules); err != nil {
+		return err
+	}
+	if err := validateEncoding(c.Project.Encoding); err != nil {
+		return err
+	}
+	if err := validateTestFileMode(c.Quality.TestFileMode); err != nil {
+		return err
+	}
+	if err := validateSnoozes(c.Snooze); err != nil {
+		return err
+	}
+	return validateLanguages(c.Languages)
+}
+
+// FindNearest walks up from dir to the filesystem root and returns the first
+// directory containing a config file. ok is false when there is none.
+func FindNearest(dir string) (found string, ok bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		if Exists(abs) {
+			return abs, true
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", false
+		}
+		abs = parent
+	}
+}
+
+// LoadNearest loads the config that applies to dir: the nearest one found by
+// FindNearest, or defaults when there is none. Environment overrides apply
+// either way, as with Load.
+func LoadNearest(dir string) (*Config, error) {
+	if found, ok := FindNearest(dir); ok {
+		return Load(found)
+	}
+	return Load(dir)
+}
+
+// LoadFile loads the config file over the defaults without any environment
+// overrides. Use it when the result will be saved back, so a one-off
+// GUARDIAN_* variable doesn't end up written to disk.
+func LoadFile(dir string) (*Config, error) {
+	configPath := GetConfigPath(dir)
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -111,18 +447,183 @@ func Load(dir string) (*Config, error) {
 	}
 
 	config := DefaultConfig()
-	if err := toml.Unmarshal(data, config); err != nil {
+	if err := unmarshal(configPath, data, config); err != nil {
 		return nil, err
 	}
 
 	return config, nil
 }
 
-// Save saves configuration to guardian_config.toml
+// LoadRulesFile reads the [[custom_rules]] from a standalone rules file, in
+// any of the config formats. Everything else in the file is ignored.
+func LoadRulesFile(path string) ([]CustomRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rulesFile Config
+	if err := unmarshal(path, data, &rulesFile); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := validateCustomRules(rulesFile.CustomRules); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rulesFile.CustomRules, nil
+}
+
+// MergeCustomRules adds rules from a rules file. A rule whose id is already
+// defined is skipped, so a repo can override a shared rule by redefining it.
+func (c *Config) MergeCustomRules(rules []CustomRule) {
+	defined := make(map[string]bool)
+	for _, rule := range c.CustomRules {
+		defined[rule.ID] = true
+	}
+	for _, rule := range rules {
+		if !defined[rule.ID] {
+			defined[rule.ID] = true
+			c.CustomRules = append(c.CustomRules, rule)
+		}
+	}
+}
+
+// CustomRuleScopes are the parts of a line custom_rules.scope can limit a
+// rule to
+var CustomRuleScopes = []string{"code", "comment", "string"}
+
+// validateCustomRules checks that each rule has an id and a valid pattern
+func validateCustomRules(rules []CustomRule) error {
+	for i, rule := range rules {
+		if rule.ID == "" {
+			return fmt.Errorf("custom_rules[%d]: missing id", i)
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil || rule.Pattern == "" {
+			return fmt.Errorf("custom rule %s: invalid pattern %q", rule.ID, rule.Pattern)
+		}
+		switch rule.Severity {
+		case "", "critical", "warning", "info":
+		default:
+			return fmt.Errorf("custom rule %s: invalid severity %q", rule.ID, rule.Severity)
+		}
+		if rule.Scope != "" && !slices.Contains(CustomRuleScopes, rule.Scope) {
+			return fmt.Errorf("custom rule %s: invalid scope %q (use code, comment or string)", rule.ID, rule.Scope)
+		}
+	}
+	return nil
+}
+
+// Languages are the names [languages.<name>] and custom_rules.languages take
+var Languages = []string{"python", "js", "go", "shell"}
+
+func validateLanguages(languages map[string]LanguageConfig) error {
+	for name, lc := range languages {
+		if !slices.Contains(Languages, name) {
+			return fmt.Errorf("languages.%s: unknown language (use python, js, go or shell)", name)
+		}
+		for _, ext := range lc.Extensions {
+			if strings.Trim(strings.TrimSpace(ext), ".") == "" {
+				return fmt.Errorf("languages.%s: empty extension", name)
+			}
+		}
+	}
+	return nil
+}
+
+// IsLatin1 reports whether encoding names ISO-8859-1
+func IsLatin1(encoding string) bool {
+	switch strings.ToLower(encoding) {
+	case "latin-1", "latin1", "iso-8859-1":
+		return true
+	}
+	return false
+}
+
+// validateEncoding checks project.encoding is one Guardian can read
+func validateEncoding(encoding string) error {
+	switch strings.ToLower(encoding) {
+	case "", "utf-8", "utf8":
+		return nil
+	}
+	if IsLatin1(encoding) {
+		return nil
+	}
+	return fmt.Errorf("project.encoding: unsupported encoding %q (use utf-8 or latin-1)", encoding)
+}
+
+// validateTestFileMode rejects a test_file_mode the checks wouldn't know,
+// which would otherwise quietly act as skip. Empty means the default, skip.
+func validateTestFileMode(mode string) error {
+	switch mode {
+	case "", "skip", "downgrade", "report":
+		return nil
+	}
+	return fmt.Errorf("quality.test_file_mode: unknown mode %q (use skip, downgrade or report)", mode)
+}
+
+// Environment variables that override config values
+const (
+	EnvMaxFileLines     = "GUARDIAN_MAX_FILE_LINES"
+	EnvMaxFunctionLines = "GUARDIAN_MAX_FUNCTION_LINES"
+	EnvMaxFileBytes     = "GUARDIAN_MAX_FILE_BYTES"
+	EnvEnable           = "GUARDIAN_ENABLE"  // Comma-separated rule names
+	EnvDisable          = "GUARDIAN_DISABLE" // Comma-separated rule names; wins over GUARDIAN_ENABLE
+)
+
+// EnvVars lists the environment variables ApplyEnv reads
+var EnvVars = []string{EnvMaxFileLines, EnvMaxFunctionLines, EnvMaxFileBytes, EnvEnable, EnvDisable}
+
+// ApplyEnv overlays GUARDIAN_* environment variables on the config
+func (c *Config) ApplyEnv() error {
+	ints := []struct {
+		name   string
+		target *int
+	}{
+		{EnvMaxFileLines, &c.Limits.MaxFileLines},
+		{EnvMaxFunctionLines, &c.Limits.MaxFunctionLines},
+	}
+	for _, v := range ints {
+		if s := strings.TrimSpace(os.Getenv(v.name)); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not a number", v.name, s)
+			}
+			*v.target = n
+		}
+	}
+
+	if s := strings.TrimSpace(os.Getenv(EnvMaxFileBytes)); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not a number", EnvMaxFileBytes, s)
+		}
+		c.Limits.MaxFileBytes = n
+	}
+
+	for _, rule := range splitList(os.Getenv(EnvEnable)) {
+		c.EnableRule(rule)
+	}
+	for _, rule := range splitList(os.Getenv(EnvDisable)) {
+		c.DisableRule(rule)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated env value, dropping blanks
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Save saves configuration to the project's config file, keeping its format
 func Save(dir string, config *Config) error {
-	configPath := filepath.Join(dir, "guardian_config.toml")
+	configPath := GetConfigPath(dir)
 
-	data, err := toml.Marshal(config)
+	data, err := marshal(configPath, config)
 	if err != nil {
 		return err
 	}
@@ -130,9 +631,40 @@ func Save(dir string, config *Config) error {
 	return os.WriteFile(configPath, data, 0644)
 }
 
-// GetConfigPath returns the path to the config file
+// unmarshal decodes data according to the file extension of path
+func unmarshal(path string, data []byte, config *Config) error {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(data, config)
+	case ".json":
+		return json.Unmarshal(data, config)
+	default:
+		return toml.Unmarshal(data, config)
+	}
+}
+
+// marshal encodes config according to the file extension of path
+func marshal(path string, config *Config) ([]byte, error) {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return yaml.Marshal(config)
+	case ".json":
+		return json.MarshalIndent(config, "", "  ")
+	default:
+		return toml.Marshal(config)
+	}
+}
+
+// GetConfigPath returns the path to the project's config file - the first
+// of FileNames that exists, or guardian_config.toml if none do
 func GetConfigPath(dir string) string {
-	return filepath.Join(dir, "guardian_config.toml")
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dir, FileNames[0])
 }
 
 // Exists checks if a config file exists
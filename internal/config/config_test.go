@@ -0,0 +1,513 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Helper to write a config file into a fresh temp dir and load it
+func loadFrom(t *testing.T, name, content string) *Config {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load(%s) failed: %v", name, err)
+	}
+	return cfg
+}
+
+// ============================================================================
+// FILE FORMATS
+// ============================================================================
+
+func TestLoad_YAMLMatchesTOML(t *testing.T) {
+	fromTOML := loadFrom(t, "guardian_config.toml", `
+[limits]
+max_file_lines = 800
+
+[limits.custom_file_limits]
+"app/models.py" = 1200
+`)
+	fromYAML := loadFrom(t, "guardian_config.yaml", `
+limits:
+  max_file_lines: 800
+  custom_file_limits:
+    app/models.py: 1200
+`)
+
+	if fromYAML.Limits.CustomFileLimits["app/models.py"] != 1200 {
+		t.Errorf("YAML custom limit not loaded: %v", fromYAML.Limits.CustomFileLimits)
+	}
+	if !reflect.DeepEqual(fromTOML, fromYAML) {
+		t.Errorf("YAML config differs from TOML equivalent:\ntoml: %+v\nyaml: %+v", fromTOML, fromYAML)
+	}
+}
+
+func TestLoad_JSONMatchesTOML(t *testing.T) {
+	fromTOML := loadFrom(t, "guardian_config.toml", `
+[quality]
+ban_print = false
+`)
+	fromJSON := loadFrom(t, "guardian_config.json", `{"quality": {"ban_print": false}}`)
+
+	if fromJSON.Quality.BanPrint {
+		t.Error("JSON config should disable ban_print")
+	}
+	if !fromJSON.Quality.BanBareExcept {
+		t.Error("JSON config should keep unspecified defaults")
+	}
+	if !reflect.DeepEqual(fromTOML, fromJSON) {
+		t.Errorf("JSON config differs from TOML equivalent:\ntoml: %+v\njson: %+v", fromTOML, fromJSON)
+	}
+}
+
+func TestLoad_YMLExtension(t *testing.T) {
+	cfg := loadFrom(t, "guardian_config.yml", "quality:\n  ban_todo_markers: false\n")
+	if cfg.Quality.BanTodoMarkers {
+		t.Error(".yml config should disable ban_todo_markers")
+	}
+}
+
+func TestLoad_TOMLTakesPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte("[limits]\nmax_file_lines = 300\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "guardian_config.yaml"), []byte("limits:\n  max_file_lines: 900\n"), 0644)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Limits.MaxFileLines != 300 {
+		t.Errorf("expected TOML to win (300), got %d", cfg.Limits.MaxFileLines)
+	}
+}
+
+func TestLoad_NoFileReturnsDefaults(t *testing.T) {
+	cfg, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, DefaultConfig()) {
+		t.Error("expected defaults when no config file exists")
+	}
+}
+
+func TestSave_KeepsFormat(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "guardian_config.yaml")
+	os.WriteFile(yamlPath, []byte("quality:\n  ban_print: false\n"), 0644)
+
+	cfg, _ := Load(dir)
+	cfg.Limits.MaxFileLines = 700
+	if err := Save(dir, cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "guardian_config.toml")); err == nil {
+		t.Error("Save should not create a TOML file next to an existing YAML config")
+	}
+
+	reloaded, _ := Load(dir)
+	if reloaded.Limits.MaxFileLines != 700 || reloaded.Quality.BanPrint {
+		t.Errorf("YAML round trip lost settings: %+v", reloaded)
+	}
+}
+
+// ============================================================================
+// ENVIRONMENT OVERRIDES
+// ============================================================================
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte("[limits]\nmax_file_lines = 800\nmax_function_lines = 80\n"), 0644)
+	t.Setenv(EnvMaxFileLines, "300")
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Limits.MaxFileLines != 300 {
+		t.Errorf("env should win over file: expected 300, got %d", cfg.Limits.MaxFileLines)
+	}
+	if cfg.Limits.MaxFunctionLines != 80 {
+		t.Errorf("unset env should keep file value 80, got %d", cfg.Limits.MaxFunctionLines)
+	}
+}
+
+func TestLoad_EnvOverridesDefaults(t *testing.T) {
+	t.Setenv(EnvMaxFunctionLines, "25")
+	t.Setenv(EnvMaxFileBytes, "1024")
+
+	cfg, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Limits.MaxFunctionLines != 25 || cfg.Limits.MaxFileBytes != 1024 {
+		t.Errorf("env not applied over defaults: %+v", cfg.Limits)
+	}
+}
+
+func TestLoad_EnvDisableAndEnable(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte("[quality]\nban_star_imports = false\n\n[rules]\ndisabled = [\"ban-console\"]\n"), 0644)
+	t.Setenv(EnvDisable, "ban-print, todo-marker,sql-injection")
+	t.Setenv(EnvEnable, "ban-star,ban-console,ban-print")
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Quality.BanPrint || cfg.Quality.BanTodoMarkers {
+		t.Error("GUARDIAN_DISABLE should turn off ban-print and todo-marker (and win over GUARDIAN_ENABLE)")
+	}
+	if !cfg.IsRuleDisabled("sql-injection") {
+		t.Error("rules without a toggle should be added to rules.disabled")
+	}
+	if !cfg.Quality.BanStarImports {
+		t.Error("GUARDIAN_ENABLE should turn ban-star back on")
+	}
+	if cfg.IsRuleDisabled("ban-console") {
+		t.Error("GUARDIAN_ENABLE should remove ban-console from rules.disabled")
+	}
+}
+
+func TestLoad_EnvInvalidNumber(t *testing.T) {
+	t.Setenv(EnvMaxFileLines, "lots")
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Error("expected an error for a non-numeric GUARDIAN_MAX_FILE_LINES")
+	}
+}
+
+func TestLoadFile_IgnoresEnv(t *testing.T) {
+	t.Setenv(EnvMaxFileLines, "300")
+	t.Setenv(EnvDisable, "ban-print")
+
+	cfg, err := LoadFile(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, DefaultConfig()) {
+		t.Error("LoadFile should not apply environment overrides")
+	}
+}
+
+// ============================================================================
+// NEAREST CONFIG
+// ============================================================================
+
+func TestFindNearest_WalksUp(t *testing.T) {
+	repo := t.TempDir()
+	nested := filepath.Join(repo, "services", "a", "src")
+	os.MkdirAll(nested, 0755)
+	os.WriteFile(filepath.Join(repo, "services", "a", "guardian_config.yaml"), []byte("limits:\n  max_file_lines: 200\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "guardian_config.toml"), []byte("[limits]\nmax_file_lines = 900\n"), 0644)
+
+	found, ok := FindNearest(nested)
+	if !ok || found != filepath.Join(repo, "services", "a") {
+		t.Errorf("expected services/a, got %q (ok=%v)", found, ok)
+	}
+
+	cfg, err := LoadNearest(nested)
+	if err != nil {
+		t.Fatalf("LoadNearest failed: %v", err)
+	}
+	if cfg.Limits.MaxFileLines != 200 {
+		t.Errorf("expected the nearest config's limit, got %d", cfg.Limits.MaxFileLines)
+	}
+
+	// A sibling without its own config falls back to the repo root's
+	b := filepath.Join(repo, "services", "b")
+	os.MkdirAll(b, 0755)
+	cfg, _ = LoadNearest(b)
+	if cfg.Limits.MaxFileLines != 900 {
+		t.Errorf("expected the repo root's limit, got %d", cfg.Limits.MaxFileLines)
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	root := t.TempDir()
+	legacy := filepath.Join(root, "src", "legacy")
+	os.MkdirAll(filepath.Join(legacy, "old"), 0755)
+	os.WriteFile(filepath.Join(legacy, OverrideFile), []byte("[limits]\nmax_file_lines = 2000\n\n[quality]\nban_print = false\n"), 0644)
+	os.WriteFile(filepath.Join(legacy, "old", OverrideFile), []byte("[quality]\nban_print = true\n"), 0644)
+
+	base := DefaultConfig()
+	base.Limits.MaxFunctionLines = 80
+	o := NewOverrides(root, base)
+
+	if cfg := o.For("src/app.py"); cfg != base {
+		t.Error("files outside the override should get the root config itself")
+	}
+
+	cfg := o.For("src/legacy/billing.py")
+	if cfg.Limits.MaxFileLines != 2000 || cfg.Quality.BanPrint {
+		t.Errorf("expected the override's settings, got max_file_lines=%d ban_print=%v", cfg.Limits.MaxFileLines, cfg.Quality.BanPrint)
+	}
+	if cfg.Limits.MaxFunctionLines != 80 || !cfg.Security.BanEvalExec {
+		t.Error("settings the override leaves out should come from the root config")
+	}
+
+	// The nearest override wins, over everything above it
+	nested := o.For("src/legacy/old/report.py")
+	if nested.Limits.MaxFileLines != 2000 || !nested.Quality.BanPrint {
+		t.Errorf("expected src/legacy's limit and old/'s ban_print, got %+v", nested.Limits)
+	}
+
+	if base.Limits.MaxFileLines != 500 || !base.Quality.BanPrint {
+		t.Error("resolving overrides must not change the root config")
+	}
+	if ignored := o.Ignored(); len(ignored) != 0 {
+		t.Errorf("expected no ignored overrides, got %+v", ignored)
+	}
+}
+
+func TestOverrides_InvalidIgnored(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "gen"), 0755)
+	os.WriteFile(filepath.Join(root, "gen", OverrideFile), []byte("[limits\nmax_file_lines = 2000\n"), 0644)
+
+	o := NewOverrides(root, DefaultConfig())
+	if cfg := o.For("gen/a.py"); cfg.Limits.MaxFileLines != 500 {
+		t.Errorf("an invalid override should be ignored, got max_file_lines=%d", cfg.Limits.MaxFileLines)
+	}
+	o.For("gen/b.py")
+
+	ignored := o.Ignored()
+	if len(ignored) != 1 || ignored[0].Path != "gen/"+OverrideFile || ignored[0].Err == nil {
+		t.Errorf("expected gen/.guardian.toml to be reported once, got %+v", ignored)
+	}
+	if again := o.Ignored(); len(again) != 0 {
+		t.Errorf("ignored overrides should only be returned once, got %+v", again)
+	}
+}
+
+func TestLoadNearest_NoConfigReturnsDefaults(t *testing.T) {
+	cfg, err := LoadNearest(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadNearest failed: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, DefaultConfig()) {
+		t.Error("expected defaults when no config exists")
+	}
+}
+
+// ============================================================================
+// CUSTOM RULES
+// ============================================================================
+
+func TestLoad_RulesFileMergesWithConfigRules(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte(`
+[rules]
+file = "shared/security.toml"
+
+[[custom_rules]]
+id = "no-internal-host"
+pattern = "corp\\.internal"
+severity = "info"
+`), 0644)
+	os.MkdirAll(filepath.Join(dir, "shared"), 0755)
+	os.WriteFile(filepath.Join(dir, "shared", "security.toml"), []byte(`
+[[custom_rules]]
+id = "no-pickle"
+pattern = "pickle\\.loads"
+message = "Don't unpickle untrusted data"
+severity = "critical"
+languages = ["python"]
+
+[[custom_rules]]
+id = "no-internal-host"
+pattern = "internal"
+severity = "critical"
+`), 0644)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.CustomRules) != 2 {
+		t.Fatalf("expected 2 merged rules, got %+v", cfg.CustomRules)
+	}
+	if cfg.CustomRules[0].ID != "no-internal-host" || cfg.CustomRules[0].Severity != "info" {
+		t.Errorf("the repo's own definition should win, got %+v", cfg.CustomRules[0])
+	}
+	if cfg.CustomRules[1].ID != "no-pickle" || cfg.CustomRules[1].Languages[0] != "python" {
+		t.Errorf("expected the shared rule to be added, got %+v", cfg.CustomRules[1])
+	}
+
+	// Saving goes through LoadFile, so shared rules aren't copied into the repo config
+	fileCfg, _ := LoadFile(dir)
+	if len(fileCfg.CustomRules) != 1 {
+		t.Errorf("LoadFile should not merge the rules file, got %+v", fileCfg.CustomRules)
+	}
+}
+
+func TestLoadRulesFile_YAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	os.WriteFile(path, []byte("custom_rules:\n  - id: no-debugger\n    pattern: 'debugger;'\n    languages: [js]\n"), 0644)
+
+	rules, err := LoadRulesFile(path)
+	if err != nil {
+		t.Fatalf("LoadRulesFile failed: %v", err)
+	}
+	if len(rules) != 1 || rules[0].ID != "no-debugger" {
+		t.Errorf("unexpected rules: %+v", rules)
+	}
+}
+
+func TestLoadRulesFile_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]string{
+		"no-id.toml":        "[[custom_rules]]\npattern = \"x\"\n",
+		"bad-regex.toml":    "[[custom_rules]]\nid = \"r\"\npattern = \"(unclosed\"\n",
+		"bad-severity.toml": "[[custom_rules]]\nid = \"r\"\npattern = \"x\"\nseverity = \"urgent\"\n",
+		"bad-scope.toml":    "[[custom_rules]]\nid = \"r\"\npattern = \"x\"\nscope = \"docstring\"\n",
+	}
+	for name, content := range cases {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		if _, err := LoadRulesFile(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if _, err := LoadRulesFile(filepath.Join(dir, "missing.toml")); err == nil {
+		t.Error("expected an error for a missing rules file")
+	}
+}
+
+func TestLoad_Encoding(t *testing.T) {
+	cfg := loadFrom(t, "guardian_config.toml", "[project]\nencoding = \"Latin-1\"\n")
+	if !IsLatin1(cfg.Project.Encoding) {
+		t.Errorf("expected latin-1, got %q", cfg.Project.Encoding)
+	}
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte("[project]\nencoding = \"shift-jis\"\n"), 0644)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected an error for an unsupported encoding")
+	}
+}
+
+func TestLoad_TestFileMode(t *testing.T) {
+	cfg := loadFrom(t, "guardian_config.toml", "[quality]\ntest_file_mode = \"downgrade\"\n")
+	if cfg.Quality.TestFileMode != "downgrade" {
+		t.Errorf("expected downgrade, got %q", cfg.Quality.TestFileMode)
+	}
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte("[quality]\ntest_file_mode = \"Downgrade\"\n"), 0644)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "test_file_mode") {
+		t.Errorf("expected an error naming test_file_mode for an unknown mode, got %v", err)
+	}
+}
+
+func TestSnooze(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	today := time.Now().Format("2006-01-02")
+
+	cfg := loadFrom(t, "guardian_config.toml", fmt.Sprintf(`
+[[snooze]]
+rule = "todo-marker"
+until = %q
+
+[[snooze]]
+rule = "ban-print"
+until = %q
+
+[[snooze]]
+rule = "mock-data"
+until = %q
+`, tomorrow, yesterday, today))
+
+	if !cfg.IsRuleDisabled("todo-marker") || cfg.RuleEnabled("todo-marker") {
+		t.Error("todo-marker should be off while its snooze is active")
+	}
+	if cfg.IsRuleDisabled("ban-print") {
+		t.Error("ban-print's snooze has expired, it should run again")
+	}
+	if cfg.IsRuleDisabled("mock-data") {
+		t.Error("a snooze ends at the start of its until date")
+	}
+
+	active, expired := cfg.Snoozes(time.Now())
+	if len(active) != 1 || active[0].Rule != "todo-marker" || len(expired) != 2 {
+		t.Errorf("got active=%+v expired=%+v", active, expired)
+	}
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte("[[snooze]]\nrule = \"ban-print\"\nuntil = \"next sprint\"\n"), 0644)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected an error for an until that isn't a date")
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	cfg := loadFrom(t, "guardian_config.toml", `
+[languages.python]
+extensions = [".pyw"]
+rules = ["security", "ban-print"]
+`)
+	want := LanguageConfig{Extensions: []string{".pyw"}, Rules: []string{"security", "ban-print"}}
+	if !reflect.DeepEqual(cfg.Languages["python"], want) {
+		t.Errorf("got %+v, want %+v", cfg.Languages["python"], want)
+	}
+
+	for _, content := range []string{
+		"[languages.ruby]\nrules = [\"security\"]\n",
+		"[languages.js]\nextensions = [\"\"]\n",
+	} {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte(content), 0644)
+		if _, err := Load(dir); err == nil {
+			t.Errorf("expected an error for %q", content)
+		}
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+
+	// Walks properties by key, failing if any level is missing
+	prop := func(keys ...string) map[string]any {
+		t.Helper()
+		node := schema
+		for _, key := range keys {
+			props, _ := node["properties"].(map[string]any)
+			next, ok := props[key].(map[string]any)
+			if !ok {
+				t.Fatalf("schema has no %v", keys)
+			}
+			node = next
+		}
+		return node
+	}
+
+	if got := prop("limits", "max_file_lines")["type"]; got != "integer" {
+		t.Errorf("max_file_lines: got type %v, want integer", got)
+	}
+
+	patterns := prop("quality", "mock_patterns")
+	items, _ := patterns["items"].(map[string]any)
+	if patterns["type"] != "array" || items["type"] != "string" {
+		t.Errorf("mock_patterns: got %v, want an array of strings", patterns)
+	}
+
+	if got := prop("quality", "ban_print")["type"]; got != "boolean" {
+		t.Errorf("ban_print: got type %v, want boolean", got)
+	}
+
+	severity := prop("custom_rules")["items"].(map[string]any)["properties"].(map[string]any)["severity"].(map[string]any)
+	if !reflect.DeepEqual(severity["enum"], []string{"critical", "warning", "info"}) {
+		t.Errorf("custom rule severity: got enum %v", severity["enum"])
+	}
+}
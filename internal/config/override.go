@@ -0,0 +1,129 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// OverrideFile relaxes or tightens settings for one subtree. It's written
+// like the main config, and every setting it has replaces the parent's for
+// files beneath its directory; tables like [limits] are merged key by key.
+const OverrideFile = ".guardian.toml"
+
+// Overrides resolves the config for each file under a project root: the
+// root config with every OverrideFile between the root and the file merged
+// over it, nearest last. Results are cached per directory, and it's safe
+// for concurrent use.
+type Overrides struct {
+	root string
+	base *Config
+
+	mu      sync.Mutex
+	dirs    map[string]*Config // Slash-separated directory under root -> its config
+	ignored []IgnoredOverride
+}
+
+// IgnoredOverride is an OverrideFile that couldn't be read or is invalid.
+// Its subtree keeps the parent's config.
+type IgnoredOverride struct {
+	Path string // Slash-separated, relative to the root
+	Err  error
+}
+
+// NewOverrides resolves overrides under root on top of base
+func NewOverrides(root string, base *Config) *Overrides {
+	return &Overrides{root: root, base: base, dirs: make(map[string]*Config)}
+}
+
+// For returns the config for relPath, a slash-separated path under the root
+func (o *Overrides) For(relPath string) *Config {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.dirConfig(path.Dir(path.Clean(relPath)))
+}
+
+// Ignored returns the overrides skipped since the last call, so each is
+// reported once
+func (o *Overrides) Ignored() []IgnoredOverride {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ignored := o.ignored
+	o.ignored = nil
+	return ignored
+}
+
+func (o *Overrides) dirConfig(dir string) *Config {
+	if cfg, ok := o.dirs[dir]; ok {
+		return cfg
+	}
+	if dir == ".." || strings.HasPrefix(dir, "../") || path.IsAbs(dir) {
+		return o.base // Outside the root
+	}
+
+	cfg := o.base
+	if dir != "." {
+		cfg = o.dirConfig(path.Dir(dir))
+	}
+
+	if data, err := os.ReadFile(filepath.Join(o.root, filepath.FromSlash(dir), OverrideFile)); err == nil {
+		if merged, err := cfg.withOverride(data); err != nil {
+			o.ignored = append(o.ignored, IgnoredOverride{Path: path.Join(dir, OverrideFile), Err: err})
+		} else {
+			cfg = merged
+		}
+	}
+	o.dirs[dir] = cfg
+	return cfg
+}
+
+// withOverride returns a copy of c with an OverrideFile's content decoded
+// over it. GUARDIAN_* variables are applied again so they still win.
+func (c *Config) withOverride(data []byte) (*Config, error) {
+	merged, err := c.clone()
+	if err != nil {
+		return nil, err
+	}
+	if err := toml.Unmarshal(data, merged); err != nil {
+		return nil, err
+	}
+	if err := merged.validate(); err != nil {
+		return nil, err
+	}
+	if err := merged.ApplyEnv(); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// WithStrictRules returns c with strict.rules turned on, for checking a file
+// under strict.paths. c itself is returned when there are none to add.
+func (c *Config) WithStrictRules() *Config {
+	if len(c.Strict.Rules) == 0 {
+		return c
+	}
+	strict, err := c.clone()
+	if err != nil {
+		return c
+	}
+	for _, rule := range c.Strict.Rules {
+		strict.EnableRule(rule)
+	}
+	return strict
+}
+
+// clone deep-copies c, so decoding over the copy leaves c's maps and
+// slices alone
+func (c *Config) clone() (*Config, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	copied := &Config{}
+	return copied, json.Unmarshal(data, copied)
+}
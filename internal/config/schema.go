@@ -0,0 +1,66 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// schemaEnums lists the allowed values of string settings, by dotted key.
+// For lists and tables the values apply to each entry, and for maps to
+// each key.
+var schemaEnums = map[string][]string{
+	"project.encoding":       {"utf-8", "utf8", "latin-1", "latin1", "iso-8859-1"},
+	"quality.test_file_mode": {"skip", "downgrade", "report"},
+	"custom_rules.severity":  {"critical", "warning", "info"},
+	"custom_rules.languages": Languages,
+	"custom_rules.scope":     CustomRuleScopes,
+	"languages":              Languages,
+}
+
+// Schema describes Config as a JSON Schema, built from the struct's toml
+// tags so it can't drift from what Load accepts
+func Schema() map[string]any {
+	schema := schemaFor(reflect.TypeOf(Config{}), "")
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "Guardian config"
+	return schema
+}
+
+// schemaFor returns the schema of one Go type; path is its dotted key
+func schemaFor(t reflect.Type, path string) map[string]any {
+	switch t.Kind() {
+	case reflect.Struct:
+		props := make(map[string]any)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			key, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+			if key == "" || key == "-" || !field.IsExported() {
+				continue
+			}
+			props[key] = schemaFor(field.Type, strings.TrimPrefix(path+"."+key, "."))
+		}
+		return map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), path)}
+	case reflect.Map:
+		s := map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), path)}
+		if enum, ok := schemaEnums[path]; ok {
+			s["propertyNames"] = map[string]any{"enum": enum}
+		}
+		return s
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		s := map[string]any{"type": "string"}
+		if enum, ok := schemaEnums[path]; ok {
+			s["enum"] = enum
+		}
+		return s
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// snoozeDateLayout is how snooze dates are written: 2025-06-01
+const snoozeDateLayout = "2006-01-02"
+
+// Snooze turns a rule off until a date, e.g. for the rest of a sprint
+type Snooze struct {
+	Rule  string `toml:"rule" yaml:"rule" json:"rule"`
+	Until string `toml:"until" yaml:"until" json:"until"` // YYYY-MM-DD; the rule is back on from this date
+}
+
+// Active reports whether the snooze still applies on now's date. Dates
+// that don't parse never apply; Load rejects them.
+func (s Snooze) Active(now time.Time) bool {
+	until, err := time.ParseInLocation(snoozeDateLayout, s.Until, now.Location())
+	return err == nil && now.Before(until)
+}
+
+// IsRuleSnoozed reports whether an active [[snooze]] covers rule
+func (c *Config) IsRuleSnoozed(rule string) bool {
+	now := time.Now()
+	for _, s := range c.Snooze {
+		if s.Rule == rule && s.Active(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Snoozes splits the [[snooze]] entries into those still in effect on now's
+// date and those that have run out
+func (c *Config) Snoozes(now time.Time) (active, expired []Snooze) {
+	for _, s := range c.Snooze {
+		if s.Active(now) {
+			active = append(active, s)
+		} else {
+			expired = append(expired, s)
+		}
+	}
+	return active, expired
+}
+
+func validateSnoozes(snoozes []Snooze) error {
+	for i, s := range snoozes {
+		if s.Rule == "" {
+			return fmt.Errorf("snooze[%d]: rule is required", i)
+		}
+		if _, err := time.Parse(snoozeDateLayout, s.Until); err != nil {
+			return fmt.Errorf("snooze %s: until must be a YYYY-MM-DD date, got %q", s.Rule, s.Until)
+		}
+	}
+	return nil
+}
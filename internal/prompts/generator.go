@@ -314,9 +314,124 @@ func GetExplanation(rule string) Explanation {
 			Fix:     "Use parameterized queries: cursor.execute('SELECT * FROM users WHERE id = ?', (user_id,))",
 		},
 		"subprocess-shell": {
-			Problem: "You're using shell=True in subprocess.",
-			Why:     "This passes commands through a shell, enabling command injection attacks.",
-			Fix:     "Pass commands as a list instead: subprocess.run(['ls', '-la'])",
+			Problem: "You're running a command through a shell: shell=True, or an argument list like [\"bash\", \"-c\", f\"rm {path}\"].",
+			Why:     "This passes commands through a shell, enabling command injection attacks. Wrapping the string in bash -c is the same risk as shell=True.",
+			Fix:     "Pass commands as a list instead: subprocess.run(['ls', '-la']) or subprocess.run(['rm', path])",
+		},
+		"assert-validation": {
+			Problem: "This code uses assert to enforce a check at runtime.",
+			Why:     "Python removes every assert when run with -O, so in production the check silently disappears and the code carries on.",
+			Fix:     "Use an explicit if statement that raises an exception, e.g. if not user.is_admin: raise PermissionError(...)",
+		},
+		"insecure-cors": {
+			Problem: "This server allows cross-origin requests from any website (origin \"*\").",
+			Why:     "Any page a user visits can call your API from their browser and read the responses. It's a common default in generated code that nobody meant to ship.",
+			Fix:     "List the origins that actually need access, e.g. allow_origins=[\"https://app.example.com\"], and load them from config per environment.",
+		},
+		"insecure-tls": {
+			Problem: "This code turns off TLS certificate verification (verify=False, rejectUnauthorized: false, InsecureSkipVerify: true).",
+			Why:     "Without verification anyone on the network path can impersonate the server and read or change the traffic, passwords and tokens included. It's often added to get past a certificate error and then forgotten.",
+			Fix:     "Remove the override. If the server uses a private CA, point the client at that CA bundle (verify=\"/path/ca.pem\", ca: fs.readFileSync(...), RootCAs) instead of skipping the check.",
+		},
+		"pii-logging": {
+			Problem: "This log or print call includes personal data (an email, phone number, SSN, card number or date of birth).",
+			Why:     "Logs are copied to aggregators, backups and support tools with far wider access than your database. Personal data there is a compliance problem (GDPR, HIPAA, PCI) and is hard to delete.",
+			Fix:     "Log an ID instead of the value, or mask it (e.g. j***@example.com, last 4 digits only). If the field isn't personal data, remove it from pii_fields under [security].",
+		},
+		"permissive-chmod": {
+			Problem: "This chmod call makes a file writable (or runnable) by users and processes that shouldn't have that access.",
+			Why:     "A world-writable file can be replaced by any process on the machine. If it's a script, config or binary, that's a way to run code as whoever uses it next.",
+			Fix:     "Use the tightest mode that works: 0o600 for secrets, 0o644 for readable files, 0o755 for executables and directories. Raise max_chmod_mode under [security] if your project really needs looser modes.",
+		},
+		"unpinned-dependency": {
+			Problem: "This requirement accepts a range of versions instead of one exact version.",
+			Why:     "Every install can pull a different release, so CI, production and your laptop drift apart, and a compromised or broken new release is picked up without anyone reviewing it.",
+			Fix:     "Pin it with == (requests==2.32.3), or keep loose ranges in requirements.in and generate a pinned requirements.txt with pip-compile.",
+		},
+		"url-dependency": {
+			Problem: "This dependency is installed from a git repository, a URL or a local path instead of the npm registry.",
+			Why:     "A branch or URL can change under you without a version bump, lockfile integrity checks don't cover it the same way, and local paths break on any machine without the same checkout.",
+			Fix:     "Depend on a published version. If you need a fork, publish it under a scope, or at least pin a commit SHA (git+https://...#<sha>) and review it like your own code.",
+		},
+		"unprotected-route": {
+			Problem: "This route handler has no auth decorator or dependency, so anyone who can reach the server can call it.",
+			Why:     "Generated endpoints often skip authentication. A missing check on one route exposes its data or actions to every caller.",
+			Fix:     "Add your auth dependency (user = Depends(get_current_user)) or decorator (@login_required). If the route is meant to be public, add an ignore comment, or add your own auth helper to auth_markers under [security].",
+		},
+		"blocking-in-async": {
+			Problem: "This async function makes a blocking call (time.sleep, requests, sync file IO) that holds up the event loop.",
+			Why:     "While it waits, every other request and task on the loop waits too. One slow call in a FastAPI or Node handler stalls the whole server.",
+			Fix:     "Use the async version (asyncio.sleep, httpx.AsyncClient, aiofiles, fs.promises), or move the call off the loop with asyncio.to_thread / run_in_executor.",
+		},
+		"insecure-deserialization": {
+			Problem: "This loads data with pickle, marshal or yaml.load, which can rebuild arbitrary Python objects.",
+			Why:     "A crafted pickle or YAML document runs code the moment it's loaded. If the data comes from a user, a request, a cache or a file someone else can write, that's remote code execution.",
+			Fix:     "Use a data-only format: json.loads(), or yaml.safe_load() / yaml.load(data, Loader=yaml.SafeLoader). Keep pickle for data your own process wrote and nobody else can touch.",
+		},
+		"library-panic": {
+			Problem: "This Go package calls panic() outside package main.",
+			Why:     "A panic in a library crashes whatever program imports it, and callers can't handle it like a normal error.",
+			Fix:     "Return an error from the function and let the caller decide what to do.",
+		},
+		"ignored-error": {
+			Problem: "This code assigns an error to _ and moves on.",
+			Why:     "If the call fails, nothing notices - the program continues with bad or missing data and the real cause is lost.",
+			Fix:     "Check the error: if err != nil { return fmt.Errorf(\"doing X: %w\", err) }",
+		},
+		"large-file": {
+			Problem: "This file is very large, or is a binary (model weights, database, archive) rather than source code.",
+			Why:     "Big and binary files bloat the git history forever, slow every clone, and can't be reviewed in a diff.",
+			Fix:     "Remove it from git and add it to .gitignore. Download it at build time, or use Git LFS or object storage.",
+		},
+		"curl-pipe-sh": {
+			Problem: "This script downloads code and pipes it straight into a shell (curl ... | sh).",
+			Why:     "Whatever the server returns runs with your permissions - a compromised or spoofed host, or a download cut off halfway, runs on every build.",
+			Fix:     "Download the script to a file, check it against a pinned checksum (sha256sum -c), then run it. Better still, install a pinned version from a package manager.",
+		},
+		"remote-exec": {
+			Problem: "This script runs code straight from the internet (eval \"$(curl ...)\", bash <(curl ...)), or downloads a file and makes it executable without checking it.",
+			Why:     "Nothing checks what the server sent. A compromised or spoofed host, a hijacked release or a truncated download runs with the script's permissions - in CI, often with deploy secrets in reach.",
+			Fix:     "Pin a version, download to a file, check it against a known checksum (echo \"<sha256>  tool\" | sha256sum -c), then chmod +x and run it. Better still, install it from a package manager.",
+		},
+		"unawaited-async": {
+			Problem: "This calls an async function but never awaits it, so the result is thrown away.",
+			Why:     "In Python the coroutine never runs at all. In JS it runs, but nothing waits for it: code after it races ahead, and if it fails the error is an unhandled rejection nobody sees.",
+			Fix:     "Add await. If it really should run in the background, make that explicit: asyncio.create_task(...) in Python, or void fn().catch(handleError) in JS.",
+		},
+		"log-and-ignore": {
+			Problem: "This except/catch block only logs the error at debug level, then carries on as if nothing happened.",
+			Why:     "Debug logs are off in production, so the failure is effectively invisible. The code continues with bad or missing data and breaks somewhere harder to trace.",
+			Fix:     "Handle the error properly: recover with a real fallback, log it at warning or error level, or re-raise it (raise / throw err) so the caller knows.",
+		},
+		"commented-code": {
+			Problem: "This block of comments is old code that was commented out rather than deleted.",
+			Why:     "Dead code in comments goes stale, confuses readers about what actually runs, and gets copied back in by mistake.",
+			Fix:     "Delete it. If you might need it again, it's still in git history.",
+		},
+		"stub-implementation": {
+			Problem: "This function is a placeholder: its body is only pass, ... or raise NotImplementedError, or it only throws a \"not implemented\" error.",
+			Why:     "Callers look like they work until this runs, then silently do nothing or crash. AI assistants often leave stubs like this when they run out of room, and they're easy to miss in review.",
+			Fix:     "Write the real implementation, or delete the function and its callers. If subclasses must provide it, make it abstract (@abstractmethod, or an abstract method in TypeScript).",
+		},
+		"suppression-comment": {
+			Problem: "This comment turns off another tool's check (# type: ignore, # noqa, @ts-ignore, eslint-disable) for this line or file.",
+			Why:     "It hides the error instead of fixing it, so a real bug can ship unnoticed. AI assistants often add these just to make a red squiggle go away.",
+			Fix:     "Remove the comment and fix what the tool reports. If the tool is genuinely wrong, keep it but scope it to one error code (# type: ignore[attr-defined], // eslint-disable-next-line no-console) and say why.",
+		},
+		"unreachable-code": {
+			Problem: "This statement comes straight after a return, raise/throw, break or continue in the same block, so it never runs.",
+			Why:     "It's usually left over from an edit: the code looks like it does something, but doesn't. Readers and reviewers get the wrong idea of what the function does.",
+			Fix:     "Delete it, or if it was meant to run, move it above the return.",
+		},
+		"hardcoded-path": {
+			Problem: "This string contains an absolute path into someone's home directory (/Users/alice/..., C:\\Users\\...).",
+			Why:     "The path only exists on the machine it was written on. Anyone else running the code gets a file-not-found error.",
+			Fix:     "Build paths relative to the project, or read the location from config or an environment variable.",
+		},
+		"no-timeout": {
+			Problem: "This HTTP call has no timeout.",
+			Why:     "Without a timeout a slow or dead server makes the request wait forever, hanging workers and piling up connections.",
+			Fix:     "Python: requests.get(url, timeout=10). JS: fetch(url, { signal: AbortSignal.timeout(10000) }) or axios's timeout option.",
 		},
 		"ban-console": {
 			Problem: "You're using console.log() for output.",
@@ -0,0 +1,63 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/guardian-sh/guardian/internal/checks"
+)
+
+// PlanFile is where `guardian plan` writes the fix plan, relative to the project root
+const PlanFile = ".guardian/fix-plan.md"
+
+// GeneratePlan renders issues as a markdown checklist grouped by file, with the
+// fix for each issue inline and the full explanation of each rule at the end.
+// It's meant to be worked through top to bottom by a person or an AI agent.
+func GeneratePlan(issues []checks.Issue) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Guardian fix plan\n\n")
+	if len(issues) == 0 {
+		sb.WriteString("No issues found. Nothing to fix.\n")
+		return sb.String()
+	}
+
+	// Group by file, in the order files first appear
+	var files []string
+	byFile := make(map[string][]checks.Issue)
+	for _, issue := range issues {
+		if _, ok := byFile[issue.File]; !ok {
+			files = append(files, issue.File)
+		}
+		byFile[issue.File] = append(byFile[issue.File], issue)
+	}
+
+	sb.WriteString(fmt.Sprintf("%d issues in %d files. Check off each item as you fix it, then run `guardian check` to confirm.\n", len(issues), len(files)))
+	sb.WriteString("Fix issues properly - don't delete code just to silence a check.\n")
+
+	var rules []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", file))
+		for _, issue := range byFile[file] {
+			sb.WriteString(fmt.Sprintf("- [ ] Line %d `%s` (%s): %s\n", issue.Line, issue.Rule, issue.Severity, issue.Message))
+			sb.WriteString(fmt.Sprintf("  - Fix: %s\n", GetExplanation(issue.Rule).Fix))
+
+			if !seen[issue.Rule] {
+				seen[issue.Rule] = true
+				rules = append(rules, issue.Rule)
+			}
+		}
+	}
+
+	sb.WriteString("\n## Rules\n")
+	for _, rule := range rules {
+		exp := GetExplanation(rule)
+		sb.WriteString(fmt.Sprintf("\n### `%s`\n\n", rule))
+		sb.WriteString(fmt.Sprintf("- What's wrong: %s\n", exp.Problem))
+		sb.WriteString(fmt.Sprintf("- Why it matters: %s\n", exp.Why))
+		sb.WriteString(fmt.Sprintf("- How to fix: %s\n", exp.Fix))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+// Package report renders check results in formats other tools ingest
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/guardian-sh/guardian/internal/checks"
+)
+
+// customSuite holds issues from [[custom_rules]], which belong to no group
+const customSuite = "custom"
+
+// JUnitSuites is the <testsuites> root of a JUnit report
+type JUnitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []JUnitSuite `xml:"testsuite"`
+}
+
+// JUnitSuite is one rule group
+type JUnitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []JUnitCase `xml:"testcase"`
+}
+
+// JUnitCase is one issue, always failing
+type JUnitCase struct {
+	Name      string       `xml:"name,attr"`      // file:line
+	ClassName string       `xml:"classname,attr"` // The rule
+	Failure   JUnitFailure `xml:"failure"`
+}
+
+// JUnitFailure says what's wrong; the body repeats it in the
+// "file:line [rule] message" form for reporters that only show the body
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"` // Severity
+	Body    string `xml:",chardata"`
+}
+
+// JUnit builds a report with a testsuite per rule group, quality and
+// security first and then custom rules, and a failing testcase per issue.
+// The builtin groups are always present so CI sees the same suites on a
+// clean run.
+func JUnit(issues []checks.Issue) JUnitSuites {
+	suites := make(map[string]*JUnitSuite)
+	var order []string
+	addSuite := func(name string) *JUnitSuite {
+		if suites[name] == nil {
+			suites[name] = &JUnitSuite{Name: name}
+			order = append(order, name)
+		}
+		return suites[name]
+	}
+	for _, group := range checks.RuleGroups {
+		addSuite(group)
+	}
+
+	for _, issue := range issues {
+		group := customSuite
+		if rule, ok := checks.LookupRule(issue.Rule); ok {
+			group = rule.Group
+		}
+		suite := addSuite(group)
+		suite.Cases = append(suite.Cases, JUnitCase{
+			Name:      fmt.Sprintf("%s:%d", issue.File, issue.Line),
+			ClassName: issue.Rule,
+			Failure: JUnitFailure{
+				Message: issue.Message,
+				Type:    issue.Severity,
+				Body:    checks.FormatIssueLine(issue),
+			},
+		})
+		suite.Tests++
+		suite.Failures++
+	}
+
+	report := JUnitSuites{Name: "guardian", Tests: len(issues), Failures: len(issues)}
+	for _, name := range order {
+		report.Suites = append(report.Suites, *suites[name])
+	}
+	return report
+}
+
+// MarshalJUnit renders issues as an indented JUnit XML document
+func MarshalJUnit(issues []checks.Issue) ([]byte, error) {
+	out, err := xml.MarshalIndent(JUnit(issues), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/guardian-sh/guardian/internal/checks"
+)
+
+func TestMarshalJUnit(t *testing.T) {
+	issues := []checks.Issue{
+		{File: "app.py", Line: 3, Rule: "ban-eval", Message: "Avoid eval() - it runs arbitrary code", Severity: "critical"},
+		{File: "app.py", Line: 7, Rule: "ban-print", Message: "Remove print() - use logging instead", Severity: "info"},
+		{File: "lib/db.py", Line: 12, Rule: "sql-injection", Message: "Query built with <f-string> & input", Severity: "critical"},
+		{File: "lib/db.py", Line: 20, Rule: "no-internal-host", Message: "Internal hostname", Severity: "warning"},
+	}
+
+	out, err := MarshalJUnit(issues)
+	if err != nil {
+		t.Fatalf("MarshalJUnit failed: %v", err)
+	}
+	if !strings.HasPrefix(string(out), xml.Header) {
+		t.Errorf("expected an XML declaration, got:\n%s", out)
+	}
+
+	var got JUnitSuites
+	if err := xml.Unmarshal(out, &got); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+	if got.Tests != 4 || got.Failures != 4 {
+		t.Errorf("testsuites: tests=%d failures=%d, want 4 and 4", got.Tests, got.Failures)
+	}
+
+	want := map[string]int{"quality": 1, "security": 2, "custom": 1}
+	if len(got.Suites) != len(want) {
+		t.Fatalf("expected %d testsuites, got %+v", len(want), got.Suites)
+	}
+	total := 0
+	for _, suite := range got.Suites {
+		if suite.Tests != want[suite.Name] || suite.Failures != suite.Tests || len(suite.Cases) != suite.Tests {
+			t.Errorf("%s: tests=%d failures=%d cases=%d, want %d", suite.Name, suite.Tests, suite.Failures, len(suite.Cases), want[suite.Name])
+		}
+		total += len(suite.Cases)
+	}
+	if total != len(issues) {
+		t.Errorf("expected a testcase per issue, got %d", total)
+	}
+
+	security := got.Suites[1]
+	c := security.Cases[1]
+	if c.Name != "lib/db.py:12" || c.ClassName != "sql-injection" {
+		t.Errorf("testcase: got name=%q classname=%q", c.Name, c.ClassName)
+	}
+	if c.Failure.Message != issues[2].Message || c.Failure.Type != "critical" {
+		t.Errorf("failure: got %+v", c.Failure)
+	}
+	if strings.TrimSpace(c.Failure.Body) != "lib/db.py:12 [sql-injection] Query built with <f-string> & input" {
+		t.Errorf("failure body: got %q", c.Failure.Body)
+	}
+}
+
+func TestMarshalJUnit_NoIssues(t *testing.T) {
+	out, err := MarshalJUnit(nil)
+	if err != nil {
+		t.Fatalf("MarshalJUnit failed: %v", err)
+	}
+	var got JUnitSuites
+	if err := xml.Unmarshal(out, &got); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+	if got.Tests != 0 || len(got.Suites) != 2 || got.Suites[0].Name != "quality" || got.Suites[1].Name != "security" {
+		t.Errorf("expected empty quality and security suites, got %+v", got)
+	}
+}
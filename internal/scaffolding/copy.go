@@ -2,21 +2,50 @@ package scaffolding
 
 import (
 	"embed"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
 //go:embed files/*
 var scaffoldingFiles embed.FS
 
+// Version is stamped into generated scripts and config so a CLI upgrade can
+// spot stale scaffolding. main sets it to the binary's version.
+var Version = "dev"
+
+// versionStampRe finds the stamp in a script or config header
+var versionStampRe = regexp.MustCompile(`^(?:#|//)\s*guardian-version:\s*(\S+)`)
+
+// versionStampLines is how far into a file the stamp is looked for
+const versionStampLines = 5
+
+// installedScripts are the files checked for a version stamp, in the order
+// InstalledVersion tries them
+var installedScripts = []string{
+	".guardian/guardian.py",
+	".guardian/guardian.js",
+	".guardian/guardian.sh",
+	".guardian/guardian.php",
+	"guardian_config.toml",
+}
+
 // InstallConfig holds configuration for installation
 type InstallConfig struct {
 	Language    string   // python, typescript, go, php
 	Stack       string   // python-fastapi, typescript-react, etc.
 	SourceDir   string   // src/
 	ExcludeDirs []string // tests/, __pycache__/, etc.
+	CI          string   // gitlab, or "" for no CI config
+	GoHook      string   // Go pre-commit hook: "script" runs .guardian/guardian.sh (the default), "native" runs guardian check
+	Hook        string   // husky, or "" for none; pre-commit is configured separately
+
+	SkipPreCommit bool // Don't create or touch .pre-commit-config.yaml
+	SkipConfig    bool // Don't write guardian_config.toml (e.g. one already exists)
+	Force         bool // Overwrite scripts the user has edited since they were installed
 }
 
 // Install copies scaffolding files to the target directory
@@ -39,7 +68,9 @@ func Install(config InstallConfig) error {
 		if createdDir {
 			os.RemoveAll(guardianDir)
 		}
-		os.Remove("guardian_config.toml")
+		if !config.SkipConfig {
+			os.Remove("guardian_config.toml")
+		}
 	}
 
 	// Copy language-specific files
@@ -71,22 +102,40 @@ func Install(config InstallConfig) error {
 			destPath = filepath.Join(guardianDir, destPath)
 		}
 
-		if err := os.WriteFile(destPath, content, 0644); err != nil {
+		if err := writeScaffold(destPath, string(content), 0644, config.Force); err != nil {
 			cleanup()
 			return fmt.Errorf("failed to write %s: %w", destPath, err)
 		}
 	}
 
 	// Generate config file
-	if err := generateConfig(config); err != nil {
-		cleanup()
-		return err
+	if !config.SkipConfig {
+		if err := generateConfig(config); err != nil {
+			cleanup()
+			return err
+		}
 	}
 
 	// Generate/update pre-commit config
-	if err := generatePreCommitConfig(config); err != nil {
-		cleanup()
-		return err
+	if !config.SkipPreCommit {
+		if err := generatePreCommitConfig(config); err != nil {
+			cleanup()
+			return err
+		}
+	}
+
+	if config.CI == "gitlab" {
+		if err := generateGitLabCI(config); err != nil {
+			cleanup()
+			return err
+		}
+	}
+
+	if config.Hook == "husky" {
+		if err := generateHuskyHook(); err != nil {
+			cleanup()
+			return err
+		}
 	}
 
 	return nil
@@ -113,20 +162,35 @@ func generateFiles(config InstallConfig) error {
 	}
 
 	// Generate config file
-	if err := generateConfig(config); err != nil {
-		return err
+	if !config.SkipConfig {
+		if err := generateConfig(config); err != nil {
+			return err
+		}
 	}
 
 	// Generate/update pre-commit config
-	if err := generatePreCommitConfig(config); err != nil {
-		return err
+	if !config.SkipPreCommit {
+		if err := generatePreCommitConfig(config); err != nil {
+			return err
+		}
+	}
+
+	if config.CI == "gitlab" {
+		if err := generateGitLabCI(config); err != nil {
+			return err
+		}
+	}
+
+	if config.Hook == "husky" {
+		return generateHuskyHook()
 	}
 
 	return nil
 }
 
-func generatePythonFiles(config InstallConfig) error {
-	files := map[string]string{
+// languageFiles are the scripts generated for each language, by path
+var languageFiles = map[string]map[string]string{
+	"python": {
 		".guardian/check_file_size.py":        pythonCheckFileSize,
 		".guardian/check_function_size.py":    pythonCheckFunctionSize,
 		".guardian/check_dangerous.py":        pythonCheckDangerous,
@@ -138,71 +202,42 @@ func generatePythonFiles(config InstallConfig) error {
 		".guardian/check_subprocess_shell.py": pythonCheckSubprocessShell,
 		".guardian/check_bare_except.py":      pythonCheckBareExcept,
 		".guardian/guardian.py":               pythonGuardian,
-	}
-
-	for path, content := range files {
-		dir := filepath.Dir(path)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
-		}
-		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func generateTypeScriptFiles(config InstallConfig) error {
+	},
 	// Single comprehensive guardian.js with all checks including security
-	files := map[string]string{
+	"typescript": {
 		".guardian/guardian.js":          tsGuardianFull,
 		".guardian/guardian.config.json": tsGuardianConfig,
-	}
-
-	for path, content := range files {
-		dir := filepath.Dir(path)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
-		}
-		perm := os.FileMode(0755)
-		if filepath.Ext(path) == ".json" {
-			perm = 0644
-		}
-		if err := os.WriteFile(path, []byte(content), perm); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func generateGoFiles(config InstallConfig) error {
+	},
 	// Go projects typically use go vet, staticcheck, etc.
 	// We'll generate a simple wrapper
-	files := map[string]string{
+	"go": {
 		".guardian/guardian.sh": goGuardianScript,
-	}
+	},
+	"php": {
+		".guardian/guardian.php":         phpGuardianScript,
+		".guardian/guardian.config.json": phpGuardianConfig,
+	},
+}
 
-	for path, content := range files {
-		dir := filepath.Dir(path)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
-		}
-		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
-			return err
-		}
-	}
+func generatePythonFiles(config InstallConfig) error {
+	return writeLanguageFiles(languageFiles["python"], config.Force)
+}
 
-	return nil
+func generateTypeScriptFiles(config InstallConfig) error {
+	return writeLanguageFiles(languageFiles["typescript"], config.Force)
+}
+
+func generateGoFiles(config InstallConfig) error {
+	return writeLanguageFiles(languageFiles["go"], config.Force)
 }
 
 func generatePhpFiles(config InstallConfig) error {
-	files := map[string]string{
-		".guardian/guardian.php":         phpGuardianScript,
-		".guardian/guardian.config.json": phpGuardianConfig,
-	}
+	return writeLanguageFiles(languageFiles["php"], config.Force)
+}
 
+// writeLanguageFiles writes a language's scripts, executable except for
+// JSON config
+func writeLanguageFiles(files map[string]string, force bool) error {
 	for path, content := range files {
 		dir := filepath.Dir(path)
 		if err := os.MkdirAll(dir, 0755); err != nil {
@@ -212,7 +247,7 @@ func generatePhpFiles(config InstallConfig) error {
 		if filepath.Ext(path) == ".json" {
 			perm = 0644
 		}
-		if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		if err := writeScaffold(path, content, perm, force); err != nil {
 			return err
 		}
 	}
@@ -237,13 +272,23 @@ func generateConfig(config InstallConfig) error {
 [project]
 src_root = "%s"
 exclude_dirs = [%s]
+# Check extra extensions with python, js, go or shell rules
+# include_ext = { ".mjs" = "js", ".pyi" = "python" }
+# Read files that aren't valid UTF-8 as latin-1
+# encoding = "latin-1"
+# Where --explain links each rule's docs ("" to hide the links)
+# docs_base_url = "https://guardian.sh/rules"
 
 [limits]
 max_file_lines = 500
 max_function_lines = 50
+max_file_bytes = 5242880  # flag any committed file over 5MB (0 disables)
+max_scan_bytes = 10485760 # don't scan source files over 10MB (0 disables)
+concurrency = 0           # files checked in parallel (0 = one per CPU)
 
 [limits.custom_file_limits]
 # "some/big/file.py" = 700
+# "migrations/*.py" = 1000  # globs work too; exact paths win
 
 [quality]
 ban_print = true
@@ -263,10 +308,50 @@ mock_patterns = [
     "lorem ipsum", "foo_bar", "asdf",
 ]
 
+# Absolute home-directory paths (/Users/alice/..., C:\Users\...)
+ban_hardcoded_paths = true
+
+# HTTP calls (requests.get, fetch, axios) must set a timeout
+require_timeouts = true
+ban_library_panic = true    # Go: panic() outside package main
+ban_ignored_errors = true   # Go: _ = err
+ban_log_and_ignore = true   # except/catch that only logs at debug level
+ban_blocking_in_async = true  # time.sleep(), requests.get(), readFileSync() in async code
+# blocking_calls = ["time.sleep", "requests.get", "open", "fs.readFileSync"]
+ban_unawaited_async = true   # calling this file's async functions without await
+ban_unreachable_code = true   # statements after return/raise/throw in the same block
+ban_commented_code = true
+commented_code_min_lines = 4
+ban_suppression_comments = true   # comments that silence mypy, flake8, tsc or eslint
+max_suppression_comments = 0      # a file may have this many before each is flagged
+ban_stub_implementations = true   # functions that are only pass or raise NotImplementedError
+
+# Rules relaxed inside test files (tests/, __tests__/, test_*.py, *.spec.ts)
+# test_file_mode: "skip", "downgrade" (report as info) or "report"
+test_file_rules = ["mock-data"]
+test_file_mode = "skip"
+
 [security]
 ban_eval_exec = true
+# Safe eval-like calls ban-eval should ignore (exact call expressions)
+eval_allowlist = ["ast.literal_eval", "literal_eval"]
 ban_subprocess_shell = true
+ban_static_shell_commands = false  # Also flag ["bash", "-c", "ls"] when the command is a constant
 ban_dangerous_commands = true
+ban_assert_validation = true
+ban_wildcard_cors = true
+ban_insecure_tls = true
+ban_insecure_deserialization = true   # pickle, marshal, yaml.load without SafeLoader
+ban_pii_logging = true
+pii_fields = ["email", "ssn", "phone", "credit_card", "dob"]
+ban_unprotected_routes = true  # FastAPI/Flask routes with no auth decorator or Depends()
+# auth_markers = ["Depends(", "login_required", "jwt_required"]
+ban_permissive_chmod = true
+max_chmod_mode = 0o755   # chmod to anything looser (0o777, 0o666) is flagged
+ban_curl_pipe_sh = true   # curl ... | sh in shell scripts and Dockerfiles
+ban_remote_exec = true    # eval "$(curl ...)", bash <(curl ...), chmod +x on unverified downloads
+ban_unpinned_dependencies = true   # requirements*.txt entries without ==
+ban_url_dependencies = true   # package.json deps on git, URLs or file: paths
 dangerous_patterns = [
     "rm -rf",
     "DROP TABLE",
@@ -280,9 +365,80 @@ secret_patterns = [
     "private_key", "privatekey",
     "access_token", "auth_token",
 ]
+
+[rules]
+# Rules to turn off entirely, e.g. ["ban-console", "sql-injection"]
+disabled = []
+
+[messages]
+# Replace a rule's message; placeholders: {file} {line} {rule} {message}
+# "ban-eval" = "{message} - see https://wiki.example.com/eval ({file}:{line})"
+
+# Turn a noisy rule off until a date; it's checked again from that day
+# [[snooze]]
+# rule = "todo-marker"
+# until = "2025-06-01"
+
+# Map more extensions to a language and pick which rules run on it, by id
+# or group ("quality", "security"); no rules list means all of them
+# [languages.js]
+# extensions = [".mjs", ".cjs"]
+# rules = ["security", "ban-console"]
+
+# Hold security-critical paths to a higher standard: issues there are raised
+# one severity level (info to warning, warning to critical), and rules here
+# are turned on for those paths only
+# [strict]
+# paths = ["auth", "payments/**/*.py"]
+# rules = ["pii-logging"]
 `, strings.TrimSuffix(config.SourceDir, "/"), formatExcludes(excludes))
 
-	return os.WriteFile("guardian_config.toml", []byte(content), 0644)
+	return os.WriteFile("guardian_config.toml", []byte(stampVersion("guardian_config.toml", content)), 0644)
+}
+
+// stampVersion adds a "guardian-version: X" comment near the top of a
+// generated file, after any shebang (and PHP's opening tag). JSON has no
+// comments, so it's left as is.
+func stampVersion(path, content string) string {
+	prefix := "#"
+	switch filepath.Ext(path) {
+	case ".json":
+		return content
+	case ".js", ".php":
+		prefix = "//"
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	at := 0
+	if at < len(lines) && strings.HasPrefix(lines[at], "#!") {
+		at++
+	}
+	if at < len(lines) && strings.TrimSpace(lines[at]) == "<?php" {
+		at++
+	}
+
+	stamp := prefix + " guardian-version: " + Version + "\n"
+	return strings.Join(lines[:at], "") + stamp + strings.Join(lines[at:], "")
+}
+
+// InstalledVersion returns the version stamped into the project's installed
+// scaffolding, or "" if nothing stamped is installed in dir
+func InstalledVersion(dir string) string {
+	for _, name := range installedScripts {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		for i, line := range strings.Split(string(data), "\n") {
+			if i >= versionStampLines {
+				break
+			}
+			if m := versionStampRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				return m[1]
+			}
+		}
+	}
+	return ""
 }
 
 func formatExcludes(excludes []string) string {
@@ -384,6 +540,31 @@ func generatePreCommitConfig(config InstallConfig) error {
         entry: php .guardian/guardian.php
         language: system
         types: [php]
+`
+	case "go":
+		if config.GoHook == "native" {
+			// The builtin engine checks just the staged files it's given
+			guardianHook = `
+  - repo: local
+    hooks:
+      - id: guardian
+        name: Guardian checks
+        entry: guardian check
+        language: system
+        types: [go]
+`
+			break
+		}
+		// guardian.sh vets the whole module, so it takes no file list
+		guardianHook = `
+  - repo: local
+    hooks:
+      - id: guardian
+        name: Guardian checks
+        entry: .guardian/guardian.sh
+        language: script
+        types: [go]
+        pass_filenames: false
 `
 	default:
 		guardianHook = `
@@ -407,6 +588,135 @@ func generatePreCommitConfig(config InstallConfig) error {
 	return os.WriteFile(".pre-commit-config.yaml", []byte(newContent), 0644)
 }
 
+// gitLabCIFile is GitLab's pipeline definition, at the repo root
+const gitLabCIFile = ".gitlab-ci.yml"
+
+// gitLabJobRe finds an existing top-level guardian job
+var gitLabJobRe = regexp.MustCompile(`(?m)^guardian:`)
+
+// generateGitLabCI writes a .gitlab-ci.yml with a guardian job, or appends
+// the job to an existing pipeline. Jobs are top-level keys, so appending
+// leaves everything already there untouched.
+func generateGitLabCI(config InstallConfig) error {
+	existingContent := ""
+	if data, err := os.ReadFile(gitLabCIFile); err == nil {
+		existingContent = string(data)
+	}
+
+	// If a guardian job is already defined, leave it alone
+	if gitLabJobRe.MatchString(existingContent) {
+		return nil
+	}
+
+	job := `guardian:
+  script:
+    - curl -fsSL guardian.sh/install | sh
+    - guardian check
+`
+
+	if existingContent == "" {
+		return os.WriteFile(gitLabCIFile, []byte(job), 0644)
+	}
+
+	newContent := strings.TrimRight(existingContent, "\n") + "\n\n" + job
+	return os.WriteFile(gitLabCIFile, []byte(newContent), 0644)
+}
+
+// huskyHookFile is the hook Husky runs before each commit
+const huskyHookFile = ".husky/pre-commit"
+
+// huskyHook runs guardian.js on the staged JS/TS files. guardian.js only
+// checks the files it's given, like it does under pre-commit.
+const huskyHook = `git diff --cached --name-only --diff-filter=ACM -z -- '*.js' '*.jsx' '*.ts' '*.tsx' | xargs -0 node .guardian/guardian.js
+`
+
+var (
+	// "prepare": "...", the npm script that installs Husky's hooks
+	prepareScriptRe = regexp.MustCompile(`"prepare"\s*:\s*"`)
+	scriptsKeyRe    = regexp.MustCompile(`"scripts"\s*:\s*\{`)
+)
+
+// generateHuskyHook adds guardian to .husky/pre-commit, creating it or
+// appending to an existing hook, and makes package.json's prepare script
+// run husky. Whatever already mentions guardian or husky is left alone.
+func generateHuskyHook() error {
+	existing := ""
+	if data, err := os.ReadFile(huskyHookFile); err == nil {
+		existing = string(data)
+	}
+	if !strings.Contains(existing, "guardian") {
+		content := huskyHook
+		if existing != "" {
+			content = strings.TrimRight(existing, "\n") + "\n" + huskyHook
+		}
+		if err := os.MkdirAll(filepath.Dir(huskyHookFile), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(huskyHookFile, []byte(content), 0755); err != nil {
+			return err
+		}
+	}
+
+	data, err := os.ReadFile("package.json")
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	updated, err := addPrepareScript(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to update package.json: %w", err)
+	}
+	if updated == string(data) {
+		return nil
+	}
+	return os.WriteFile("package.json", []byte(updated), 0644)
+}
+
+// addPrepareScript makes package.json's prepare script run husky. It edits
+// the text rather than re-encoding it, so the file's key order and
+// formatting are kept.
+func addPrepareScript(pkg string) (string, error) {
+	var parsed struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal([]byte(pkg), &parsed); err != nil {
+		return "", err
+	}
+
+	var updated string
+	prepare, hasPrepare := parsed.Scripts["prepare"]
+	switch {
+	case strings.Contains(prepare, "husky"):
+		return pkg, nil
+	case hasPrepare:
+		// Run husky first, then whatever prepare already did
+		scripts := scriptsKeyRe.FindStringIndex(pkg)[1]
+		loc := prepareScriptRe.FindStringIndex(pkg[scripts:])
+		updated = pkg[:scripts+loc[1]] + "husky && " + pkg[scripts+loc[1]:]
+	case parsed.Scripts != nil:
+		loc := scriptsKeyRe.FindStringIndex(pkg)
+		entry := `"prepare": "husky"`
+		if len(parsed.Scripts) > 0 {
+			entry += ","
+		}
+		updated = pkg[:loc[1]] + "\n    " + entry + pkg[loc[1]:]
+	default:
+		start := strings.Index(pkg, "{") + 1
+		entry := "\n  \"scripts\": {\n    \"prepare\": \"husky\"\n  }"
+		if strings.TrimSpace(pkg[start:]) != "}" {
+			entry += ","
+		}
+		updated = pkg[:start] + entry + pkg[start:]
+	}
+
+	if !json.Valid([]byte(updated)) {
+		return "", fmt.Errorf("couldn't add a prepare script; add \"prepare\": \"husky\" to scripts yourself")
+	}
+	return updated, nil
+}
+
 // Python check scripts
 const pythonCheckFileSize = `#!/usr/bin/env python3
 """Check that Python files don't exceed line limits."""
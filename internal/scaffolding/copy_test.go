@@ -1,8 +1,10 @@
 package scaffolding
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -293,6 +295,243 @@ func TestPreCommitConfig_DoesNotDuplicateHook(t *testing.T) {
 	})
 }
 
+// ============================================================================
+// SKIPPING GENERATED FILES
+// ============================================================================
+
+func TestInstall_NoPreCommit(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		if err := Install(InstallConfig{Language: "python", SkipPreCommit: true}); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+
+		if _, err := os.Stat(".pre-commit-config.yaml"); !os.IsNotExist(err) {
+			t.Error(".pre-commit-config.yaml should not be created with SkipPreCommit")
+		}
+		if _, err := os.Stat(".guardian"); err != nil {
+			t.Error(".guardian directory not created")
+		}
+		if _, err := os.Stat("guardian_config.toml"); err != nil {
+			t.Error("guardian_config.toml should still be created")
+		}
+	})
+}
+
+func TestInstall_NoConfig(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		if err := Install(InstallConfig{Language: "typescript", SkipConfig: true}); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+
+		if _, err := os.Stat("guardian_config.toml"); !os.IsNotExist(err) {
+			t.Error("guardian_config.toml should not be created with SkipConfig")
+		}
+		if _, err := os.Stat(".guardian"); err != nil {
+			t.Error(".guardian directory not created")
+		}
+		if _, err := os.Stat(".pre-commit-config.yaml"); err != nil {
+			t.Error(".pre-commit-config.yaml should still be created")
+		}
+	})
+}
+
+func TestInstall_NoConfigKeepsExistingConfig(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		existing := "[limits]\nmax_file_lines = 900\n"
+		os.WriteFile("guardian_config.toml", []byte(existing), 0644)
+
+		if err := Install(InstallConfig{Language: "go", SkipConfig: true, SkipPreCommit: true}); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+
+		content, _ := os.ReadFile("guardian_config.toml")
+		if string(content) != existing {
+			t.Errorf("existing config was changed:\n%s", content)
+		}
+		if _, err := os.Stat(".pre-commit-config.yaml"); !os.IsNotExist(err) {
+			t.Error(".pre-commit-config.yaml should not be created")
+		}
+	})
+}
+
+// ============================================================================
+// GITLAB CI GENERATION
+// ============================================================================
+
+func TestGitLabCI_CreatesNew(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		if err := generateGitLabCI(InstallConfig{Language: "python", CI: "gitlab"}); err != nil {
+			t.Fatalf("generateGitLabCI failed: %v", err)
+		}
+
+		content, err := os.ReadFile(".gitlab-ci.yml")
+		if err != nil {
+			t.Fatalf("failed to read .gitlab-ci.yml: %v", err)
+		}
+		if !strings.HasPrefix(string(content), "guardian:\n") {
+			t.Errorf("expected a top-level guardian job, got:\n%s", content)
+		}
+		if !strings.Contains(string(content), "- guardian check") {
+			t.Error("guardian job should run guardian check")
+		}
+	})
+}
+
+func TestGitLabCI_AppendsToExisting(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		existingContent := `stages:
+  - build
+  - test
+
+build:
+  stage: build
+  script:
+    - make`
+		if err := os.WriteFile(".gitlab-ci.yml", []byte(existingContent), 0644); err != nil {
+			t.Fatalf("failed to create existing pipeline: %v", err)
+		}
+
+		config := InstallConfig{Language: "python", CI: "gitlab"}
+		generateGitLabCI(config)
+		generateGitLabCI(config)
+
+		content, err := os.ReadFile(".gitlab-ci.yml")
+		if err != nil {
+			t.Fatalf("failed to read .gitlab-ci.yml: %v", err)
+		}
+
+		// Original pipeline kept as-is, job added once after it
+		if !strings.HasPrefix(string(content), existingContent+"\n\nguardian:\n") {
+			t.Errorf("existing pipeline not preserved, got:\n%s", content)
+		}
+		if n := strings.Count(string(content), "guardian:"); n != 1 {
+			t.Errorf("expected 1 guardian job, found %d", n)
+		}
+	})
+}
+
+func TestInstall_InitCIGitLab(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		if err := Install(InstallConfig{Language: "go", CI: "gitlab"}); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+		if _, err := os.Stat(".gitlab-ci.yml"); err != nil {
+			t.Error(".gitlab-ci.yml not created")
+		}
+	})
+}
+
+func TestInstall_NoCIByDefault(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		if err := Install(InstallConfig{Language: "go"}); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+		if _, err := os.Stat(".gitlab-ci.yml"); !os.IsNotExist(err) {
+			t.Error(".gitlab-ci.yml should only be written with CI: gitlab")
+		}
+	})
+}
+
+// ============================================================================
+// HUSKY HOOK
+// ============================================================================
+
+func TestInstall_HuskyHook(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		pkg := `{
+  "name": "app",
+  "scripts": {
+    "build": "tsc",
+    "test": "vitest"
+  }
+}
+`
+		os.WriteFile("package.json", []byte(pkg), 0644)
+
+		if err := Install(InstallConfig{Language: "typescript", Hook: "husky", SkipPreCommit: true}); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+
+		hook, err := os.ReadFile(".husky/pre-commit")
+		if err != nil {
+			t.Fatalf(".husky/pre-commit not created: %v", err)
+		}
+		if !strings.Contains(string(hook), "node .guardian/guardian.js") {
+			t.Errorf("hook should run guardian.js, got:\n%s", hook)
+		}
+		if info, _ := os.Stat(".husky/pre-commit"); info.Mode().Perm()&0o100 == 0 {
+			t.Errorf("hook should be executable, mode %v", info.Mode())
+		}
+
+		content, _ := os.ReadFile("package.json")
+		var got struct {
+			Name    string            `json:"name"`
+			Scripts map[string]string `json:"scripts"`
+		}
+		if err := json.Unmarshal(content, &got); err != nil {
+			t.Fatalf("package.json no longer valid: %v\n%s", err, content)
+		}
+		want := map[string]string{"prepare": "husky", "build": "tsc", "test": "vitest"}
+		if got.Name != "app" || !reflect.DeepEqual(got.Scripts, want) {
+			t.Errorf("expected existing scripts kept and prepare added, got:\n%s", content)
+		}
+		if _, err := os.Stat(".pre-commit-config.yaml"); !os.IsNotExist(err) {
+			t.Error(".pre-commit-config.yaml should not be created alongside Husky")
+		}
+	})
+}
+
+func TestHuskyHook_SkipsWhenConfigured(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.MkdirAll(".husky", 0755)
+		os.WriteFile(".husky/pre-commit", []byte("npx lint-staged\n"), 0755)
+		pkg := `{"scripts": {"prepare": "husky"}}`
+		os.WriteFile("package.json", []byte(pkg), 0644)
+
+		generateHuskyHook()
+		generateHuskyHook()
+
+		hook, _ := os.ReadFile(".husky/pre-commit")
+		if !strings.HasPrefix(string(hook), "npx lint-staged\n") || strings.Count(string(hook), "guardian.js") != 1 {
+			t.Errorf("expected guardian appended once to the existing hook, got:\n%s", hook)
+		}
+		if content, _ := os.ReadFile("package.json"); string(content) != pkg {
+			t.Errorf("package.json with a husky prepare script should be untouched, got:\n%s", content)
+		}
+	})
+}
+
+func TestAddPrepareScript(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  string
+		want string
+	}{
+		{"no scripts", `{"name": "app"}`, "husky"},
+		{"empty object", `{}`, "husky"},
+		{"empty scripts", `{"scripts": {}}`, "husky"},
+		{"existing prepare", `{"scripts": {"prepare": "npm run build"}}`, "husky && npm run build"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := addPrepareScript(tt.pkg)
+			if err != nil {
+				t.Fatalf("addPrepareScript failed: %v", err)
+			}
+			var got struct {
+				Scripts map[string]string `json:"scripts"`
+			}
+			if err := json.Unmarshal([]byte(updated), &got); err != nil {
+				t.Fatalf("invalid JSON: %v\n%s", err, updated)
+			}
+			if got.Scripts["prepare"] != tt.want {
+				t.Errorf("prepare = %q, want %q in:\n%s", got.Scripts["prepare"], tt.want, updated)
+			}
+		})
+	}
+}
+
 // ============================================================================
 // CLEANUP ON FAILURE
 // ============================================================================
@@ -508,3 +747,192 @@ func TestInstall_SpecialCharsInExcludes(t *testing.T) {
 		}
 	})
 }
+
+// ============================================================================
+// VERSION STAMP
+// ============================================================================
+
+func TestInstall_StampsVersion(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		orig := Version
+		Version = "1.2.3"
+		defer func() { Version = orig }()
+
+		if err := Install(InstallConfig{Language: "python"}); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+
+		if got := InstalledVersion(dir); got != "1.2.3" {
+			t.Errorf("expected installed version 1.2.3, got %q", got)
+		}
+
+		content, _ := os.ReadFile(".guardian/guardian.py")
+		lines := strings.Split(string(content), "\n")
+		if lines[0] != "#!/usr/bin/env python3" || lines[1] != "# guardian-version: 1.2.3" {
+			t.Errorf("stamp should follow the shebang, got:\n%s\n%s", lines[0], lines[1])
+		}
+
+		config, _ := os.ReadFile("guardian_config.toml")
+		if !strings.HasPrefix(string(config), "# guardian-version: 1.2.3\n") {
+			t.Errorf("config should be stamped, got:\n%s", config)
+		}
+	})
+}
+
+func TestStampVersion_PHPAfterOpenTag(t *testing.T) {
+	orig := Version
+	Version = "1.2.3"
+	defer func() { Version = orig }()
+
+	got := stampVersion("guardian.php", "#!/usr/bin/env php\n<?php\ndeclare(strict_types=1);\n")
+	want := "#!/usr/bin/env php\n<?php\n// guardian-version: 1.2.3\ndeclare(strict_types=1);\n"
+	if got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+	if got := stampVersion("guardian.config.json", "{}\n"); got != "{}\n" {
+		t.Errorf("JSON should not be stamped, got %q", got)
+	}
+}
+
+func TestInstalledVersion_DetectsMismatch(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		if got := InstalledVersion(dir); got != "" {
+			t.Errorf("expected no version before install, got %q", got)
+		}
+
+		os.MkdirAll(".guardian", 0755)
+		os.WriteFile(".guardian/guardian.js", []byte("#!/usr/bin/env node\n// guardian-version: 0.0.9\n"), 0755)
+
+		got := InstalledVersion(dir)
+		if got != "0.0.9" {
+			t.Fatalf("expected 0.0.9, got %q", got)
+		}
+		if got == Version {
+			t.Error("an older stamp should not match the current version")
+		}
+	})
+}
+
+// ============================================================================
+// EDITED SCRIPTS
+// ============================================================================
+
+func TestInstall_KeepsEditedScriptsUnlessForced(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		orig := Version
+		defer func() { Version = orig }()
+
+		Version = "1.0.0"
+		if err := Install(InstallConfig{Language: "python", SkipPreCommit: true}); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+		edited := ".guardian/check_dangerous.py"
+		custom := "#!/usr/bin/env python3\n# our own patterns\n"
+		os.WriteFile(edited, []byte(custom), 0755)
+
+		// An upgrade rewrites untouched scripts but keeps the edited one
+		Version = "1.1.0"
+		if got := EditedFiles(InstallConfig{Language: "python"}); !reflect.DeepEqual(got, []string{edited}) {
+			t.Errorf("expected only %s reported as edited, got %v", edited, got)
+		}
+		if err := Install(InstallConfig{Language: "python", SkipPreCommit: true, SkipConfig: true}); err != nil {
+			t.Fatalf("re-install failed: %v", err)
+		}
+		if content, _ := os.ReadFile(edited); string(content) != custom {
+			t.Errorf("edited script should be untouched, got:\n%s", content)
+		}
+		if content, _ := os.ReadFile(".guardian/check_file_size.py"); !strings.Contains(string(content), "guardian-version: 1.1.0") {
+			t.Errorf("unmodified script should be updated, got:\n%s", content)
+		}
+
+		// The kept script is still recognized as edited next time
+		if got := EditedFiles(InstallConfig{Language: "python"}); !reflect.DeepEqual(got, []string{edited}) {
+			t.Errorf("expected %s still reported as edited, got %v", edited, got)
+		}
+
+		if err := Install(InstallConfig{Language: "python", SkipPreCommit: true, SkipConfig: true, Force: true}); err != nil {
+			t.Fatalf("forced install failed: %v", err)
+		}
+		if content, _ := os.ReadFile(edited); string(content) == custom {
+			t.Error("--force should overwrite the edited script")
+		}
+		if got := EditedFiles(InstallConfig{Language: "python"}); len(got) != 0 {
+			t.Errorf("expected nothing edited after a forced install, got %v", got)
+		}
+	})
+}
+
+func TestInstall_RestoresDeletedAndKeepsUnrecordedScripts(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		// A script written before the manifest existed, or by hand
+		os.MkdirAll(".guardian", 0755)
+		os.WriteFile(".guardian/guardian.js", []byte("// hand written\n"), 0755)
+
+		if err := Install(InstallConfig{Language: "typescript", SkipPreCommit: true}); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+		if content, _ := os.ReadFile(".guardian/guardian.js"); string(content) != "// hand written\n" {
+			t.Errorf("an unrecorded script that differs should be kept, got:\n%s", content)
+		}
+
+		os.Remove(".guardian/guardian.config.json")
+		if err := Install(InstallConfig{Language: "typescript", SkipPreCommit: true, SkipConfig: true}); err != nil {
+			t.Fatalf("re-install failed: %v", err)
+		}
+		if _, err := os.Stat(".guardian/guardian.config.json"); err != nil {
+			t.Errorf("a deleted script should be restored: %v", err)
+		}
+	})
+}
+
+func TestPreCommitConfig_GoHook(t *testing.T) {
+	tests := []struct {
+		name    string
+		goHook  string
+		want    []string
+		notWant []string
+	}{
+		{"default runs the script", "", []string{"entry: .guardian/guardian.sh", "types: [go]", "pass_filenames: false"}, []string{"entry: guardian check"}},
+		{"script", "script", []string{"entry: .guardian/guardian.sh", "types: [go]"}, []string{"entry: guardian check"}},
+		{"native runs guardian check", "native", []string{"entry: guardian check", "language: system", "types: [go]"}, []string{"guardian.sh", "pass_filenames"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withTempDir(t, func(dir string) {
+				if err := generatePreCommitConfig(InstallConfig{Language: "go", GoHook: tt.goHook}); err != nil {
+					t.Fatalf("generatePreCommitConfig failed: %v", err)
+				}
+				content, err := os.ReadFile(".pre-commit-config.yaml")
+				if err != nil {
+					t.Fatalf("failed to read config: %v", err)
+				}
+				for _, want := range tt.want {
+					if !strings.Contains(string(content), want) {
+						t.Errorf("missing %q in:\n%s", want, content)
+					}
+				}
+				for _, notWant := range tt.notWant {
+					if strings.Contains(string(content), notWant) {
+						t.Errorf("unexpected %q in:\n%s", notWant, content)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestPreCommitConfig_PHPTypes(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		if err := generatePreCommitConfig(InstallConfig{Language: "php", Stack: "php-laravel"}); err != nil {
+			t.Fatalf("generatePreCommitConfig failed: %v", err)
+		}
+		content, err := os.ReadFile(".pre-commit-config.yaml")
+		if err != nil {
+			t.Fatalf("failed to read config: %v", err)
+		}
+		if !strings.Contains(string(content), "entry: php .guardian/guardian.php") || !strings.Contains(string(content), "types: [php]") {
+			t.Errorf("PHP hook should run guardian.php on php files:\n%s", content)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package scaffolding
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ManifestFile records a hash of each script as it was installed, so a
+// re-install can tell the user's edits from its own output
+const ManifestFile = ".guardian/manifest.json"
+
+// manifest maps each installed script's path to the hash of what was written
+type manifest map[string]string
+
+func loadManifest() manifest {
+	m := make(manifest)
+	if data, err := os.ReadFile(ManifestFile); err == nil {
+		json.Unmarshal(data, &m)
+	}
+	return m
+}
+
+func (m manifest) save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ManifestFile, append(data, '\n'), 0644)
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// edited reports whether the file at path has been changed since it was
+// installed. A file that isn't in the manifest, e.g. from before it existed
+// or written by hand, counts as edited unless it already matches content.
+func (m manifest) edited(path, content string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	if string(data) == content {
+		return false
+	}
+	return m[filepath.ToSlash(path)] != contentHash(data)
+}
+
+// writeScaffold writes a generated script, stamped with the version, and
+// records it in the manifest. A script the user has edited is left alone
+// unless force is set.
+func writeScaffold(path, content string, perm os.FileMode, force bool) error {
+	content = stampVersion(path, content)
+	m := loadManifest()
+	if !force && m.edited(path, content) {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		return err
+	}
+	m[filepath.ToSlash(path)] = contentHash([]byte(content))
+	return m.save()
+}
+
+// EditedFiles lists the scripts Install would keep because the user has
+// changed them since they were installed. Install with Force overwrites
+// them.
+func EditedFiles(config InstallConfig) []string {
+	files, ok := languageFiles[config.Language]
+	if !ok {
+		files = languageFiles["python"] // Install's default
+	}
+	m := loadManifest()
+	var edited []string
+	for path, content := range files {
+		if m.edited(path, stampVersion(path, content)) {
+			edited = append(edited, path)
+		}
+	}
+	sort.Strings(edited)
+	return edited
+}
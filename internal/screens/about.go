@@ -5,13 +5,22 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guardian-sh/guardian/internal/checks"
+	"github.com/guardian-sh/guardian/internal/config"
 	"github.com/guardian-sh/guardian/internal/ui"
 )
 
-type AboutModel struct{}
+type AboutModel struct {
+	cfg *config.Config // For each rule's effective severity
+}
 
 func NewAbout() AboutModel {
-	return AboutModel{}
+	cfg, err := config.Load(".")
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	return AboutModel{cfg: cfg}
 }
 
 func (m AboutModel) Init() tea.Cmd {
@@ -31,6 +40,9 @@ func (m AboutModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m AboutModel) View() string {
 	var s strings.Builder
+	if m.cfg == nil {
+		m.cfg = config.DefaultConfig()
+	}
 
 	s.WriteString(ui.SmallLogo())
 	s.WriteString("\n\n")
@@ -46,33 +58,36 @@ func (m AboutModel) View() string {
 	s.WriteString(ui.DimStyle.Render("(no AI, <200ms):"))
 	s.WriteString("\n\n")
 
-	freeChecks := []struct {
-		name string
-		desc string
-	}{
-		{"file-size", "Files over 500 lines"},
-		{"func-size", "Functions over 50 lines"},
-		{"mock-data", "test_, fake_, example@, placeholder"},
-		{"ban-print", "print() statements"},
-		{"ban-except", "Bare except: blocks"},
-		{"ban-eval", "eval(), exec()"},
-		{"ban-star", "from x import *"},
-		{"mutable-default", "def foo(items=[])"},
-		{"todo-markers", "TODO, FIXME, HACK"},
-		{"dangerous-cmds", "rm -rf, DROP TABLE, DELETE FROM"},
-		{"secret-patterns", "api_key=, password=, hardcoded tokens"},
-		{"subprocess-shell", "shell=True"},
-		{"sql-injection", "f-strings in SQL"},
+	// Listed from the registry, with the severity each rule reports at
+	// under the loaded config ("off" when it's disabled)
+	for i, rule := range checks.Rules {
+		prefix := "├─"
+		if i == len(checks.Rules)-1 && len(m.cfg.CustomRules) == 0 {
+			prefix = "└─"
+		}
+		severity := checks.EffectiveSeverity(rule.ID, m.cfg)
+		s.WriteString(ui.DimStyle.Render("    " + prefix + " "))
+		s.WriteString(ui.HighlightStyle.Render(padRight(rule.ID, 26)))
+		s.WriteString(aboutSeverityStyle(severity).Render(padRight(severity, 10)))
+		s.WriteString(ui.NormalStyle.Render(rule.Description))
+		s.WriteString("\n")
 	}
-
-	for i, check := range freeChecks {
+	for i, rule := range m.cfg.CustomRules {
 		prefix := "├─"
-		if i == len(freeChecks)-1 {
+		if i == len(m.cfg.CustomRules)-1 {
 			prefix = "└─"
 		}
+		severity := rule.Severity
+		if severity == "" {
+			severity = "warning"
+		}
+		if m.cfg.IsRuleDisabled(rule.ID) {
+			severity = "off"
+		}
 		s.WriteString(ui.DimStyle.Render("    " + prefix + " "))
-		s.WriteString(ui.HighlightStyle.Render(padRight(check.name, 18)))
-		s.WriteString(ui.NormalStyle.Render(check.desc))
+		s.WriteString(ui.HighlightStyle.Render(padRight(rule.ID, 26)))
+		s.WriteString(aboutSeverityStyle(severity).Render(padRight(severity, 10)))
+		s.WriteString(ui.NormalStyle.Render("custom rule"))
 		s.WriteString("\n")
 	}
 
@@ -97,7 +112,7 @@ func (m AboutModel) View() string {
 			prefix = "└─"
 		}
 		s.WriteString(ui.DimStyle.Render("    " + prefix + " "))
-		s.WriteString(ui.HighlightStyle.Render(padRight(feature.name, 18)))
+		s.WriteString(ui.HighlightStyle.Render(padRight(feature.name, 36)))
 		s.WriteString(ui.NormalStyle.Render(feature.desc))
 		s.WriteString("\n")
 	}
@@ -122,6 +137,20 @@ func (m AboutModel) View() string {
 	return s.String()
 }
 
+// aboutSeverityStyle colours a rule's severity in the About list
+func aboutSeverityStyle(severity string) lipgloss.Style {
+	switch severity {
+	case "critical":
+		return ui.CriticalStyle
+	case "warning":
+		return ui.WarningIssueStyle
+	case "off":
+		return ui.DimStyle
+	default:
+		return ui.InfoIssueStyle
+	}
+}
+
 func padRight(s string, length int) string {
 	if len(s) >= length {
 		return s
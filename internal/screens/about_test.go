@@ -0,0 +1,76 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/guardian-sh/guardian/internal/checks"
+	"github.com/guardian-sh/guardian/internal/ui"
+)
+
+// aboutRuleRowRe matches a rule row in the About view: tree prefix, rule id,
+// severity
+var aboutRuleRowRe = regexp.MustCompile(`(?m)^\s+[├└]─ (\S+)\s+(critical|warning|info|off)\b`)
+
+func aboutRules(t *testing.T) map[string]string {
+	t.Helper()
+	ui.ConfigureColor(true)
+	rows := make(map[string]string)
+	for _, m := range aboutRuleRowRe.FindAllStringSubmatch(NewAbout().View(), -1) {
+		rows[m[1]] = m[2]
+	}
+	return rows
+}
+
+func TestAbout_ListsExactlyTheRegisteredRules(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		rows := aboutRules(t)
+
+		for _, rule := range checks.Rules {
+			if _, ok := rows[rule.ID]; !ok {
+				t.Errorf("About doesn't list registered rule %s", rule.ID)
+			}
+		}
+		for id := range rows {
+			if _, ok := checks.LookupRule(id); !ok {
+				t.Errorf("About lists %s, which isn't a registered rule", id)
+			}
+		}
+		if rows["ban-eval"] != "critical" || rows["ban-print"] != "info" {
+			t.Errorf("expected default severities, got ban-eval=%s ban-print=%s", rows["ban-eval"], rows["ban-print"])
+		}
+	})
+}
+
+func TestAbout_ShowsEffectiveSeverityFromConfig(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte(`
+[quality]
+ban_print = false
+
+[rules]
+disabled = ["insecure-cors"]
+
+[[custom_rules]]
+id = "no-debugger"
+pattern = "debugger"
+severity = "critical"
+`), 0644)
+
+		rows := aboutRules(t)
+		for _, id := range []string{"ban-print", "ban-console", "insecure-cors"} {
+			if rows[id] != "off" {
+				t.Errorf("expected %s to show as off, got %q", id, rows[id])
+			}
+		}
+		if rows["no-debugger"] != "critical" {
+			t.Errorf("expected the custom rule with its severity, got %q", rows["no-debugger"])
+		}
+		if !strings.Contains(NewAbout().View(), "custom rule") {
+			t.Error("expected custom rules to be marked as such")
+		}
+	})
+}
@@ -24,6 +24,14 @@ const (
 	AIStepResults
 )
 
+// aiMenuItems are the actions offered once a key is validated. Local Scan
+// needs no key, so the key step offers it too.
+var aiMenuItems = []string{
+	"Smart Scan      Analyze this project, generate custom config",
+	"Local Scan      Analyze locally, no API calls",
+	"Back            Return to main menu",
+}
+
 type AISetupModel struct {
 	step        AISetupStep
 	keyInput    textinput.Model
@@ -55,18 +63,7 @@ func NewAISetup() AISetupModel {
 }
 
 func loadExistingKey() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return ""
-	}
-
-	keyPath := filepath.Join(homeDir, ".guardian", "credentials")
-	data, err := os.ReadFile(keyPath)
-	if err != nil {
-		return ""
-	}
-
-	return strings.TrimSpace(string(data))
+	return ai.LoadKey()
 }
 
 func saveKey(key string) error {
@@ -128,6 +125,10 @@ func (m AISetupModel) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.step = AIStepValidating
 			return m, validateKey(m.keyInput.Value())
 		}
+	case key.Matches(msg, keys.Tab):
+		// No key, or no wish to send code anywhere
+		m.step = AIStepScanning
+		return m, doLocalScan()
 	case key.Matches(msg, keys.Back), key.Matches(msg, keys.Quit):
 		return m, goBack()
 	}
@@ -144,14 +145,18 @@ func (m AISetupModel) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.cursor--
 		}
 	case key.Matches(msg, keys.Down):
-		if m.cursor < 1 {
+		if m.cursor < len(aiMenuItems)-1 {
 			m.cursor++
 		}
 	case key.Matches(msg, keys.Enter):
-		if m.cursor == 0 {
+		switch m.cursor {
+		case 0:
 			m.step = AIStepScanning
 			return m, doScan(m.keyInput.Value())
-		} else {
+		case 1:
+			m.step = AIStepScanning
+			return m, doLocalScan()
+		default:
 			return m, goBack()
 		}
 	case key.Matches(msg, keys.Back), key.Matches(msg, keys.Quit):
@@ -166,7 +171,11 @@ func (m AISetupModel) updateResults(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Apply configuration and go to interactive mode
 		return m, switchScreen(ScreenInteractive, nil)
 	case "n", "N", "esc":
+		// A local scan from the key step goes back there
 		m.step = AIStepMenu
+		if !m.validKey {
+			m.step = AIStepKey
+		}
 		return m, nil
 	case "q":
 		return m, tea.Quit
@@ -228,7 +237,7 @@ func (m AISetupModel) viewKey() string {
 	s.WriteString(ui.DimStyle.Render("    ⚠ Key stored in ~/.guardian/credentials (plaintext)"))
 	s.WriteString("\n\n")
 
-	s.WriteString(ui.DimStyle.Render("  enter continue · esc back"))
+	s.WriteString(ui.DimStyle.Render("  enter continue · tab local scan (no API) · esc back"))
 
 	return s.String()
 }
@@ -258,8 +267,7 @@ func (m AISetupModel) viewMenu() string {
 	s.WriteString(ui.NormalStyle.Render("  AI features enabled:"))
 	s.WriteString("\n\n")
 
-	items := []string{"Smart Scan      Analyze this project, generate custom config", "Back            Return to main menu"}
-	for i, item := range items {
+	for i, item := range aiMenuItems {
 		if i == m.cursor {
 			s.WriteString(ui.CursorStyle.Render("  ❯ ● "))
 			parts := strings.SplitN(item, "  ", 2)
@@ -410,3 +418,9 @@ func doScan(apiKey string) tea.Cmd {
 		return scanCompleteMsg{results: results, err: err}
 	}
 }
+
+func doLocalScan() tea.Cmd {
+	return func() tea.Msg {
+		return scanCompleteMsg{results: ai.ScanLocal(".")}
+	}
+}
@@ -0,0 +1,37 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAISetup_LocalScanWithoutKey(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		t.Setenv("HOME", dir)
+		os.WriteFile(filepath.Join(dir, "pyproject.toml"), []byte("[project]\n"), 0644)
+
+		next, cmd := NewAISetup().Update(tea.KeyMsg{Type: tea.KeyTab})
+		m := next.(AISetupModel)
+		if m.step != AIStepScanning || cmd == nil {
+			t.Fatalf("expected tab to start a local scan from the key step, got step %d", m.step)
+		}
+
+		next, _ = m.Update(cmd())
+		m = next.(AISetupModel)
+		if m.step != AIStepResults || m.err != nil || m.scanResults == nil {
+			t.Fatalf("expected local scan results, got step %d (err %v)", m.step, m.err)
+		}
+		if m.scanResults.Language != "Python" {
+			t.Errorf("expected Python detected, got %q", m.scanResults.Language)
+		}
+
+		// Without a validated key there's no menu to return to
+		next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if step := next.(AISetupModel).step; step != AIStepKey {
+			t.Errorf("expected esc to return to the key step, got step %d", step)
+		}
+	})
+}
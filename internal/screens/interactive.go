@@ -1,16 +1,20 @@
 package screens
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/guardian-sh/guardian/internal/ai"
 	"github.com/guardian-sh/guardian/internal/checks"
+	"github.com/guardian-sh/guardian/internal/config"
 	"github.com/guardian-sh/guardian/internal/prompts"
 	"github.com/guardian-sh/guardian/internal/ui"
 )
@@ -25,6 +29,7 @@ const (
 	ModePromptResult
 	ModeExplain
 	ModeDryRun
+	ModeRunning
 )
 
 type InteractiveModel struct {
@@ -35,9 +40,20 @@ type InteractiveModel struct {
 	helpCursor   int
 	promptCursor int
 	promptText   string
-	explainIdx int
-	dryRunInfo *checks.DryRunInfo
-	lastError  string // Stores last error message for display
+	copyErr      error        // Why the prompt couldn't be copied, if it couldn't
+	promptNote   string       // Why a prompt is shown instead of an AI fix, if it is
+	fixFor       checks.Issue // The issue fixPatch and fixing refer to
+	fixPatch     string       // AI-suggested patch, shown but never applied
+	fixing       bool         // Waiting on the AI provider
+	explainIdx   int
+	dryRunInfo   *checks.DryRunInfo
+	dryRunFiles  bool               // Dry run lists every file instead of per-directory totals
+	lastError    string             // Stores last error message for display
+	notice       string             // Confirmation shown above results (e.g. rule disabled)
+	failed       []checks.FileError // Files skipped (too large, or a check crashed)
+	runID        int                // Bumped per /run, so a cancelled run's late messages are ignored
+	cancelRun    context.CancelFunc
+	progress     checks.Progress
 	// NOTE: QuickStart config (excludeDirs, sourceDir) not yet passed to checks.
 	// Currently uses hardcoded defaults. Enhancement for v1.1.
 }
@@ -80,22 +96,61 @@ func (m InteractiveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateExplain(msg)
 		case ModeDryRun:
 			return m.updateDryRun(msg)
+		case ModeRunning:
+			return m.updateRunning(msg)
 		}
 
+	case checksProgressMsg:
+		if msg.run == m.runID {
+			m.progress = msg.progress
+		}
+		// Keep reading until the run finishes, even a cancelled one
+		return m, waitForChecks(msg.updates)
+
 	case checksCompleteMsg:
-		m.issues = msg.issues
+		if msg.run != m.runID || msg.err != nil {
+			// Cancelled, or superseded by a newer run
+			return m, nil
+		}
+		m.cancelRun = nil
+		// Keep each file's issues together so the cursor follows display order
+		m.issues = checks.SortIssues(msg.issues)
+		m.failed = msg.failed
+		if m.cursor >= len(m.issues) {
+			m.cursor = 0
+		}
 		m.mode = ModeResults
 		return m, nil
 
+	case ruleDisabledMsg:
+		if msg.err != nil {
+			m.notice = ""
+			m.lastError = msg.err.Error()
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("Disabled %s in %s - re-running checks", msg.rule, msg.path)
+		return m.startChecks()
+
 	case dryRunCompleteMsg:
 		m.dryRunInfo = msg.info
+		m.dryRunFiles = false
 		m.mode = ModeDryRun
 		return m, nil
 
 	case promptGeneratedMsg:
 		m.promptText = msg.prompt
+		m.copyErr = msg.copyErr
+		m.promptNote = msg.note
+		if msg.note != "" {
+			m.fixing = false
+		}
 		m.mode = ModePromptResult
-		clipboard.WriteAll(msg.prompt)
+		return m, nil
+
+	case fixSuggestedMsg:
+		m.fixFor = msg.issue
+		m.fixPatch = msg.patch
+		m.fixing = false
 		return m, nil
 
 	case configOpenedMsg:
@@ -125,11 +180,12 @@ func (m InteractiveModel) updateCommand(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m InteractiveModel) handleCommand(cmd string) (tea.Model, tea.Cmd) {
-	// Clear any previous error when user enters a new command
+	// Clear any previous error or notice when user enters a new command
 	m.lastError = ""
+	m.notice = ""
 	switch strings.ToLower(cmd) {
 	case "/run", "run":
-		return m, runChecks()
+		return m.startChecks()
 	case "/dry-run", "dry-run", "/dryrun", "dryrun":
 		return m, runDryRun()
 	case "/help", "help", "?":
@@ -168,6 +224,35 @@ func (m InteractiveModel) handleCommand(cmd string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// startChecks begins a cancellable run, showing progress until it finishes
+func (m InteractiveModel) startChecks() (tea.Model, tea.Cmd) {
+	if m.cancelRun != nil {
+		m.cancelRun()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.runID++
+	m.cancelRun = cancel
+	m.progress = checks.Progress{}
+	m.mode = ModeRunning
+	return m, runChecks(ctx, m.runID)
+}
+
+func (m InteractiveModel) updateRunning(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Back), key.Matches(msg, keys.Quit):
+		if m.cancelRun != nil {
+			m.cancelRun()
+			m.cancelRun = nil
+		}
+		m.notice = ""
+		m.mode = ModeCommand
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
 func (m InteractiveModel) updateResults(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, keys.Back):
@@ -177,6 +262,19 @@ func (m InteractiveModel) updateResults(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 	}
 
+	switch {
+	case key.Matches(msg, keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+	case key.Matches(msg, keys.Down):
+		if m.cursor < len(m.issues)-1 {
+			m.cursor++
+		}
+		return m, nil
+	}
+
 	cmd := strings.ToLower(msg.String())
 	switch cmd {
 	case "p":
@@ -184,9 +282,13 @@ func (m InteractiveModel) updateResults(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.promptCursor = 0
 	case "e":
 		if len(m.issues) > 0 {
-			m.explainIdx = 0
+			m.explainIdx = m.cursor
 			m.mode = ModeExplain
 		}
+	case "d":
+		if m.cursor < len(m.issues) {
+			return m, disableRule(m.issues[m.cursor].Rule)
+		}
 	}
 
 	return m, nil
@@ -267,12 +369,27 @@ func (m InteractiveModel) updateExplain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.explainIdx < len(m.issues) {
 			return m, generatePromptForIssue(m.issues[m.explainIdx])
 		}
+	case msg.String() == "d":
+		if m.explainIdx < len(m.issues) {
+			return m, disableRule(m.issues[m.explainIdx].Rule)
+		}
+	case msg.String() == "f":
+		if m.explainIdx < len(m.issues) && !m.fixing {
+			issue := m.issues[m.explainIdx]
+			m.fixFor = issue
+			m.fixPatch = ""
+			m.fixing = true
+			return m, suggestFix(issue)
+		}
 	}
 	return m, nil
 }
 
 func (m InteractiveModel) updateDryRun(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
+	case key.Matches(msg, keys.Tab):
+		m.dryRunFiles = !m.dryRunFiles
+		return m, nil
 	case key.Matches(msg, keys.Back), key.Matches(msg, keys.Quit), key.Matches(msg, keys.Enter):
 		m.mode = ModeCommand
 		return m, nil
@@ -298,11 +415,33 @@ func (m InteractiveModel) View() string {
 		s.WriteString(m.viewExplain())
 	case ModeDryRun:
 		s.WriteString(m.viewDryRun())
+	case ModeRunning:
+		s.WriteString(m.viewRunning())
 	}
 
 	return s.String()
 }
 
+func (m InteractiveModel) viewRunning() string {
+	var s strings.Builder
+
+	s.WriteString(ui.SmallLogo())
+	s.WriteString("\n\n")
+
+	s.WriteString(ui.TitleStyle.Render("  Checking your code..."))
+	s.WriteString("\n\n")
+	if m.progress.Checked > 0 {
+		s.WriteString(ui.NormalStyle.Render(fmt.Sprintf("  %d files checked", m.progress.Checked)))
+		s.WriteString("\n")
+		s.WriteString(ui.DimStyle.Render("  " + m.progress.File))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+
+	s.WriteString(ui.DimStyle.Render("  esc cancel"))
+	return s.String()
+}
+
 func (m InteractiveModel) viewCommand() string {
 	var s strings.Builder
 
@@ -348,6 +487,18 @@ func (m InteractiveModel) viewCommand() string {
 func (m InteractiveModel) viewResults() string {
 	var s strings.Builder
 
+	if m.notice != "" {
+		s.WriteString(ui.Success(m.notice))
+		s.WriteString("\n\n")
+	}
+	for _, fe := range m.failed {
+		s.WriteString(ui.Warning(fmt.Sprintf("%s skipped - %s", fe.File, fe.Err)))
+		s.WriteString("\n")
+	}
+	if len(m.failed) > 0 {
+		s.WriteString("\n")
+	}
+
 	if len(m.issues) == 0 {
 		headerBox := ui.HeaderBox.Render(ui.TitleStyle.Render("GUARDIAN") + ui.DimStyle.Render(" · ") + ui.SuccessStyle.Render("No issues found"))
 		s.WriteString(headerBox)
@@ -382,28 +533,34 @@ func (m InteractiveModel) viewResults() string {
 	s.WriteString(headerBox)
 	s.WriteString("\n")
 
-	for file, issues := range fileIssues {
-		s.WriteString("\n")
-		s.WriteString(ui.FilePathStyle.Render("  " + file))
-		s.WriteString("\n")
+	// Issues are sorted by file, so a new group starts whenever the file changes
+	for i, issue := range m.issues {
+		if i == 0 || issue.File != m.issues[i-1].File {
+			s.WriteString("\n")
+			s.WriteString(ui.FilePathStyle.Render("  " + issue.File))
+			s.WriteString("\n")
+		}
 
-		for _, issue := range issues {
+		if i == m.cursor {
+			s.WriteString(ui.CursorStyle.Render("  ❯ "))
+			s.WriteString(ui.LineNumStyle.Render(fmt.Sprintf(":%d", issue.Line)))
+		} else {
 			s.WriteString(ui.LineNumStyle.Render(fmt.Sprintf("    :%d", issue.Line)))
-			s.WriteString("   ")
-
-			switch issue.Severity {
-			case "critical":
-				s.WriteString(ui.CriticalStyle.Render(fmt.Sprintf("[%s]", issue.Rule)))
-			case "warning":
-				s.WriteString(ui.WarningIssueStyle.Render(fmt.Sprintf("[%s]", issue.Rule)))
-			default:
-				s.WriteString(ui.InfoIssueStyle.Render(fmt.Sprintf("[%s]", issue.Rule)))
-			}
+		}
+		s.WriteString("   ")
 
-			s.WriteString("  ")
-			s.WriteString(ui.NormalStyle.Render(issue.Message))
-			s.WriteString("\n")
+		switch issue.Severity {
+		case "critical":
+			s.WriteString(ui.CriticalStyle.Render(fmt.Sprintf("[%s]", issue.Rule)))
+		case "warning":
+			s.WriteString(ui.WarningIssueStyle.Render(fmt.Sprintf("[%s]", issue.Rule)))
+		default:
+			s.WriteString(ui.InfoIssueStyle.Render(fmt.Sprintf("[%s]", issue.Rule)))
 		}
+
+		s.WriteString("  ")
+		s.WriteString(ui.NormalStyle.Render(issue.Message))
+		s.WriteString("\n")
 	}
 
 	s.WriteString("\n")
@@ -432,7 +589,7 @@ func (m InteractiveModel) viewResults() string {
 	s.WriteString(ui.DimStyle.Render("  Explain issue N in detail"))
 	s.WriteString("\n\n")
 
-	s.WriteString(ui.DimStyle.Render("  p prompt · e explain · esc back"))
+	s.WriteString(ui.DimStyle.Render("  ↑/↓ select · p prompt · e explain · d disable rule · esc back"))
 
 	return s.String()
 }
@@ -514,18 +671,36 @@ func (m InteractiveModel) viewPromptResult() string {
 	var s strings.Builder
 
 	promptHeader := ui.PromptHeaderStyle.Render("COPY THIS INTO CLAUDE")
-	promptBox := ui.PromptBoxStyle.Render(m.promptText)
-
 	s.WriteString(promptHeader)
 	s.WriteString("\n")
-	s.WriteString(promptBox)
-	s.WriteString("\n\n")
 
-	s.WriteString(ui.Success("Copied to clipboard"))
-	s.WriteString("\n\n")
+	if m.promptNote != "" {
+		s.WriteString(ui.Warning(m.promptNote))
+		s.WriteString("\n\n")
+	}
 
-	s.WriteString(ui.NormalStyle.Render("  Now paste this into Claude Code."))
-	s.WriteString("\n\n")
+	if m.copyErr != nil {
+		// No border or padding, so a mouse selection copies just the prompt
+		s.WriteString(ui.Warning(fmt.Sprintf("Couldn't copy to clipboard (%v)", m.copyErr)))
+		s.WriteString("\n")
+		s.WriteString(ui.NormalStyle.Render("  Select the text between the lines and copy it yourself."))
+		s.WriteString("\n\n")
+		s.WriteString(ui.DimStyle.Render(strings.Repeat("─", 40)))
+		s.WriteString("\n")
+		s.WriteString(m.promptText)
+		s.WriteString("\n")
+		s.WriteString(ui.DimStyle.Render(strings.Repeat("─", 40)))
+		s.WriteString("\n\n")
+	} else {
+		s.WriteString(ui.PromptBoxStyle.Render(m.promptText))
+		s.WriteString("\n\n")
+
+		s.WriteString(ui.Success("Copied to clipboard"))
+		s.WriteString("\n\n")
+
+		s.WriteString(ui.NormalStyle.Render("  Now paste this into Claude Code."))
+		s.WriteString("\n\n")
+	}
 
 	s.WriteString(ui.DimStyle.Render("  Press any key to continue..."))
 
@@ -550,6 +725,10 @@ func (m InteractiveModel) viewExplain() string {
 	s.WriteString(ui.Divider())
 	s.WriteString("\n\n")
 
+	// The offending line with a little context, read fresh in case it changed
+	s.WriteString(renderSnippet(issue))
+	s.WriteString("\n")
+
 	// Get explanation
 	explanation := prompts.GetExplanation(issue.Rule)
 
@@ -571,15 +750,83 @@ func (m InteractiveModel) viewExplain() string {
 	s.WriteString(ui.Divider())
 	s.WriteString("\n\n")
 
+	if m.fixFor == issue && m.fixing {
+		s.WriteString(ui.DimStyle.Render("  Asking AI for a fix..."))
+		s.WriteString("\n\n")
+	} else if m.fixFor == issue && m.fixPatch != "" {
+		s.WriteString(ui.TitleStyle.Render("  Suggested fix (not applied):"))
+		s.WriteString("\n\n")
+		s.WriteString(renderPatch(m.fixPatch))
+		s.WriteString("\n")
+		s.WriteString(ui.Divider())
+		s.WriteString("\n\n")
+	}
+
 	s.WriteString(ui.HighlightStyle.Render("  /prompt fix"))
 	s.WriteString(ui.DimStyle.Render("    Get a Claude prompt to fix this"))
 	s.WriteString("\n\n")
 
-	s.WriteString(ui.DimStyle.Render("  p prompt · esc back"))
+	s.WriteString(ui.DimStyle.Render("  f fix with AI · p prompt · d disable rule · esc back"))
 
 	return s.String()
 }
 
+// snippetContext is how many lines are shown either side of the issue line
+const snippetContext = 2
+
+// renderSnippet shows the issue's line with snippetContext lines around it,
+// or a note when the file or line is gone since the scan
+func renderSnippet(issue checks.Issue) string {
+	content, err := os.ReadFile(issue.File)
+	if err != nil {
+		return ui.DimStyle.Render("  (source unavailable - the file was moved or deleted since the scan)") + "\n"
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if issue.Line < 1 || issue.Line > len(lines) {
+		return ui.DimStyle.Render(fmt.Sprintf("  (line %d no longer exists - the file changed since the scan)", issue.Line)) + "\n"
+	}
+
+	start := max(issue.Line-snippetContext, 1)
+	end := min(issue.Line+snippetContext, len(lines))
+	width := len(fmt.Sprint(end))
+
+	var s strings.Builder
+	for n := start; n <= end; n++ {
+		text := strings.ReplaceAll(lines[n-1], "\t", "    ")
+		num := fmt.Sprintf("%*d", width, n)
+		if n == issue.Line {
+			s.WriteString(ui.HighlightStyle.Render(fmt.Sprintf("  > %s │ %s", num, text)))
+		} else {
+			s.WriteString(ui.LineNumStyle.Render(fmt.Sprintf("    %s │ ", num)))
+			s.WriteString(ui.NormalStyle.Render(text))
+		}
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
+// renderPatch shows a suggested diff with added lines green and removed
+// lines red
+func renderPatch(patch string) string {
+	var s strings.Builder
+	for _, line := range strings.Split(patch, "\n") {
+		text := "    " + strings.ReplaceAll(line, "\t", "    ")
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "@@"):
+			s.WriteString(ui.DimStyle.Render(text))
+		case strings.HasPrefix(line, "+"):
+			s.WriteString(ui.SuccessStyle.Render(text))
+		case strings.HasPrefix(line, "-"):
+			s.WriteString(ui.ErrorStyle.Render(text))
+		default:
+			s.WriteString(ui.NormalStyle.Render(text))
+		}
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
 func (m InteractiveModel) viewDryRun() string {
 	var s strings.Builder
 
@@ -597,15 +844,27 @@ func (m InteractiveModel) viewDryRun() string {
 	s.WriteString(ui.NormalStyle.Render("  Would check:"))
 	s.WriteString("\n\n")
 
-	for _, file := range m.dryRunInfo.Files {
-		line := fmt.Sprintf("    %s (%d lines)", file.Path, file.Lines)
-		if file.Lines > 500 {
-			s.WriteString(ui.FilePathStyle.Render(line))
-			s.WriteString(ui.WarningStyle.Render(" ⚠ large"))
-		} else {
-			s.WriteString(ui.FilePathStyle.Render(line))
+	if m.dryRunFiles {
+		for _, file := range m.dryRunInfo.Files {
+			line := fmt.Sprintf("    %s (%d lines)", file.Path, file.Lines)
+			if file.Lines > 500 {
+				s.WriteString(ui.FilePathStyle.Render(line))
+				s.WriteString(ui.WarningStyle.Render(" ⚠ large"))
+			} else {
+				s.WriteString(ui.FilePathStyle.Render(line))
+			}
+			s.WriteString("\n")
+		}
+	} else {
+		// One line per top-level directory, so big repos fit on screen
+		for _, dir := range m.dryRunInfo.Dirs {
+			name := dir.Path + "/"
+			if dir.Path == "." {
+				name = "./ (top level)"
+			}
+			s.WriteString(ui.FilePathStyle.Render(fmt.Sprintf("    %s %d files, %d lines", name, dir.Files, dir.Lines)))
+			s.WriteString("\n")
 		}
-		s.WriteString("\n")
 	}
 
 	s.WriteString("\n")
@@ -617,20 +876,51 @@ func (m InteractiveModel) viewDryRun() string {
 	s.WriteString(ui.NormalStyle.Render(fmt.Sprintf("  %d files · ~%d lines · <1 second", m.dryRunInfo.FileCount, m.dryRunInfo.TotalLines)))
 	s.WriteString("\n\n")
 
-	s.WriteString(ui.DimStyle.Render("  Press any key to continue..."))
+	toggle := "tab list files"
+	if m.dryRunFiles {
+		toggle = "tab by directory"
+	}
+	s.WriteString(ui.DimStyle.Render("  " + toggle + " · enter continue"))
 
 	return s.String()
 }
 
 // Messages
 type checksCompleteMsg struct {
+	run    int
 	issues []checks.Issue
+	failed []checks.FileError
+	err    error // Set when the run was cancelled
+}
+
+type checksProgressMsg struct {
+	run      int
+	progress checks.Progress
+	updates  <-chan tea.Msg
 }
 
-func runChecks() tea.Cmd {
+// runChecks starts checking in the background. Progress and then the final
+// checksCompleteMsg arrive on one channel, read one message per command.
+func runChecks(ctx context.Context, run int) tea.Cmd {
 	return func() tea.Msg {
-		issues := checks.RunAll(".")
-		return checksCompleteMsg{issues: issues}
+		updates := make(chan tea.Msg, 1)
+		go func() {
+			issues, err := checks.RunAllContext(ctx, ".", func(p checks.Progress) {
+				// Drop updates the view hasn't caught up with
+				select {
+				case updates <- checksProgressMsg{run: run, progress: p, updates: updates}:
+				default:
+				}
+			})
+			updates <- checksCompleteMsg{run: run, issues: issues, failed: checks.TakeFileErrors(), err: err}
+		}()
+		return waitForChecks(updates)()
+	}
+}
+
+func waitForChecks(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
 	}
 }
 
@@ -646,27 +936,116 @@ func runDryRun() tea.Cmd {
 }
 
 type promptGeneratedMsg struct {
-	prompt string
+	prompt  string
+	copyErr error  // Set when the clipboard isn't available
+	note    string // Set when this stands in for an AI fix
+}
+
+// writeClipboard copies text to the system clipboard, swapped out by tests
+var writeClipboard = clipboard.WriteAll
+
+// copiedPrompt copies a generated prompt and reports whether that worked
+func copiedPrompt(prompt string) promptGeneratedMsg {
+	return promptGeneratedMsg{prompt: prompt, copyErr: writeClipboard(prompt)}
 }
 
 func generatePrompt(selection string, issues []checks.Issue) tea.Cmd {
 	return func() tea.Msg {
 		prompt := prompts.Generate(selection, issues)
-		return promptGeneratedMsg{prompt: prompt}
+		return copiedPrompt(prompt)
 	}
 }
 
 func generateHelpPrompt(topic string) tea.Cmd {
 	return func() tea.Msg {
 		prompt := prompts.GenerateHelp(topic)
-		return promptGeneratedMsg{prompt: prompt}
+		return copiedPrompt(prompt)
 	}
 }
 
 func generatePromptForIssue(issue checks.Issue) tea.Cmd {
 	return func() tea.Msg {
 		prompt := prompts.GenerateForIssue(issue)
-		return promptGeneratedMsg{prompt: prompt}
+		return copiedPrompt(prompt)
+	}
+}
+
+type fixSuggestedMsg struct {
+	issue checks.Issue
+	patch string
+}
+
+// newProvider returns the AI provider, or false without a key; swapped out
+// by tests
+var newProvider = ai.NewProvider
+
+// fixContext is how many lines either side of the issue the AI is shown
+const fixContext = 15
+
+// suggestFix asks the AI provider for a patch fixing issue. Without a key,
+// or when the provider fails, it falls back to the prompt p would copy.
+func suggestFix(issue checks.Issue) tea.Cmd {
+	return func() tea.Msg {
+		fallback := func(note string) tea.Msg {
+			msg := copiedPrompt(prompts.GenerateForIssue(issue))
+			msg.note = note
+			return msg
+		}
+
+		provider, ok := newProvider()
+		if !ok {
+			return fallback("No AI key set (GEMINI_API_KEY) - here's a prompt instead")
+		}
+		code, err := sourceAround(issue, fixContext)
+		if err != nil {
+			return fallback(fmt.Sprintf("Couldn't read %s (%v) - here's a prompt instead", issue.File, err))
+		}
+		patch, err := ai.SuggestFix(provider, issue, code)
+		if err != nil {
+			return fallback(fmt.Sprintf("AI fix failed (%v) - here's a prompt instead", err))
+		}
+		return fixSuggestedMsg{issue: issue, patch: patch}
+	}
+}
+
+// sourceAround returns the issue's line with context lines either side,
+// numbered
+func sourceAround(issue checks.Issue, context int) (string, error) {
+	content, err := os.ReadFile(issue.File)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	start := max(issue.Line-context, 1)
+	end := min(issue.Line+context, len(lines))
+
+	var s strings.Builder
+	for n := start; n <= end; n++ {
+		fmt.Fprintf(&s, "%d │ %s\n", n, lines[n-1])
+	}
+	return s.String(), nil
+}
+
+type ruleDisabledMsg struct {
+	rule string
+	path string
+	err  error
+}
+
+// disableRule turns rule off in the project config and saves it
+func disableRule(rule string) tea.Cmd {
+	return func() tea.Msg {
+		cfg, err := config.LoadFile(".")
+		if err != nil {
+			return ruleDisabledMsg{rule: rule, err: fmt.Errorf("couldn't load config: %w", err)}
+		}
+
+		cfg.DisableRule(rule)
+		if err := config.Save(".", cfg); err != nil {
+			return ruleDisabledMsg{rule: rule, err: fmt.Errorf("couldn't save config: %w", err)}
+		}
+
+		return ruleDisabledMsg{rule: rule, path: filepath.Base(config.GetConfigPath("."))}
 	}
 }
 
@@ -676,10 +1055,10 @@ type configOpenedMsg struct {
 
 func openConfig() tea.Cmd {
 	return func() tea.Msg {
-		configPath := "guardian_config.toml"
+		configPath := config.GetConfigPath(".")
 
 		// Check if config exists
-		if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		if !config.Exists(".") {
 			return configOpenedMsg{err: fmt.Errorf("no guardian_config.toml found - run 'guardian add <language>' first")}
 		}
 
@@ -0,0 +1,427 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/guardian-sh/guardian/internal/ai"
+	"github.com/guardian-sh/guardian/internal/checks"
+	"github.com/guardian-sh/guardian/internal/config"
+)
+
+// Helper to run test in temp directory (screens commands operate on ".")
+func withTempDir(t *testing.T, fn func(dir string)) {
+	t.Helper()
+	dir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(oldDir)
+	fn(dir)
+}
+
+// Helper to send a key and run the resulting command, feeding its message back
+func pressKey(t *testing.T, m InteractiveModel, k string) (InteractiveModel, tea.Msg) {
+	t.Helper()
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	m = next.(InteractiveModel)
+	if cmd == nil {
+		return m, nil
+	}
+	return m, cmd()
+}
+
+// Helper to feed a run's progress messages back until it completes
+func finishChecks(t *testing.T, m InteractiveModel, cmd tea.Cmd) InteractiveModel {
+	t.Helper()
+	for cmd != nil {
+		msg := cmd()
+		var next tea.Model
+		next, cmd = m.Update(msg)
+		m = next.(InteractiveModel)
+		if _, done := msg.(checksCompleteMsg); done {
+			break
+		}
+	}
+	return m
+}
+
+// ============================================================================
+// RULE TOGGLING
+// ============================================================================
+
+func TestResults_DisableRulePersistsAndReruns(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(\"debug\")\nresult = eval(x)\n"), 0644)
+
+		m := NewInteractive(nil)
+		next, _ := m.Update(checksCompleteMsg{issues: checks.RunAll(".")})
+		m = next.(InteractiveModel)
+
+		// Select the ban-print issue
+		for m.cursor = 0; m.cursor < len(m.issues); m.cursor++ {
+			if m.issues[m.cursor].Rule == "ban-print" {
+				break
+			}
+		}
+		if m.cursor == len(m.issues) {
+			t.Fatalf("expected a ban-print issue, got %+v", m.issues)
+		}
+
+		m, msg := pressKey(t, m, "d")
+		if _, ok := msg.(ruleDisabledMsg); !ok {
+			t.Fatalf("expected ruleDisabledMsg, got %T", msg)
+		}
+
+		cfg, err := config.Load(dir)
+		if err != nil {
+			t.Fatalf("failed to load saved config: %v", err)
+		}
+		if cfg.Quality.BanPrint {
+			t.Error("ban_print should be false in the saved config")
+		}
+
+		// Handling the message re-runs checks
+		next, cmd := m.Update(msg)
+		m = next.(InteractiveModel)
+		if m.notice == "" {
+			t.Error("expected a confirmation notice")
+		}
+		if cmd == nil {
+			t.Fatal("expected checks to re-run after disabling a rule")
+		}
+		m = finishChecks(t, m, cmd)
+
+		for _, issue := range m.issues {
+			if issue.Rule == "ban-print" {
+				t.Error("ban-print should not be reported after disabling it")
+			}
+		}
+		if len(m.issues) == 0 {
+			t.Error("other rules should still be reported")
+		}
+	})
+}
+
+func TestResults_DisableRuleWithoutToggleUsesDisabledList(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		m := NewInteractive(nil)
+		m.issues = []checks.Issue{{File: "app.py", Line: 1, Rule: "sql-injection", Severity: "critical"}}
+		m.mode = ModeResults
+
+		_, msg := pressKey(t, m, "d")
+		if msg == nil {
+			t.Fatal("expected disable command")
+		}
+
+		cfg, _ := config.Load(dir)
+		if !cfg.IsRuleDisabled("sql-injection") {
+			t.Errorf("expected sql-injection in rules.disabled, got %v", cfg.Rules.Disabled)
+		}
+	})
+}
+
+// ============================================================================
+// EXPLAIN SNIPPET
+// ============================================================================
+
+func TestExplain_ShowsSourceSnippet(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		src := "import os\n\ndef load(data):\n    return eval(data)\n\nx = 1\ny = 2\n"
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte(src), 0644)
+
+		m := NewInteractive(nil)
+		m.issues = []checks.Issue{{File: "app.py", Line: 4, Rule: "ban-eval", Severity: "critical"}}
+		m.mode = ModeResults
+		m, _ = pressKey(t, m, "e")
+
+		view := m.View()
+		for _, want := range []string{"return eval(data)", "def load(data):", "x = 1"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("expected %q in the explain view:\n%s", want, view)
+			}
+		}
+		if strings.Contains(view, "import os") || strings.Contains(view, "y = 2") {
+			t.Errorf("expected only 2 lines of context either side:\n%s", view)
+		}
+		if !strings.Contains(view, "> 4 │") {
+			t.Errorf("expected the issue line to be marked:\n%s", view)
+		}
+	})
+}
+
+func TestExplain_SourceChangedSinceScan(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "short.py"), []byte("x = 1\n"), 0644)
+
+		m := NewInteractive(nil)
+		m.mode = ModeExplain
+		m.issues = []checks.Issue{
+			{File: "short.py", Line: 40, Rule: "ban-eval"},
+			{File: "deleted.py", Line: 1, Rule: "ban-eval"},
+		}
+
+		if view := m.View(); !strings.Contains(view, "line 40 no longer exists") {
+			t.Errorf("expected a note for a line past EOF:\n%s", view)
+		}
+		m.explainIdx = 1
+		if view := m.View(); !strings.Contains(view, "source unavailable") || !strings.Contains(view, "What's wrong") {
+			t.Errorf("expected a note for a deleted file and the explanation still shown:\n%s", view)
+		}
+	})
+}
+
+// ============================================================================
+// FIX WITH AI
+// ============================================================================
+
+// fakeProvider replies with reply, or fails with err
+type fakeProvider struct {
+	reply  string
+	err    error
+	prompt string
+}
+
+func (f *fakeProvider) Complete(prompt string) (string, error) {
+	f.prompt = prompt
+	return f.reply, f.err
+}
+
+// Helper to replace the AI provider for the duration of a test; nil means
+// no key is configured
+func stubProvider(t *testing.T, p ai.Provider) {
+	t.Helper()
+	old := newProvider
+	newProvider = func() (ai.Provider, bool) { return p, p != nil }
+	t.Cleanup(func() { newProvider = old })
+}
+
+// Helper to open the explain screen on an eval in app.py
+func explainEval(t *testing.T, dir string) InteractiveModel {
+	t.Helper()
+	os.WriteFile(filepath.Join(dir, "app.py"), []byte("def load(data):\n    return eval(data)\n"), 0644)
+	m := NewInteractive(nil)
+	m.issues = []checks.Issue{{File: "app.py", Line: 2, Rule: "ban-eval", Message: "eval() is dangerous", Severity: "critical"}}
+	m.mode = ModeExplain
+	return m
+}
+
+func TestExplain_FixWithAIShowsPatch(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		patch := "--- a/app.py\n+++ b/app.py\n@@ -2 +2 @@\n-    return eval(data)\n+    return json.loads(data)"
+		provider := &fakeProvider{reply: "```diff\n" + patch + "\n```"}
+		stubProvider(t, provider)
+
+		m, msg := pressKey(t, explainEval(t, dir), "f")
+		if !strings.Contains(m.View(), "Asking AI") {
+			t.Errorf("expected a waiting note while the provider runs:\n%s", m.View())
+		}
+		if !strings.Contains(provider.prompt, "return eval(data)") {
+			t.Errorf("expected the surrounding code in the prompt:\n%s", provider.prompt)
+		}
+
+		next, _ := m.Update(msg)
+		m = next.(InteractiveModel)
+		if m.mode != ModeExplain {
+			t.Fatalf("expected to stay on the explain screen, got mode %v", m.mode)
+		}
+		view := m.View()
+		for _, want := range []string{"Suggested fix (not applied)", "+    return json.loads(data)", "-    return eval(data)"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("expected %q in the explain view:\n%s", want, view)
+			}
+		}
+
+		content, _ := os.ReadFile(filepath.Join(dir, "app.py"))
+		if strings.Contains(string(content), "json.loads") {
+			t.Error("the suggested patch should not be applied")
+		}
+	})
+}
+
+func TestExplain_FixWithAIFallsBackToPrompt(t *testing.T) {
+	old := writeClipboard
+	defer func() { writeClipboard = old }()
+	writeClipboard = func(string) error { return nil }
+
+	for name, provider := range map[string]ai.Provider{
+		"no key":         nil,
+		"provider error": &fakeProvider{err: errors.New("quota exceeded")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			withTempDir(t, func(dir string) {
+				stubProvider(t, provider)
+
+				m, msg := pressKey(t, explainEval(t, dir), "f")
+				if _, ok := msg.(promptGeneratedMsg); !ok {
+					t.Fatalf("expected the prompt fallback, got %T", msg)
+				}
+				next, _ := m.Update(msg)
+				m = next.(InteractiveModel)
+				if m.mode != ModePromptResult {
+					t.Errorf("expected the prompt screen, got mode %v", m.mode)
+				}
+				view := m.View()
+				if !strings.Contains(view, "here's a prompt instead") || !strings.Contains(view, "ban-eval") {
+					t.Errorf("expected the fallback note and prompt:\n%s", view)
+				}
+			})
+		})
+	}
+}
+
+// ============================================================================
+// RUNNING CHECKS
+// ============================================================================
+
+func TestRun_ShowsProgressThenResults(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "a.py"), []byte("print(1)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "b.py"), []byte("x = eval(y)\n"), 0644)
+
+		m := NewInteractive(nil)
+		next, cmd := m.handleCommand("/run")
+		m = next.(InteractiveModel)
+		if m.mode != ModeRunning {
+			t.Fatalf("expected ModeRunning while checking, got %v", m.mode)
+		}
+		if !strings.Contains(m.View(), "esc cancel") {
+			t.Errorf("expected a cancel hint while running:\n%s", m.View())
+		}
+
+		m = finishChecks(t, m, cmd)
+		if m.mode != ModeResults {
+			t.Fatalf("expected ModeResults after the run, got %v", m.mode)
+		}
+		if len(m.issues) != 2 {
+			t.Errorf("expected 2 issues, got %+v", m.issues)
+		}
+	})
+}
+
+func TestRun_ProgressUpdatesView(t *testing.T) {
+	m := NewInteractive(nil)
+	m.mode = ModeRunning
+	m.runID = 1
+
+	updates := make(chan tea.Msg)
+	next, cmd := m.Update(checksProgressMsg{run: 1, progress: checks.Progress{Checked: 3, File: "src/app.py"}, updates: updates})
+	m = next.(InteractiveModel)
+	if cmd == nil {
+		t.Error("expected to keep waiting for the run")
+	}
+	view := m.View()
+	if !strings.Contains(view, "3 files checked") || !strings.Contains(view, "src/app.py") {
+		t.Errorf("expected progress in the view:\n%s", view)
+	}
+}
+
+func TestRun_EscCancelsAndRestoresCommandMode(t *testing.T) {
+	m := NewInteractive(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.mode = ModeRunning
+	m.runID = 1
+	m.cancelRun = cancel
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(InteractiveModel)
+	if m.mode != ModeCommand {
+		t.Errorf("expected ModeCommand after cancelling, got %v", m.mode)
+	}
+	if ctx.Err() == nil {
+		t.Error("expected the run's context to be cancelled")
+	}
+
+	// The cancelled run finishing late must not replace the command screen
+	next, _ = m.Update(checksCompleteMsg{run: 1, err: context.Canceled})
+	m = next.(InteractiveModel)
+	if m.mode != ModeCommand {
+		t.Errorf("expected a cancelled run's result to be ignored, got mode %v", m.mode)
+	}
+}
+
+func TestRun_CancelStopsTheScan(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		for i := 0; i < 50; i++ {
+			os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%02d.py", i)), []byte("print(1)\n"), 0644)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		m := NewInteractive(nil)
+		m.runID = 1
+		msg := runChecks(ctx, 1)()
+		for {
+			if p, ok := msg.(checksProgressMsg); ok {
+				msg = waitForChecks(p.updates)()
+				continue
+			}
+			break
+		}
+		done, ok := msg.(checksCompleteMsg)
+		if !ok || done.err == nil {
+			t.Fatalf("expected a cancelled completion, got %#v", msg)
+		}
+		next, _ := m.Update(done)
+		if next.(InteractiveModel).mode == ModeResults {
+			t.Error("a cancelled run should not show results")
+		}
+	})
+}
+
+func TestDryRun_RollupThenFileList(t *testing.T) {
+	m := NewInteractive(nil)
+	next, _ := m.Update(dryRunCompleteMsg{info: &checks.DryRunInfo{
+		Files:     []checks.FileInfo{{Path: "main.py", Lines: 3}, {Path: "src/a.py", Lines: 10}, {Path: "src/b.py", Lines: 20}},
+		Dirs:      []checks.DirInfo{{Path: ".", Files: 1, Lines: 3}, {Path: "src", Files: 2, Lines: 30}},
+		FileCount: 3, TotalLines: 33,
+	}})
+	m = next.(InteractiveModel)
+
+	view := m.View()
+	if !strings.Contains(view, "src/ 2 files, 30 lines") || strings.Contains(view, "src/a.py") {
+		t.Errorf("expected per-directory totals by default:\n%s", view)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = next.(InteractiveModel)
+	if view := m.View(); !strings.Contains(view, "src/a.py (10 lines)") {
+		t.Errorf("tab should list every file:\n%s", view)
+	}
+	if m.mode != ModeDryRun {
+		t.Errorf("tab should stay on the dry run, got mode %v", m.mode)
+	}
+}
+
+func TestPromptResult_ClipboardUnavailable(t *testing.T) {
+	old := writeClipboard
+	defer func() { writeClipboard = old }()
+
+	writeClipboard = func(string) error { return nil }
+	m := NewInteractive(nil)
+	next, _ := m.Update(generateHelpPrompt("testing")())
+	copied := next.(InteractiveModel).View()
+	if !strings.Contains(copied, "Copied to clipboard") {
+		t.Errorf("expected the copy to be confirmed:\n%s", copied)
+	}
+
+	writeClipboard = func(string) error { return errors.New("no xclip or xsel") }
+	next, _ = m.Update(generateHelpPrompt("testing")())
+	m = next.(InteractiveModel)
+	view := m.View()
+	if strings.Contains(view, "Copied to clipboard") {
+		t.Errorf("should not claim the prompt was copied:\n%s", view)
+	}
+	if !strings.Contains(view, "no xclip or xsel") || !strings.Contains(view, "copy it yourself") {
+		t.Errorf("expected manual copy instructions:\n%s", view)
+	}
+	if !strings.Contains(view, m.promptText) {
+		t.Errorf("expected the prompt as plain selectable text:\n%s", view)
+	}
+}
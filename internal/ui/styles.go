@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"os"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 // Forest green color palette
@@ -130,6 +133,36 @@ var (
 			Foreground(Cyan)
 )
 
+// ConfigureColor turns off ANSI styling when noColor is set, NO_COLOR is
+// present (https://no-color.org), or stdout isn't a terminal. CLICOLOR_FORCE
+// (other than "0") keeps color when piped, but loses to the other two.
+func ConfigureColor(noColor bool) {
+	switch {
+	case noColor || os.Getenv("NO_COLOR") != "":
+		lipgloss.SetColorProfile(termenv.Ascii)
+	case colorForced():
+		if lipgloss.ColorProfile() == termenv.Ascii {
+			lipgloss.SetColorProfile(termenv.ANSI)
+		}
+	case !IsTerminal(os.Stdout):
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+func colorForced() bool {
+	force := os.Getenv("CLICOLOR_FORCE")
+	return force != "" && force != "0"
+}
+
+// IsTerminal reports whether f is attached to a character device (a TTY)
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
 // Helper functions
 func Success(s string) string {
 	return SuccessStyle.Render("✓ " + s)
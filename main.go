@@ -1,13 +1,25 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guardian-sh/guardian/internal/ai"
 	"github.com/guardian-sh/guardian/internal/checks"
+	"github.com/guardian-sh/guardian/internal/config"
+	"github.com/guardian-sh/guardian/internal/prompts"
+	"github.com/guardian-sh/guardian/internal/report"
 	"github.com/guardian-sh/guardian/internal/scaffolding"
 	"github.com/guardian-sh/guardian/internal/screens"
 	"github.com/guardian-sh/guardian/internal/ui"
@@ -16,6 +28,8 @@ import (
 const version = "0.1.0"
 
 func main() {
+	scaffolding.Version = version
+
 	if len(os.Args) < 2 {
 		// No arguments - launch interactive mode
 		runInteractive()
@@ -24,13 +38,32 @@ func main() {
 
 	cmd := strings.ToLower(os.Args[1])
 
+	// Plain output when piped (unless CLICOLOR_FORCE) or NO_COLOR is set;
+	// --no-color is handled per command
+	ui.ConfigureColor(false)
+
 	switch cmd {
 	case "check", "run":
-		runCheck()
+		runCheck(os.Args[2:])
+	case "scan":
+		runScan(os.Args[2:])
+	case "stats":
+		runStats(os.Args[2:])
+	case "plan":
+		runPlan(os.Args[2:])
+	case "score":
+		runScore(os.Args[2:])
+	case "watch":
+		runWatch(os.Args[2:])
+	case "explain":
+		runExplain(os.Args[2:])
+	case "bench":
+		// Not in the help: for tracking performance as rules are added
+		runBench(os.Args[2:])
 	case "add":
 		runAdd()
 	case "config":
-		runConfig()
+		runConfig(os.Args[2:])
 	case "version", "--version", "-v":
 		fmt.Printf("guardian %s\n", version)
 	case "help", "--help", "-h":
@@ -54,49 +87,468 @@ func runInteractive() {
 	}
 }
 
-func runCheck() {
-	fmt.Println(ui.SmallLogo())
-	fmt.Println()
+func runCheck(args []string) {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	absolute := fs.Bool("absolute", false, "Show absolute file paths")
+	relativeTo := fs.String("relative-to", "", "Show file paths relative to this directory (default the scan root)")
+	minConfidence := fs.String("min-confidence", "low", "Only report issues at or above this confidence (low, medium, high)")
+	record := fs.Bool("record", false, "Append a summary of this run to "+checks.HistoryFile)
+	summaryLine := fs.Bool("summary-line", false, "Always print a GUARDIAN_SUMMARY line (default only when piped)")
+	explain := fs.Bool("explain", false, "Print what's wrong, why, and how to fix it for each rule found")
+	includeExt := fs.String("include-ext", "", "Check extra extensions with a language's rules (e.g. .mjs=js,.pyi=python)")
+	groupBy := fs.String("group-by", "file", "Group the report by file, rule or severity")
+	rulesFile := fs.String("rules-file", "", "Load extra [[custom_rules]] from this file (merged with the config's)")
+	sinceLastRun := fs.Bool("since-last-run", false, "Only report issues that are new since the previous check")
+	showFixed := fs.Bool("show-fixed", false, "With --since-last-run, also list issues fixed since the previous check")
+	format := fs.String("format", "text", "Output format: text, guardian for plain \"file:line [rule] message\" lines, github for Actions annotations (default when GITHUB_ACTIONS=true), or junit for JUnit XML")
+	verbose := fs.Bool("verbose", false, "Include stack traces for files whose checks failed")
+	baseline := fs.Bool("baseline", false, "Only report issues that aren't in "+checks.BaselineFile)
+	baselineUpdate := fs.Bool("baseline-update", false, "Accept every current issue by rewriting "+checks.BaselineFile)
+	baselineFormat := fs.String("baseline-format", checks.BaselineFuzzy, "With --baseline-update, how issues are matched later: strict (file, line and rule) or fuzzy (file, rule and line text)")
+	maxIssues := fs.Int("max-issues", 0, "Show at most this many issues in the report (0 for all); counts and exit code still cover every issue")
+	porcelain := fs.Bool("porcelain", false, "Print one tab-separated \"severity rule file line message\" record per issue")
+	engineName := fs.String("engine", "auto", "Checks to run: auto (scripts if installed, else builtin), script (fail if the scripts can't run), builtin, or both merged")
+	noBuiltin := fs.Bool("no-builtin", false, "Same as --engine script: never fall back to the builtin checks")
+	group := fs.String("group", "", "Only report rules in this group: quality or security")
+	filesFrom := fs.String("files-from", "", "Check only the newline-separated paths in this file (- reads stdin)")
+	exitZero := fs.Bool("exit-zero", false, "Report issues as usual but exit 0 even when critical issues are found")
+	followSymlinks := fs.Bool("follow-symlinks", false, "Check the files and directories symlinks point to (links back into the project are skipped)")
+	changedOnly := fs.Bool("changed-only", false, "Without file arguments, check the files staged in git instead of the whole project")
+	var count countFlag
+	fs.Var(&count, "count", "Print only the number of issues, or with --count critical|warning|info those of one severity")
+	printConfig := fs.Bool("print-config", false, "Print the effective config (defaults, file, GUARDIAN_* env and flags) as TOML, or JSON with --format json, and exit without checking")
+	hookMode := fs.Bool("hook-mode", false, "Pre-commit preset: check the staged files (or those passed), list warnings and info as non-blocking and fail only on critical issues")
+	fs.Parse(joinCountArg(args))
+
+	if *noColor {
+		ui.ConfigureColor(true)
+	}
+
+	switch *minConfidence {
+	case "low", "medium", "high":
+	default:
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --min-confidence: %s (use low, medium or high)", *minConfidence)))
+		os.Exit(2)
+	}
+
+	if *group != "" && !slices.Contains(checks.RuleGroups, *group) {
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --group: %s (use quality or security)", *group)))
+		os.Exit(2)
+	}
+
+	switch *groupBy {
+	case "file", "rule", "severity":
+	default:
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --group-by: %s (use file, rule or severity)", *groupBy)))
+		os.Exit(2)
+	}
+
+	if *absolute && *relativeTo != "" {
+		fmt.Println(ui.Error("Use --absolute or --relative-to, not both"))
+		os.Exit(2)
+	}
+	var pathBase string
+	if *relativeTo != "" {
+		base, err := absDir(*relativeTo)
+		if err != nil {
+			fmt.Println(ui.Error(fmt.Sprintf("Invalid --relative-to: %v", err)))
+			os.Exit(2)
+		}
+		pathBase = base
+	}
+
+	if *maxIssues < 0 {
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --max-issues: %d (use 0 for no limit)", *maxIssues)))
+		os.Exit(2)
+	}
+
+	switch *format {
+	case "text", "guardian", "github", "junit":
+	case "toml", "json":
+		if !*printConfig {
+			fmt.Println(ui.Error(fmt.Sprintf("--format %s is only for --print-config", *format)))
+			os.Exit(2)
+		}
+	default:
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --format: %s (use text, guardian, github or junit)", *format)))
+		os.Exit(2)
+	}
+	engines := map[string]checks.Engine{"auto": checks.EngineAuto, "script": checks.EngineScript, "builtin": checks.EngineBuiltin, "both": checks.EngineBoth}
+	engine, ok := engines[*engineName]
+	if !ok {
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --engine: %s (use auto, script, builtin or both)", *engineName)))
+		os.Exit(2)
+	}
+	if *noBuiltin {
+		if engine == checks.EngineBuiltin || engine == checks.EngineBoth {
+			fmt.Println(ui.Error("--no-builtin conflicts with --engine " + *engineName))
+			os.Exit(2)
+		}
+		engine = checks.EngineScript
+	}
+
+	if count.set && (*porcelain || flagPassed(fs, "format")) {
+		fmt.Println(ui.Error("--count prints only a number; don't combine it with --format or --porcelain"))
+		os.Exit(2)
+	}
+
+	if count.set {
+		*format = "count"
+	} else if *porcelain {
+		*format = "porcelain"
+	} else if !flagPassed(fs, "format") && os.Getenv("GITHUB_ACTIONS") == "true" {
+		// Annotate the pull request diff instead of printing a report
+		*format = "github"
+	}
+
+	cfg, err := config.Load(".")
+	if err != nil && *printConfig {
+		// Defaults would hide exactly what --print-config is for
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid config: %v", err)))
+		os.Exit(2)
+	}
+	if err != nil {
+		// Malformed config or GUARDIAN_* value - run with defaults rather than
+		// skipping checks. Stderr keeps --count and the machine formats clean.
+		fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Using default config: %v", err)))
+		cfg = config.DefaultConfig()
+	}
+
+	var sharedRules []config.CustomRule
+	if *rulesFile != "" {
+		sharedRules, err = config.LoadRulesFile(*rulesFile)
+		if err != nil {
+			fmt.Println(ui.Error(fmt.Sprintf("Invalid --rules-file: %v", err)))
+			os.Exit(2)
+		}
+		cfg.MergeCustomRules(sharedRules)
+	}
+
+	var extMapping map[string]string
+	if *includeExt != "" {
+		extMapping, err = checks.ParseIncludeExt(*includeExt)
+		if err != nil {
+			fmt.Println(ui.Error(fmt.Sprintf("Invalid --include-ext: %v", err)))
+			os.Exit(2)
+		}
+		addIncludeExt(cfg, extMapping)
+	}
+	if *followSymlinks {
+		cfg.Project.FollowSymlinks = true
+	}
+
+	if *printConfig {
+		sources := config.Sources(".", cfg)
+		if *rulesFile != "" {
+			sources = append(sources, "--rules-file "+*rulesFile)
+		}
+		if *includeExt != "" {
+			sources = append(sources, "--include-ext "+*includeExt)
+		}
+		if *followSymlinks {
+			sources = append(sources, "--follow-symlinks")
+		}
+		printEffectiveConfig(cfg, sources, *format)
+		return
+	}
+
+	// Positional args are files to check, as passed by a pre-commit hook,
+	// or project roots to check with their own configs (monorepos)
+	files, roots := splitCheckArgs(fs.Args())
+	if len(files) > 0 && len(roots) > 0 {
+		fmt.Println(ui.Error("Pass either files or directories to check, not both"))
+		os.Exit(2)
+	}
+	if *filesFrom != "" {
+		if fs.NArg() > 0 {
+			fmt.Println(ui.Error("Pass paths either as arguments or with --files-from, not both"))
+			os.Exit(2)
+		}
+		listed, err := readFileList(*filesFrom)
+		if err != nil {
+			fmt.Println(ui.Error(fmt.Sprintf("Could not read --files-from: %v", err)))
+			os.Exit(2)
+		}
+		files = existingFiles(listed)
+		if len(files) == 0 {
+			noFilesToCheck(count.set)
+			return
+		}
+	}
+	if (*hookMode || *changedOnly) && *filesFrom == "" && fs.NArg() == 0 {
+		staged, err := stagedFiles()
+		if err != nil {
+			fmt.Println(ui.Error(fmt.Sprintf("Could not list staged files: %v", err)))
+			os.Exit(2)
+		}
+		files = staged
+		if len(files) == 0 {
+			noFilesToCheck(count.set)
+			return
+		}
+	}
+	if *hookMode && *exitZero {
+		fmt.Println(ui.Error("--exit-zero can't be combined with --hook-mode, which exists to block commits"))
+		os.Exit(2)
+	}
+	if *hookMode && len(roots) > 0 {
+		fmt.Println(ui.Error("--hook-mode checks files; don't pass directories"))
+		os.Exit(2)
+	}
+	if *changedOnly && len(roots) > 0 {
+		fmt.Println(ui.Error("--changed-only checks files; don't pass directories"))
+		os.Exit(2)
+	}
+	if len(files) == 0 && len(roots) == 0 && os.Getenv("PRE_COMMIT") != "" {
+		// pre-commit had no matching staged files to pass us
+		noFilesToCheck(count.set)
+		return
+	}
+	fileMode := len(files) > 0
+	if (engine == checks.EngineScript || engine == checks.EngineBoth) && (fileMode || len(roots) > 0) {
+		// The scripts always check the whole project
+		fmt.Println(ui.Error("--engine " + *engineName + " checks the whole project; don't pass paths"))
+		os.Exit(2)
+	}
+	if engine == checks.EngineBuiltin && len(roots) > 0 {
+		fmt.Println(ui.Error("--engine builtin can't be combined with directories; run it from each root"))
+		os.Exit(2)
+	}
+	if *baseline && *baselineUpdate {
+		fmt.Println(ui.Error("Use --baseline to filter or --baseline-update to rewrite the baseline, not both"))
+		os.Exit(2)
+	}
+	if !slices.Contains(checks.BaselineFormats, *baselineFormat) {
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --baseline-format: %s (use strict or fuzzy)", *baselineFormat)))
+		os.Exit(2)
+	}
+	if *baselineUpdate && (fileMode || len(roots) > 0) {
+		// A partial run would drop every other file's accepted issues
+		fmt.Println(ui.Error("--baseline-update checks the whole project; don't pass paths"))
+		os.Exit(2)
+	}
+	var accepted *checks.Baseline
+	if *baseline {
+		var found bool
+		accepted, found, err = checks.LoadBaseline(".")
+		if err != nil {
+			fmt.Println(ui.Error(fmt.Sprintf("Could not read %s: %v", checks.BaselineFile, err)))
+			os.Exit(2)
+		}
+		if !found {
+			fmt.Println(ui.Error(fmt.Sprintf("No %s yet - run 'guardian check --baseline-update' to create it", checks.BaselineFile)))
+			os.Exit(2)
+		}
+	}
+	warnStaleScaffolding(".")
+	warnSnoozes(cfg)
 
-	issues := checks.RunAll(".")
+	var issues []checks.Issue
+	fileCount := func() int { return checks.DryRunWithConfig(".", cfg).FileCount }
+	if fileMode {
+		issues = checks.RunFilesWithConfig(".", files, cfg)
+		fileCount = func() int { return countCheckedFiles(files, cfg) }
+	} else {
+		if *format == "text" {
+			fmt.Println(ui.SmallLogo())
+			fmt.Println()
+		}
+		if engine != checks.EngineAuto {
+			issues, err = checks.RunWithEngine(".", cfg, engine)
+			if err != nil {
+				fmt.Println(ui.Error(fmt.Sprintf("Checks could not run: %v", err)))
+				os.Exit(2)
+			}
+		} else if len(roots) == 0 {
+			issues = checks.RunWithConfig(".", cfg)
+		} else {
+			// Each root is judged by its nearest config, not the one in "."
+			total := 0
+			for _, root := range roots {
+				rootCfg, err := config.LoadNearest(root)
+				if err != nil {
+					fmt.Println(ui.Warning(fmt.Sprintf("%s: using default config: %v", root, err)))
+					rootCfg = config.DefaultConfig()
+				}
+				rootCfg.MergeCustomRules(sharedRules)
+				addIncludeExt(rootCfg, extMapping)
+				if *followSymlinks {
+					rootCfg.Project.FollowSymlinks = true
+				}
+				issues = append(issues, checks.RunRoot(root, rootCfg)...)
+				total += checks.DryRunWithConfig(root, rootCfg).FileCount
+			}
+			fileCount = func() int { return total }
+		}
+	}
+	printFileErrors(checks.TakeFileErrors(), *verbose)
+	allIssues := issues
+	// What the report shows; everything found is still saved for later runs
+	reported := func(list []checks.Issue) []checks.Issue {
+		list = checks.FilterByConfidence(list, *minConfidence)
+		if *group != "" {
+			list = checks.FilterGroup(list, *group)
+		}
+		return list
+	}
+	issues = reported(issues)
+
+	if *baselineUpdate {
+		if err := checks.SaveBaseline(".", allIssues, *baselineFormat); err != nil {
+			fmt.Println(ui.Error(fmt.Sprintf("Could not write %s: %v", checks.BaselineFile, err)))
+			os.Exit(1)
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Baseline updated: %d issues accepted in %s (%s)", len(allIssues), checks.BaselineFile, *baselineFormat)))
+		return
+	}
+
+	if *record {
+		if err := checks.AppendHistory(".", checks.Summarize(allIssues, time.Now())); err != nil {
+			fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Could not record history: %v", err)))
+		}
+	}
+
+	// Compare with, then remember, the previous --since-last-run. Like
+	// --record it's opt-in, so a plain check writes nothing. File mode only
+	// sees some files, so saving it would make everything else look fixed.
+	var fixed []checks.Issue
+	if !fileMode && *sinceLastRun {
+		prev, found, err := checks.LoadLastRun(".")
+		switch {
+		case err != nil:
+			fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Could not read last run, showing all issues: %v", err)))
+		case !found:
+			fmt.Fprintln(os.Stderr, ui.Info("No previous run recorded - showing all issues"))
+		default:
+			added, gone := checks.DiffIssues(prev, allIssues)
+			issues = reported(added)
+			fixed = reported(gone)
+		}
+		if err := checks.SaveLastRun(".", allIssues); err != nil {
+			fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Could not save last run: %v", err)))
+		}
+	}
+
+	if *baseline {
+		issues = checks.FilterBaseline(".", issues, accepted)
+	}
+
+	if *absolute {
+		for _, list := range [][]checks.Issue{issues, fixed} {
+			for i := range list {
+				if abs, err := filepath.Abs(list[i].File); err == nil {
+					list[i].File = abs
+				}
+			}
+		}
+	}
+	if pathBase != "" {
+		for _, list := range [][]checks.Issue{issues, fixed} {
+			for i := range list {
+				list[i].File = relativePath(list[i].File, pathBase)
+			}
+		}
+	}
+	if !*showFixed {
+		fixed = nil
+	}
+
+	// Stable trailer for CI log parsing; always on when output isn't a terminal
+	emitSummary := *summaryLine || !ui.IsTerminal(os.Stdout)
+
+	if *format == "count" {
+		exitCheck(runCheckCount(issues, count.severity), *exitZero)
+		return
+	}
+	if *format == "guardian" {
+		exitCheck(runCheckLines(issues, checks.FormatIssueLine, emitSummary, fileCount), *exitZero)
+		return
+	}
+	if *format == "github" {
+		exitCheck(runCheckLines(issues, checks.FormatGitHubAnnotation, emitSummary, fileCount), *exitZero)
+		return
+	}
+	if *format == "porcelain" {
+		exitCheck(runCheckPorcelain(issues), *exitZero)
+		return
+	}
+	if *format == "junit" {
+		exitCheck(runCheckJUnit(issues), *exitZero)
+		return
+	}
+
+	if *hookMode {
+		exitCheck(runCheckHook(issues, files, cfg, emitSummary, *explain), false)
+		return
+	}
+	if fileMode {
+		exitCheck(runCheckFiles(issues, files, cfg, emitSummary, *explain, *maxIssues), *exitZero)
+		return
+	}
 
 	if len(issues) == 0 {
-		fmt.Println(ui.Success("No issues found"))
+		if *sinceLastRun {
+			fmt.Println(ui.Success("No new issues since last run"))
+		} else {
+			fmt.Println(ui.Success("No issues found"))
+		}
+		printFixed(fixed)
+		if emitSummary {
+			printSummaryLine(0, 0, 0, fileCount())
+		}
 		return
 	}
 
-	// Group by file
-	fileIssues := make(map[string][]checks.Issue)
-	for _, issue := range issues {
-		fileIssues[issue.File] = append(fileIssues[issue.File], issue)
-	}
-
-	// Print issues
-	critical, warnings, info := 0, 0, 0
-	for file, issues := range fileIssues {
-		fmt.Printf("\n%s\n", ui.FilePathStyle.Render(file))
-
-		for _, issue := range issues {
-			severity := ""
-			switch issue.Severity {
-			case "critical":
-				severity = ui.CriticalStyle.Render(fmt.Sprintf("[%s]", issue.Rule))
-				critical++
-			case "warning":
-				severity = ui.WarningIssueStyle.Render(fmt.Sprintf("[%s]", issue.Rule))
-				warnings++
-			default:
-				severity = ui.InfoIssueStyle.Render(fmt.Sprintf("[%s]", issue.Rule))
-				info++
+	// Print issues, grouped by file (default), rule or severity, stopping
+	// after --max-issues. The summary and exit code still count them all.
+	critical, warnings, info := countSeverities(issues)
+	shown := 0
+	for _, group := range groupIssues(issues, *groupBy) {
+		if *maxIssues > 0 && shown >= *maxIssues {
+			break
+		}
+		switch *groupBy {
+		case "rule":
+			fmt.Printf("\n%s %s\n", severityStyle(group.issues[0].Severity).Render(fmt.Sprintf("[%s]", group.key)),
+				ui.DimStyle.Render(fmt.Sprintf("(%d)", len(group.issues))))
+		case "severity":
+			fmt.Printf("\n%s\n", severityStyle(group.key).Render(fmt.Sprintf("%s (%d)", group.key, len(group.issues))))
+		default:
+			fmt.Printf("\n%s\n", ui.FilePathStyle.Render(group.key))
+		}
+
+		for _, issue := range group.issues {
+			if *maxIssues > 0 && shown >= *maxIssues {
+				break
 			}
+			shown++
+			rule := severityStyle(issue.Severity).Render(fmt.Sprintf("[%s]", issue.Rule))
+			location := ui.FilePathStyle.Render(fmt.Sprintf("%s:%d", issue.File, issue.Line))
 
-			fmt.Printf("  %s  %s  %s\n",
-				ui.LineNumStyle.Render(fmt.Sprintf(":%d", issue.Line)),
-				severity,
-				issue.Message,
-			)
+			switch *groupBy {
+			case "rule":
+				fmt.Printf("  %s  %s\n", location, issue.Message)
+			case "severity":
+				fmt.Printf("  %s  %s  %s\n", location, rule, issue.Message)
+			default:
+				fmt.Printf("  %s  %s  %s\n",
+					ui.LineNumStyle.Render(fmt.Sprintf(":%d", issue.Line)),
+					rule,
+					issue.Message,
+				)
+			}
 		}
 	}
+	if hidden := len(issues) - shown; hidden > 0 {
+		fmt.Printf("\n%s\n", ui.DimStyle.Render(fmt.Sprintf("... and %d more", hidden)))
+	}
+
+	if *explain {
+		printExplanations(issues, cfg)
+	}
+
+	printFixed(fixed)
 
 	fmt.Println()
 	fmt.Println(ui.Divider())
@@ -118,11 +570,666 @@ func runCheck() {
 	fmt.Println()
 	fmt.Println(ui.DimStyle.Render("Run 'guardian' for interactive mode with /prompt to generate fixes."))
 
-	if critical > 0 {
+	if emitSummary {
+		printSummaryLine(critical, warnings, info, fileCount())
+	}
+
+	exitCheck(critical > 0, *exitZero)
+}
+
+// countFlag is --count. Bare, it counts every issue; with a severity,
+// only issues of that severity.
+type countFlag struct {
+	set      bool
+	severity string
+}
+
+func (c *countFlag) String() string { return c.severity }
+
+func (c *countFlag) IsBoolFlag() bool { return true }
+
+func (c *countFlag) Set(s string) error {
+	switch s {
+	case "true":
+		c.severity = ""
+	case "false":
+		c.set, c.severity = false, ""
+		return nil
+	case "critical", "warning", "info":
+		c.severity = s
+	default:
+		return fmt.Errorf("invalid severity %q (use critical, warning or info)", s)
+	}
+	c.set = true
+	return nil
+}
+
+// joinCountArg rewrites "--count critical" as "--count=critical". --count is
+// a bool-style flag so it can stand alone, which means the flag package
+// would otherwise take the severity for a path.
+func joinCountArg(args []string) []string {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if (args[i] == "--count" || args[i] == "-count") && i+1 < len(args) {
+			switch args[i+1] {
+			case "critical", "warning", "info":
+				out = append(out, args[i]+"="+args[i+1])
+				i++
+				continue
+			}
+		}
+		if args[i] == "--" {
+			return append(out, args[i:]...)
+		}
+		out = append(out, args[i])
+	}
+	return out
+}
+
+// noFilesToCheck reports a run with nothing to check: a note normally, or a
+// zero for --count so shell arithmetic still works
+func noFilesToCheck(count bool) {
+	if count {
+		fmt.Println(0)
+		return
+	}
+	fmt.Println("guardian: no files to check")
+}
+
+// runCheckCount prints the number of issues, or of those with severity, and
+// nothing else. The exit code still follows critical issues as usual.
+func runCheckCount(issues []checks.Issue, severity string) (failed bool) {
+	critical, warnings, info := countSeverities(issues)
+	switch severity {
+	case "critical":
+		fmt.Println(critical)
+	case "warning":
+		fmt.Println(warnings)
+	case "info":
+		fmt.Println(info)
+	default:
+		fmt.Println(len(issues))
+	}
+	return critical > 0
+}
+
+// exitCheck ends a check that found critical issues with status 1, unless
+// --exit-zero asked for success whatever was found
+func exitCheck(critical, exitZero bool) {
+	if critical && !exitZero {
 		os.Exit(1)
 	}
 }
 
+// warnStaleScaffolding warns when the scripts in .guardian/ were generated
+// by a different Guardian version, since they may not match this binary's
+// rules. It goes to stderr with the other run warnings.
+func warnStaleScaffolding(dir string) {
+	installed := scaffolding.InstalledVersion(dir)
+	if installed == "" || installed == version {
+		return
+	}
+	fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf(
+		"Installed scripts are from guardian %s, this is %s - re-run 'guardian add' to update them", installed, version)))
+}
+
+// warnSnoozes notes each snoozed rule, and each snooze that has run out and
+// can be deleted. Like warnStaleScaffolding it writes to stderr.
+func warnSnoozes(cfg *config.Config) {
+	active, expired := cfg.Snoozes(time.Now())
+	for _, s := range active {
+		fmt.Fprintln(os.Stderr, ui.Info(fmt.Sprintf("%s is snoozed until %s", s.Rule, s.Until)))
+	}
+	for _, s := range expired {
+		fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf(
+			"Snooze on %s ended %s - it's checked again; remove the [[snooze]] entry", s.Rule, s.Until)))
+	}
+}
+
+// printFileErrors reports files that were skipped (too large, or a check
+// panicked). It goes to stderr so --format guardian output stays parseable.
+func printFileErrors(errs []checks.FileError, verbose bool) {
+	for _, fe := range errs {
+		fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("%s: skipped, %s", fe.File, fe.Err)))
+		if verbose && fe.Stack != "" {
+			fmt.Fprintln(os.Stderr, ui.DimStyle.Render(fe.Stack))
+		}
+	}
+}
+
+// issueGroup is one heading in the check report and the issues under it
+type issueGroup struct {
+	key    string
+	issues []checks.Issue
+}
+
+// groupIssues groups issues by "file", "rule" or "severity". Groups appear in
+// the order of their first issue, except severity groups, which always go
+// critical, warning, info.
+func groupIssues(issues []checks.Issue, by string) []issueGroup {
+	var groups []issueGroup
+	index := make(map[string]int)
+
+	for _, issue := range issues {
+		key := issue.File
+		switch by {
+		case "rule":
+			key = issue.Rule
+		case "severity":
+			key = issue.Severity
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, issueGroup{key: key})
+		}
+		groups[i].issues = append(groups[i].issues, issue)
+	}
+
+	if by == "severity" {
+		rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+		sort.SliceStable(groups, func(a, b int) bool {
+			return rank[groups[a].key] < rank[groups[b].key]
+		})
+	}
+	return groups
+}
+
+// countSeverities tallies issues by severity; anything not critical or a
+// warning counts as info
+func countSeverities(issues []checks.Issue) (critical, warnings, info int) {
+	for _, issue := range issues {
+		switch issue.Severity {
+		case "critical":
+			critical++
+		case "warning":
+			warnings++
+		default:
+			info++
+		}
+	}
+	return critical, warnings, info
+}
+
+// severityStyle returns the style used for a severity's rule tags
+func severityStyle(severity string) lipgloss.Style {
+	switch severity {
+	case "critical":
+		return ui.CriticalStyle
+	case "warning":
+		return ui.WarningIssueStyle
+	default:
+		return ui.InfoIssueStyle
+	}
+}
+
+// runCheckFiles prints issues for an explicit file list in the one-line
+// file:line: form that pre-commit and editors display well. Like a full
+// check, the run only fails when there are critical issues.
+func runCheckFiles(issues []checks.Issue, files []string, cfg *config.Config, emitSummary, explain bool, maxIssues int) (failed bool) {
+	critical, warnings, info := countSeverities(issues)
+	for i, issue := range issues {
+		if maxIssues > 0 && i >= maxIssues {
+			fmt.Println(ui.DimStyle.Render(fmt.Sprintf("... and %d more", len(issues)-i)))
+			break
+		}
+		rule := severityStyle(issue.Severity).Render(fmt.Sprintf("[%s]", issue.Rule))
+		fmt.Printf("%s:%d: %s %s\n", issue.File, issue.Line, rule, issue.Message)
+	}
+
+	if explain && len(issues) > 0 {
+		printExplanations(issues, cfg)
+	}
+
+	checked := countCheckedFiles(files, cfg)
+
+	if len(issues) == 0 {
+		fmt.Println(ui.Success(fmt.Sprintf("No issues in %d files", checked)))
+	} else {
+		fmt.Printf("\n%d critical, %d warnings, %d info\n", critical, warnings, info)
+	}
+
+	if emitSummary {
+		printSummaryLine(critical, warnings, info, checked)
+	}
+
+	return critical > 0
+}
+
+// runCheckHook prints a pre-commit report: critical issues block the
+// commit and are listed first, everything else follows as a heads-up
+func runCheckHook(issues []checks.Issue, files []string, cfg *config.Config, emitSummary, explain bool) (failed bool) {
+	var blocking, other []checks.Issue
+	for _, issue := range issues {
+		if issue.Severity == "critical" {
+			blocking = append(blocking, issue)
+		} else {
+			other = append(other, issue)
+		}
+	}
+
+	printIssues := func(list []checks.Issue) {
+		for _, issue := range list {
+			rule := severityStyle(issue.Severity).Render(fmt.Sprintf("[%s]", issue.Rule))
+			fmt.Printf("  %s:%d: %s %s\n", issue.File, issue.Line, rule, issue.Message)
+		}
+	}
+	if len(blocking) > 0 {
+		fmt.Println(ui.CriticalStyle.Render(fmt.Sprintf("Blocking (%d)", len(blocking))))
+		printIssues(blocking)
+	}
+	if len(other) > 0 {
+		fmt.Println(ui.DimStyle.Render(fmt.Sprintf("Not blocking (%d)", len(other))))
+		printIssues(other)
+	}
+
+	if explain && len(issues) > 0 {
+		printExplanations(issues, cfg)
+	}
+
+	checked := countCheckedFiles(files, cfg)
+	critical, warnings, info := countSeverities(issues)
+	if len(issues) == 0 {
+		fmt.Println(ui.Success(fmt.Sprintf("No issues in %d files", checked)))
+	}
+	if emitSummary {
+		printSummaryLine(critical, warnings, info, checked)
+	}
+
+	if critical > 0 {
+		fmt.Println(ui.Error(fmt.Sprintf("Commit blocked by %d critical issues", critical)))
+	}
+	return critical > 0
+}
+
+// printEffectiveConfig prints cfg as JSON when format is "json" and as TOML
+// otherwise. The TOML starts with a comment listing where the values came
+// from; JSON has no comments, so it's just the config.
+func printEffectiveConfig(cfg *config.Config, sources []string, format string) {
+	if format != "json" {
+		format = "toml"
+	}
+	out, err := config.Dump(cfg, format)
+	if err != nil {
+		fmt.Println(ui.Error(fmt.Sprintf("Could not print config: %v", err)))
+		os.Exit(2)
+	}
+	if format == "toml" {
+		fmt.Println("# Effective config, merged in this order:")
+		for _, source := range sources {
+			fmt.Println("#   " + source)
+		}
+		fmt.Println()
+	}
+	fmt.Print(string(out))
+	if format == "json" {
+		fmt.Println()
+	}
+}
+
+// stagedFiles lists the files staged for commit under the current
+// directory, relative to it. Deleted files are left out.
+func stagedFiles() ([]string, error) {
+	out, err := exec.Command("git", "diff", "--cached", "--name-only", "--diff-filter=ACMR", "--relative", "-z").Output()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, path := range strings.Split(string(out), "\x00") {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// runCheckLines prints one unstyled line per issue and no headings: the
+// "file:line [rule] message" lines guardian.py emits, so the output can be
+// fed back through the same parser as the scripts, or GitHub annotations
+func runCheckLines(issues []checks.Issue, format func(checks.Issue) string, emitSummary bool, fileCount func() int) (failed bool) {
+	for _, issue := range issues {
+		fmt.Println(format(issue))
+	}
+
+	critical, warnings, info := countSeverities(issues)
+	if emitSummary {
+		printSummaryLine(critical, warnings, info, fileCount())
+	}
+
+	return critical > 0
+}
+
+// runCheckPorcelain prints one FormatPorcelain record per issue and nothing
+// else - no headings, summary or styling - for awk and cut
+func runCheckPorcelain(issues []checks.Issue) (failed bool) {
+	for _, issue := range issues {
+		if issue.Severity == "critical" {
+			failed = true
+		}
+		fmt.Println(checks.FormatPorcelain(issue))
+	}
+	return failed
+}
+
+// runCheckJUnit prints the issues as JUnit XML for CI test reporters. The
+// summary line is left out so stdout stays a single XML document.
+func runCheckJUnit(issues []checks.Issue) (failed bool) {
+	out, err := report.MarshalJUnit(issues)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.Error(fmt.Sprintf("Could not write JUnit report: %v", err)))
+		os.Exit(2)
+	}
+	os.Stdout.Write(out)
+
+	for _, issue := range issues {
+		if issue.Severity == "critical" {
+			return true
+		}
+	}
+	return false
+}
+
+// flagPassed reports whether a flag was set on the command line, as opposed
+// to left at its default
+func flagPassed(fs *flag.FlagSet, name string) bool {
+	passed := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+	return passed
+}
+
+// splitCheckArgs separates `guardian check` arguments into files and
+// directories. Paths that don't exist are treated as files, which file mode
+// skips, so a deleted file in a pre-commit run isn't mistaken for a root.
+func splitCheckArgs(args []string) (files, roots []string) {
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			roots = append(roots, arg)
+		} else {
+			files = append(files, arg)
+		}
+	}
+	return files, roots
+}
+
+// readFileList reads newline-separated paths from a file, or stdin for "-",
+// skipping blank lines
+func readFileList(name string) ([]string, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if path := strings.TrimSpace(line); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// absDir returns dir as an absolute path, checking it is a directory
+func absDir(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	return abs, nil
+}
+
+// relativePath rewrites path, relative to the working directory or
+// absolute, as relative to base. It's left as it is when it can't be.
+func relativePath(path, base string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(base, abs)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// existingFiles drops paths that don't exist, warning about each one - a
+// changed-files list includes files the change deleted
+func existingFiles(paths []string) []string {
+	var files []string
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("%s: skipped, file not found", path)))
+			continue
+		}
+		files = append(files, path)
+	}
+	return files
+}
+
+// addIncludeExt merges --include-ext mappings into cfg
+func addIncludeExt(cfg *config.Config, mapping map[string]string) {
+	if len(mapping) == 0 {
+		return
+	}
+	if cfg.Project.IncludeExt == nil {
+		cfg.Project.IncludeExt = make(map[string]string)
+	}
+	for ext, lang := range mapping {
+		cfg.Project.IncludeExt[ext] = lang
+	}
+}
+
+// countCheckedFiles counts the files in a file-mode run that checks apply to
+func countCheckedFiles(files []string, cfg *config.Config) int {
+	checked := 0
+	for _, file := range files {
+		if checks.IsCheckedFile(file, cfg) {
+			checked++
+		}
+	}
+	return checked
+}
+
+// printFixed lists issues resolved since the last run, if any
+func printFixed(fixed []checks.Issue) {
+	if len(fixed) == 0 {
+		return
+	}
+	fmt.Printf("\n%s\n", ui.SuccessStyle.Render(fmt.Sprintf("Fixed since last run (%d)", len(fixed))))
+	for _, issue := range fixed {
+		fmt.Printf("  %s  %s\n",
+			ui.FilePathStyle.Render(fmt.Sprintf("%s:%d", issue.File, issue.Line)),
+			ui.DimStyle.Render(fmt.Sprintf("[%s] %s", issue.Rule, issue.Message)))
+	}
+}
+
+// printExplanations prints the fix guidance for each rule in issues, once
+// per rule, in the order the rules first appear
+func printExplanations(issues []checks.Issue, cfg *config.Config) {
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("How to fix"))
+
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		if seen[issue.Rule] {
+			continue
+		}
+		seen[issue.Rule] = true
+
+		exp := prompts.GetExplanation(issue.Rule)
+		fmt.Printf("\n%s\n", ui.HighlightStyle.Render(fmt.Sprintf("[%s]", issue.Rule)))
+		fmt.Printf("  %s %s\n", ui.DimStyle.Render("What's wrong:"), exp.Problem)
+		fmt.Printf("  %s %s\n", ui.DimStyle.Render("Why it matters:"), exp.Why)
+		fmt.Printf("  %s %s\n", ui.DimStyle.Render("How to fix:"), exp.Fix)
+		if url := checks.RuleDocURL(issue.Rule, cfg); url != "" {
+			fmt.Printf("  %s %s\n", ui.DimStyle.Render("Docs:"), url)
+		}
+	}
+}
+
+// ruleExplanation is the JSON form of `guardian explain`, for editor hovers
+type ruleExplanation struct {
+	Rule     string `json:"rule"`
+	Problem  string `json:"problem"`
+	Why      string `json:"why"`
+	Fix      string `json:"fix"`
+	Severity string `json:"severity"`
+	DocsURL  string `json:"docs_url,omitempty"`
+}
+
+// runExplain prints what a rule flags, why it matters and how to fix it.
+// Unknown rules get the generic explanation rather than an error, so an
+// editor can hover any rule id, including custom ones.
+func runExplain(args []string) {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	format := fs.String("format", "text", "Output format: text or json")
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	fs.Parse(args)
+
+	// Allow flags after the rule: guardian explain ban-eval --format json
+	rule := fs.Arg(0)
+	if fs.NArg() > 0 {
+		fs.Parse(fs.Args()[1:])
+	}
+	if rule == "" || fs.NArg() > 0 {
+		fmt.Println(ui.Error("Usage: guardian explain <rule> [--format text|json]"))
+		os.Exit(2)
+	}
+	if *noColor {
+		ui.ConfigureColor(true)
+	}
+
+	exp := prompts.GetExplanation(rule)
+	severity := checks.RuleSeverity(rule)
+	cfg, err := config.Load(".")
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	docsURL := checks.RuleDocURL(rule, cfg)
+
+	switch *format {
+	case "json":
+		out, _ := json.Marshal(ruleExplanation{
+			Rule:     rule,
+			Problem:  exp.Problem,
+			Why:      exp.Why,
+			Fix:      exp.Fix,
+			Severity: severity,
+			DocsURL:  docsURL,
+		})
+		fmt.Println(string(out))
+	case "text":
+		fmt.Printf("%s %s\n", severityStyle(severity).Render(fmt.Sprintf("[%s]", rule)), ui.DimStyle.Render(severity))
+		fmt.Printf("  %s %s\n", ui.DimStyle.Render("What's wrong:"), exp.Problem)
+		fmt.Printf("  %s %s\n", ui.DimStyle.Render("Why it matters:"), exp.Why)
+		fmt.Printf("  %s %s\n", ui.DimStyle.Render("How to fix:"), exp.Fix)
+		if docsURL != "" {
+			fmt.Printf("  %s %s\n", ui.DimStyle.Render("Docs:"), docsURL)
+		}
+	default:
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --format: %s (use text or json)", *format)))
+		os.Exit(2)
+	}
+}
+
+// printSummaryLine prints the unstyled GUARDIAN_SUMMARY line. The format is
+// relied on by CI scripts, so keep it stable: only append new key=value pairs.
+func printSummaryLine(critical, warnings, info, files int) {
+	fmt.Printf("GUARDIAN_SUMMARY critical=%d warnings=%d info=%d files=%d\n", critical, warnings, info, files)
+}
+
+func runScan(args []string) {
+	fs := flag.NewFlagSet("scan", flag.ExitOnError)
+	offline := fs.Bool("offline", false, "Analyze locally without calling the API")
+	reportPath := fs.String("report", "", "Write found secrets to this file as \"file:line [rule] message\" lines")
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	fs.Parse(args)
+
+	if *noColor {
+		ui.ConfigureColor(true)
+	}
+
+	fmt.Println(ui.SmallLogo())
+	fmt.Println()
+
+	var results *ai.ScanResults
+	if *offline || ai.IsOffline() {
+		fmt.Println(ui.DimStyle.Render("Local scan - no code leaves this machine"))
+		results = ai.ScanLocal(".")
+	} else {
+		apiKey := ai.LoadKey()
+		if apiKey == "" {
+			fmt.Println(ui.Error("No API key found"))
+			fmt.Println()
+			fmt.Println("Set GEMINI_API_KEY, run 'guardian' → AI Setup, or use 'guardian scan --offline'.")
+			os.Exit(1)
+		}
+
+		var err error
+		results, err = ai.ScanProject(apiKey, ".")
+		if err != nil {
+			fmt.Println(ui.Error(fmt.Sprintf("Scan failed: %v", err)))
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Language:   %s\n", results.Language)
+	fmt.Printf("Framework:  %s\n", results.Framework)
+	fmt.Printf("Source:     %s\n", results.SourceDir)
+	fmt.Printf("Tests:      %s\n", results.TestDir)
+
+	if len(results.MockPatterns) > 0 {
+		fmt.Printf("Mock data:  %s\n", strings.Join(results.MockPatterns, ", "))
+	}
+	secrets := ai.SecretsToIssues(results)
+	if len(secrets) > 0 {
+		fmt.Println(ui.Warning(fmt.Sprintf("Found %d possible exposed keys", len(secrets))))
+		for _, issue := range secrets {
+			fmt.Println("  " + ui.FilePathStyle.Render(fmt.Sprintf("%s:%d", issue.File, issue.Line)))
+		}
+	}
+	if *reportPath != "" {
+		var sb strings.Builder
+		for _, issue := range secrets {
+			sb.WriteString(checks.FormatIssueLine(issue) + "\n")
+		}
+		if err := os.WriteFile(*reportPath, []byte(sb.String()), 0644); err != nil {
+			fmt.Println(ui.Error(fmt.Sprintf("Could not write report: %v", err)))
+			os.Exit(1)
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Wrote %d secrets to %s", len(secrets), *reportPath)))
+	}
+
+	if len(results.Recommendations) > 0 {
+		fmt.Println()
+		for _, rec := range results.Recommendations {
+			fmt.Println(ui.Success(rec))
+		}
+	}
+	for _, c := range results.Conflicts {
+		fmt.Println(ui.Warning(c))
+	}
+}
+
 // Valid languages for guardian add
 var validLanguages = map[string]bool{
 	"python":           true,
@@ -135,6 +1242,237 @@ var validLanguages = map[string]bool{
 	"php-laravel":      true,
 }
 
+func runPlan(args []string) {
+	fs := flag.NewFlagSet("plan", flag.ExitOnError)
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	fs.Parse(args)
+
+	if *noColor {
+		ui.ConfigureColor(true)
+	}
+
+	cfg, err := config.Load(".")
+	if err != nil {
+		fmt.Println(ui.Warning(fmt.Sprintf("Using default config: %v", err)))
+		cfg = config.DefaultConfig()
+	}
+
+	issues := checks.RunWithConfig(".", cfg)
+	printFileErrors(checks.TakeFileErrors(), false)
+
+	path := filepath.Join(".", prompts.PlanFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		fmt.Println(ui.Error(fmt.Sprintf("Could not create %s: %v", filepath.Dir(path), err)))
+		os.Exit(1)
+	}
+	if err := os.WriteFile(path, []byte(prompts.GeneratePlan(issues)), 0644); err != nil {
+		fmt.Println(ui.Error(fmt.Sprintf("Could not write fix plan: %v", err)))
+		os.Exit(1)
+	}
+
+	if len(issues) == 0 {
+		fmt.Println(ui.Success(fmt.Sprintf("No issues found - wrote empty plan to %s", prompts.PlanFile)))
+		return
+	}
+	fmt.Println(ui.Success(fmt.Sprintf("Wrote %d issues to %s", len(issues), prompts.PlanFile)))
+	fmt.Println(ui.DimStyle.Render("Hand it to your AI agent, or work through the checkboxes yourself."))
+}
+
+// runBench prints how fast the builtin checks get through a directory
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	rounds := fs.Int("rounds", 3, "Times to check the directory; the results are averaged")
+	fs.Parse(args)
+
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		fmt.Println(ui.Error(fmt.Sprintf("Not a directory: %s", dir)))
+		os.Exit(2)
+	}
+	if *rounds < 1 {
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --rounds: %d (use 1 or more)", *rounds)))
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadNearest(dir)
+	if err != nil {
+		fmt.Println(ui.Warning(fmt.Sprintf("Using default config: %v", err)))
+		cfg = config.DefaultConfig()
+	}
+
+	result := checks.Bench(dir, cfg, *rounds)
+	fmt.Printf("%d files, %d lines, %d issues\n", result.Files, result.Lines, result.Issues)
+	fmt.Printf("%-10s %s per round (%d rounds)\n", "time", result.Elapsed.Round(time.Microsecond), result.Rounds)
+	fmt.Printf("%-10s %.0f\n", "files/sec", result.FilesPerSec())
+	fmt.Printf("%-10s %.0f\n", "ns/line", result.NsPerLine())
+}
+
+func runScore(args []string) {
+	fs := flag.NewFlagSet("score", flag.ExitOnError)
+	minScore := fs.Int("min-score", 0, "Exit 1 if the score is below this (0-100)")
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	fs.Parse(args)
+
+	if *noColor {
+		ui.ConfigureColor(true)
+	}
+
+	if *minScore < 0 || *minScore > 100 {
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --min-score: %d (use 0-100)", *minScore)))
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(".")
+	if err != nil {
+		fmt.Println(ui.Warning(fmt.Sprintf("Using default config: %v", err)))
+		cfg = config.DefaultConfig()
+	}
+
+	issues := checks.RunWithConfig(".", cfg)
+	printFileErrors(checks.TakeFileErrors(), false)
+	info := checks.DryRunWithConfig(".", cfg)
+	score := checks.Score(issues, info.TotalLines, checks.DefaultScoreWeights)
+	counts := checks.Summarize(issues, time.Now())
+
+	fmt.Printf("Score: %d/100\n", score)
+	fmt.Println(ui.DimStyle.Render(fmt.Sprintf("%d critical · %d warnings · %d info in %d lines",
+		counts.Critical, counts.Warning, counts.Info, info.TotalLines)))
+
+	if score < *minScore {
+		fmt.Println(ui.Error(fmt.Sprintf("Score %d is below the minimum of %d", score, *minScore)))
+		os.Exit(1)
+	}
+}
+
+func runWatch(args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	fix := fs.Bool("fix", false, "Remove print()/console.log() and fix mutable defaults in saved files")
+	dryRun := fs.Bool("dry-run", false, "With --fix, print the diff of each fix instead of writing it")
+	interval := fs.Duration("interval", time.Second, "How often to look for changes")
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	fs.Parse(args)
+
+	if *noColor {
+		ui.ConfigureColor(true)
+	}
+	if *dryRun && !*fix {
+		fmt.Println(ui.Error("--dry-run only applies with --fix"))
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(".")
+	if err != nil {
+		fmt.Println(ui.Warning(fmt.Sprintf("Using default config: %v", err)))
+		cfg = config.DefaultConfig()
+	}
+
+	fmt.Println(ui.SmallLogo())
+	fmt.Println()
+	if *dryRun {
+		fmt.Println(ui.Info("Watching for changes - safe fixes are shown as diffs, nothing is written (Ctrl+C to stop)"))
+	} else if *fix {
+		fmt.Println(ui.Info("Watching for changes - safe fixes are applied on save (Ctrl+C to stop)"))
+	} else {
+		fmt.Println(ui.Info("Watching for changes (Ctrl+C to stop)"))
+	}
+
+	w := checks.NewWatcher(".", cfg, *fix)
+	w.DryRun = *dryRun
+	for {
+		time.Sleep(*interval)
+		for _, file := range w.Changed() {
+			printWatchResult(w.Handle(file))
+		}
+		printFileErrors(checks.TakeFileErrors(), false)
+	}
+}
+
+// printWatchResult reports what watch did with one saved file
+func printWatchResult(result checks.WatchResult) {
+	fmt.Printf("\n%s %s\n", ui.DimStyle.Render(time.Now().Format("15:04:05")), ui.FilePathStyle.Render(result.File))
+	if result.Err != nil {
+		fmt.Println(ui.Error(fmt.Sprintf("Could not fix: %v", result.Err)))
+	}
+	verb := "fixed"
+	if result.Diff != "" {
+		verb = "would fix"
+	}
+	for _, f := range result.Fixes {
+		fmt.Printf("  %s %s\n", ui.SuccessStyle.Render(fmt.Sprintf("%s :%d [%s]", verb, f.Line, f.Rule)), f.Description)
+	}
+	if result.Diff != "" {
+		fmt.Print(result.Diff)
+	}
+	for _, issue := range result.Issues {
+		rule := severityStyle(issue.Severity).Render(fmt.Sprintf("[%s]", issue.Rule))
+		fmt.Printf("  %s  %s  %s\n", ui.LineNumStyle.Render(fmt.Sprintf(":%d", issue.Line)), rule, issue.Message)
+	}
+	if len(result.Issues) == 0 {
+		fmt.Println("  " + ui.Success("No issues"))
+	}
+}
+
+func runStats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	since := fs.String("since", "", "Only include runs within this window (e.g. 30d, 2w, 12h)")
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	fs.Parse(args)
+
+	if *noColor {
+		ui.ConfigureColor(true)
+	}
+
+	entries, err := checks.LoadHistory(".")
+	if err != nil {
+		fmt.Println(ui.Error(fmt.Sprintf("Could not read history: %v", err)))
+		os.Exit(1)
+	}
+
+	if *since != "" {
+		window, err := checks.ParseSince(*since)
+		if err != nil {
+			fmt.Println(ui.Error(fmt.Sprintf("Invalid --since: %s (use e.g. 30d, 2w, 12h)", *since)))
+			os.Exit(2)
+		}
+		entries = checks.FilterSince(entries, time.Now().Add(-window))
+	}
+
+	fmt.Println(ui.SmallLogo())
+	fmt.Println()
+
+	if len(entries) == 0 {
+		fmt.Println(ui.Info("No recorded runs yet"))
+		fmt.Println(ui.DimStyle.Render("Run 'guardian check --record' to start tracking."))
+		return
+	}
+
+	fmt.Printf("  %-17s %8s %8s %6s %6s\n", "Run", "Critical", "Warnings", "Info", "Total")
+	totals := make([]int, len(entries))
+	for i, entry := range entries {
+		totals[i] = entry.Total
+		fmt.Printf("  %-17s %8d %8d %6d %6d\n",
+			entry.Time.Local().Format("2006-01-02 15:04"),
+			entry.Critical, entry.Warning, entry.Info, entry.Total)
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Divider())
+
+	trend := checks.ComputeTrend(entries)
+	summary := fmt.Sprintf("%s  %d runs · %d → %d issues", checks.Sparkline(totals), trend.Runs, trend.First.Total, trend.Last.Total)
+	switch trend.Direction {
+	case "down":
+		fmt.Printf("\n%s\n", ui.Success(fmt.Sprintf("%s (down %d)", summary, -trend.Delta)))
+	case "up":
+		fmt.Printf("\n%s\n", ui.Warning(fmt.Sprintf("%s (up %d)", summary, trend.Delta)))
+	default:
+		fmt.Printf("\n%s\n", ui.Info(summary+" (no change)"))
+	}
+}
+
 func runAdd() {
 	if len(os.Args) < 3 {
 		fmt.Println("Usage: guardian add <language>")
@@ -148,11 +1486,52 @@ func runAdd() {
 		fmt.Println("  go              Go project")
 		fmt.Println("  php             PHP project")
 		fmt.Println("  php-laravel     PHP + Laravel")
+		fmt.Println()
+		fmt.Println("Flags:")
+		fmt.Println("  --init-ci gitlab  Also add a guardian job to .gitlab-ci.yml")
+		fmt.Println("  --init-hook husky TypeScript: use a Husky pre-commit hook instead of pre-commit")
+		fmt.Println("  --no-precommit    Don't create or edit .pre-commit-config.yaml")
+		fmt.Println("  --no-config       Don't write guardian_config.toml")
+		fmt.Println("  --go-hook native  Go: run guardian check in pre-commit instead of guardian.sh")
+		fmt.Println("  --force           Overwrite .guardian/ scripts you've edited")
 		os.Exit(1)
 	}
 
 	lang := strings.ToLower(os.Args[2])
 
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	initCI := fs.String("init-ci", "", "Add a CI job that runs guardian check (gitlab)")
+	initHook := fs.String("init-hook", "", "Git hook manager to use instead of pre-commit (husky, TypeScript only)")
+	noPreCommit := fs.Bool("no-precommit", false, "Don't create or edit .pre-commit-config.yaml")
+	noConfig := fs.Bool("no-config", false, "Don't write guardian_config.toml")
+	goHook := fs.String("go-hook", "script", "Go pre-commit hook: script (.guardian/guardian.sh) or native (guardian check)")
+	force := fs.Bool("force", false, "Overwrite .guardian/ scripts that have been edited since they were installed")
+	fs.Parse(os.Args[3:])
+
+	switch *initCI {
+	case "", "gitlab":
+	default:
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --init-ci: %s (use gitlab)", *initCI)))
+		os.Exit(2)
+	}
+	switch *initHook {
+	case "":
+	case "husky":
+		if !strings.HasPrefix(lang, "typescript") {
+			fmt.Println(ui.Error("--init-hook husky is only for TypeScript projects"))
+			os.Exit(2)
+		}
+	default:
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --init-hook: %s (use husky)", *initHook)))
+		os.Exit(2)
+	}
+	switch *goHook {
+	case "script", "native":
+	default:
+		fmt.Println(ui.Error(fmt.Sprintf("Invalid --go-hook: %s (use script or native)", *goHook)))
+		os.Exit(2)
+	}
+
 	// Validate language
 	if !validLanguages[lang] {
 		fmt.Println(ui.Error(fmt.Sprintf("Unknown language: %s", lang)))
@@ -178,6 +1557,8 @@ func runAdd() {
 		language = "python"
 	} else if strings.HasPrefix(lang, "typescript") {
 		language = "typescript"
+	} else if strings.HasPrefix(lang, "php") {
+		language = "php"
 	}
 
 	fmt.Println(ui.SmallLogo())
@@ -191,25 +1572,54 @@ func runAdd() {
 		Stack:       stack,
 		SourceDir:   "src",
 		ExcludeDirs: []string{"tests", "__pycache__", "node_modules"},
+		CI:          *initCI,
+		GoHook:      *goHook,
+		Hook:        *initHook,
+
+		// Husky replaces pre-commit, so don't set up both
+		SkipPreCommit: *noPreCommit || *initHook == "husky",
+		SkipConfig:    *noConfig,
+		Force:         *force,
 	}
 
+	var kept []string
+	if !*force {
+		kept = scaffolding.EditedFiles(config)
+	}
 	if err := scaffolding.Install(config); err != nil {
 		fmt.Println(ui.Error(fmt.Sprintf("Failed to install: %v", err)))
 		os.Exit(1)
 	}
 
+	for _, path := range kept {
+		fmt.Println(ui.Warning(fmt.Sprintf("Kept %s - it's been edited (use --force to overwrite it)", path)))
+	}
 	fmt.Println(ui.Success("Created .guardian/ checks"))
-	fmt.Println(ui.Success("Created guardian_config.toml"))
-	fmt.Println(ui.Success("Created .pre-commit-config.yaml"))
+	if !*noConfig {
+		fmt.Println(ui.Success("Created guardian_config.toml"))
+	}
+	if *initHook == "husky" {
+		fmt.Println(ui.Success("Added guardian to .husky/pre-commit"))
+	} else if !*noPreCommit {
+		fmt.Println(ui.Success("Created .pre-commit-config.yaml"))
+	}
+	if *initCI == "gitlab" {
+		fmt.Println(ui.Success("Added guardian job to .gitlab-ci.yml"))
+	}
 
 	fmt.Println()
 	fmt.Println("Run 'guardian' to enter interactive mode.")
 }
 
-func runConfig() {
-	configPath := "guardian_config.toml"
+func runConfig(args []string) {
+	if len(args) > 0 && args[0] == "schema" {
+		// For editor completion and validation of guardian_config files
+		out, _ := json.MarshalIndent(config.Schema(), "", "  ")
+		fmt.Println(string(out))
+		return
+	}
 
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+	if !config.Exists(".") {
 		fmt.Println(ui.Error("No guardian_config.toml found"))
 		fmt.Println()
 		fmt.Println("Run 'guardian add <language>' to create one.")
@@ -256,6 +1666,7 @@ func runConfig() {
 		os.Exit(1)
 	}
 
+	configPath := config.GetConfigPath(".")
 	fmt.Printf("Opening %s in %s...\n", configPath, editor)
 
 	// Open editor - use only the validated command name
@@ -277,12 +1688,73 @@ func printHelp() {
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  (none)         Launch interactive mode")
-	fmt.Println("  check          Run all checks")
+	fmt.Println("  check [paths]  Run all checks (or just the given files, or each given")
+	fmt.Println("                 directory with its nearest config)")
+	fmt.Println("  scan           Smart scan with AI (--offline for local only)")
+	fmt.Println("  stats          Show issue trend from recorded runs (--since 30d)")
+	fmt.Println("  plan           Write a fix checklist to .guardian/fix-plan.md")
+	fmt.Println("  score          Print a 0-100 cleanliness score (--min-score 80 to gate)")
+	fmt.Println("  watch          Re-check files as they're saved (--fix applies safe fixes)")
+	fmt.Println("  explain <rule> Explain a rule (--format json for editor integrations)")
 	fmt.Println("  add <lang>     Add Guardian to project")
-	fmt.Println("  config         Open configuration")
+	fmt.Println("  config         Open configuration (config schema prints its JSON Schema)")
 	fmt.Println("  version        Print version")
 	fmt.Println("  help           Print this help")
 	fmt.Println()
+	fmt.Println("Check flags:")
+	fmt.Println("  --no-color     Disable colored output (also NO_COLOR=1; CLICOLOR_FORCE=1 keeps it when piped)")
+	fmt.Println("  --absolute     Show absolute file paths")
+	fmt.Println("  --relative-to DIR")
+	fmt.Println("                 Show file paths relative to DIR instead of the scan root")
+	fmt.Println("  --min-confidence low|medium|high")
+	fmt.Println("                 Hide heuristic matches below this confidence")
+	fmt.Println("  --record       Append run summary to .guardian/history.jsonl")
+	fmt.Println("  --summary-line Print GUARDIAN_SUMMARY line (always on when piped)")
+	fmt.Println("  --explain      Explain each rule found and how to fix it")
+	fmt.Println("  --group quality|security")
+	fmt.Println("                 Only report rules in that config section")
+	fmt.Println("  --group-by file|rule|severity")
+	fmt.Println("                 How to group the report (default file)")
+	fmt.Println("  --format text|guardian|github|junit")
+	fmt.Println("                 guardian prints plain \"file:line [rule] message\" lines,")
+	fmt.Println("                 github prints Actions annotations (default when GITHUB_ACTIONS=true),")
+	fmt.Println("                 junit prints JUnit XML for CI test reporters")
+	fmt.Println("  --baseline     Only report issues not in .guardian/baseline.json")
+	fmt.Println("  --baseline-update")
+	fmt.Println("                 Accept all current issues by rewriting the baseline")
+	fmt.Println("  --baseline-format strict|fuzzy")
+	fmt.Println("                 Match baseline issues by line number, or by the line's")
+	fmt.Println("                 text so they survive reformatting (default fuzzy)")
+	fmt.Println("  --max-issues N Show at most N issues; the summary still counts them all")
+	fmt.Println("  --porcelain    Print tab-separated severity, rule, file, line, message records")
+	fmt.Println("  --since-last-run")
+	fmt.Println("                 Only show issues that are new since the previous check")
+	fmt.Println("  --show-fixed   With --since-last-run, also list what was fixed")
+	fmt.Println("  --rules-file rules.toml")
+	fmt.Println("                 Add shared [[custom_rules]] from another file")
+	fmt.Println("  --include-ext .mjs=js,.pyi=python")
+	fmt.Println("                 Check extra extensions with python, js, go or shell rules")
+	fmt.Println("  --files-from changed.txt")
+	fmt.Println("                 Check only the listed files, one per line (- reads stdin)")
+	fmt.Println("  --follow-symlinks")
+	fmt.Println("                 Check what symlinked files and directories point to; by")
+	fmt.Println("                 default they're skipped and listed on stderr")
+	fmt.Println("  --exit-zero    Print the report as usual but always exit 0 for issues")
+	fmt.Println("  --changed-only Check the staged files when no files are passed, for use")
+	fmt.Println("                 as a bare git hook")
+	fmt.Println("  --count [critical|warning|info]")
+	fmt.Println("                 Print only the number of issues (of one severity), for scripts")
+	fmt.Println("  --print-config Print the effective config after defaults, file, env and")
+	fmt.Println("                 flags are merged, then exit (--format json for JSON)")
+	fmt.Println("  --hook-mode    For pre-commit: check staged files, fail only on critical")
+	fmt.Println("                 issues and list the rest as not blocking")
+	fmt.Println("  --engine auto|script|builtin|both")
+	fmt.Println("                 script fails (exit 2) instead of falling back when")
+	fmt.Println("                 .guardian/guardian.py is missing or python3 fails;")
+	fmt.Println("                 both runs the scripts and the builtin checks and merges them")
+	fmt.Println("  --no-builtin   Same as --engine script")
+	fmt.Println("  --verbose      Show stack traces for files whose checks failed")
+	fmt.Println()
 	fmt.Println("Interactive commands:")
 	fmt.Println("  /run           Check your code now")
 	fmt.Println("  /dry-run       Preview what would be checked")
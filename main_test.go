@@ -1,12 +1,21 @@
 package main
 
 import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/guardian-sh/guardian/internal/checks"
+	"github.com/guardian-sh/guardian/internal/config"
+	"github.com/guardian-sh/guardian/internal/prompts"
 )
 
 var (
@@ -15,6 +24,12 @@ var (
 	buildErr       error
 )
 
+func TestMain(m *testing.M) {
+	// Under GitHub Actions, check would switch to annotations by default
+	os.Unsetenv("GITHUB_ACTIONS")
+	os.Exit(m.Run())
+}
+
 // Build guardian binary once for all tests
 func getGuardianBinary(t *testing.T) string {
 	t.Helper()
@@ -224,214 +239,1651 @@ func TestCLI_Check_ExcludesHiddenDirs(t *testing.T) {
 	})
 }
 
-// ============================================================================
-// ADD COMMAND
-// ============================================================================
+func TestCLI_Check_NoANSIWhenPiped(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "test.py"), []byte(`result = eval("1+1")`), 0644)
 
-func TestCLI_Add_Python(t *testing.T) {
+		// CombinedOutput captures through a pipe, so stdout is not a TTY
+		output, _ := runGuardianInDir(t, dir, "check")
+
+		if strings.Contains(output, "\x1b[") {
+			t.Errorf("piped output should not contain ANSI escapes, got: %q", output)
+		}
+	})
+}
+
+func TestCLI_Check_NoColorFlag(t *testing.T) {
 	withTestProject(t, func(dir string) {
-		output, err := runGuardianInDir(t, dir, "add", "python")
-		if err != nil {
-			t.Fatalf("add python failed: %v\n%s", err, output)
+		os.WriteFile(filepath.Join(dir, "test.py"), []byte(`result = eval("1+1")`), 0644)
+
+		// Force color, so escapes missing below are down to --no-color and not
+		// to the output being piped
+		run := func(args ...string) string {
+			cmd := exec.Command(getGuardianBinary(t), args...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "CLICOLOR_FORCE=1", "NO_COLOR=")
+			output, _ := cmd.CombinedOutput()
+			return string(output)
 		}
 
-		// Should create .guardian directory
-		if _, err := os.Stat(filepath.Join(dir, ".guardian")); os.IsNotExist(err) {
-			t.Error(".guardian directory not created")
+		if output := run("check"); !strings.Contains(output, "\x1b[") {
+			t.Fatalf("expected CLICOLOR_FORCE=1 to color piped output, got: %q", output)
 		}
 
-		// Should create guardian_config.toml
-		if _, err := os.Stat(filepath.Join(dir, "guardian_config.toml")); os.IsNotExist(err) {
-			t.Error("guardian_config.toml not created")
+		output := run("check", "--no-color")
+		if strings.Contains(output, "\x1b[") {
+			t.Errorf("--no-color output should not contain ANSI escapes, got: %q", output)
+		}
+		if !strings.Contains(output, "ban-eval") {
+			t.Errorf("--no-color should still report issues, got: %s", output)
 		}
 	})
 }
 
-func TestCLI_Add_TypeScript(t *testing.T) {
+func TestCLI_Check_MessageTemplate(t *testing.T) {
 	withTestProject(t, func(dir string) {
-		output, err := runGuardianInDir(t, dir, "add", "typescript")
-		if err != nil {
-			t.Fatalf("add typescript failed: %v\n%s", err, output)
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("result = eval(data)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte(`
+[messages]
+"ban-eval" = "Banned, see https://wiki.internal/eval ({file}:{line})"
+`), 0644)
+
+		output, _ := runGuardianInDir(t, dir, "check")
+		if !strings.Contains(output, "Banned, see https://wiki.internal/eval (app.py:1)") {
+			t.Errorf("expected templated message, got: %s", output)
 		}
+	})
+}
 
-		if _, err := os.Stat(filepath.Join(dir, ".guardian")); os.IsNotExist(err) {
-			t.Error(".guardian directory not created for typescript")
+func TestCLI_Check_Explain(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "a.py"), []byte("x = eval(data)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "b.py"), []byte("y = eval(other)\n"), 0644)
+
+		output, _ := runGuardianInDir(t, dir, "check", "--explain")
+
+		fix := prompts.GetExplanation("ban-eval").Fix
+		if !strings.Contains(output, fix) {
+			t.Errorf("expected ban-eval fix guidance in output, got: %s", output)
+		}
+		if n := strings.Count(output, fix); n != 1 {
+			t.Errorf("explanation should appear once per rule, appeared %d times", n)
 		}
 	})
 }
 
-func TestCLI_Add_Go(t *testing.T) {
+func TestCLI_Check_ExplainLinksDocs(t *testing.T) {
 	withTestProject(t, func(dir string) {
-		output, err := runGuardianInDir(t, dir, "add", "go")
-		if err != nil {
-			t.Fatalf("add go failed: %v\n%s", err, output)
+		os.WriteFile(filepath.Join(dir, "a.py"), []byte("x = eval(data)\n"), 0644)
+
+		output, _ := runGuardianInDir(t, dir, "check", "--explain")
+		if !strings.Contains(output, "https://guardian.sh/rules/ban-eval") {
+			t.Errorf("expected the default docs link, got: %s", output)
 		}
 
-		if _, err := os.Stat(filepath.Join(dir, ".guardian")); os.IsNotExist(err) {
-			t.Error(".guardian directory not created for go")
+		os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte("[project]\ndocs_base_url = \"https://docs.internal/rules/\"\n"), 0644)
+		output, _ = runGuardianInDir(t, dir, "check", "--explain")
+		if !strings.Contains(output, "https://docs.internal/rules/ban-eval") || strings.Contains(output, "guardian.sh/rules") {
+			t.Errorf("expected the link to use docs_base_url, got: %s", output)
+		}
+
+		output, _ = runGuardianInDir(t, dir, "explain", "ban-eval", "--format", "json")
+		var got map[string]string
+		if err := json.Unmarshal([]byte(output), &got); err != nil || got["docs_url"] != "https://docs.internal/rules/ban-eval" {
+			t.Errorf("expected docs_url in explain JSON, got %q (%v)", output, err)
 		}
 	})
 }
 
-func TestCLI_Add_InvalidLanguage(t *testing.T) {
+func TestCLI_Check_NoExplainByDefault(t *testing.T) {
 	withTestProject(t, func(dir string) {
-		output, err := runGuardianInDir(t, dir, "add", "nonexistent")
+		os.WriteFile(filepath.Join(dir, "a.py"), []byte("x = eval(data)\n"), 0644)
 
-		// Should fail or warn about invalid language
-		if err == nil && !strings.Contains(strings.ToLower(output), "unknown") &&
-			!strings.Contains(strings.ToLower(output), "unsupported") &&
-			!strings.Contains(strings.ToLower(output), "invalid") {
-			t.Logf("add invalid language output: %s", output)
+		output, _ := runGuardianInDir(t, dir, "check")
+		if strings.Contains(output, prompts.GetExplanation("ban-eval").Fix) {
+			t.Errorf("explanations should only show with --explain, got: %s", output)
 		}
 	})
 }
 
-func TestCLI_Add_NoLanguage(t *testing.T) {
+func TestCLI_Check_IncludeExt(t *testing.T) {
 	withTestProject(t, func(dir string) {
-		output, err := runGuardianInDir(t, dir, "add")
+		os.WriteFile(filepath.Join(dir, "app.mjs"), []byte("console.log(\"debug\");\n"), 0644)
 
-		// Should show usage or error
-		if err == nil && !strings.Contains(strings.ToLower(output), "usage") &&
-			!strings.Contains(strings.ToLower(output), "language") {
-			t.Logf("add without language output: %s", output)
+		output, _ := runGuardianInDir(t, dir, "check")
+		if strings.Contains(output, "ban-console") {
+			t.Errorf(".mjs should not be checked by default, got: %s", output)
+		}
+
+		output, _ = runGuardianInDir(t, dir, "check", "--include-ext", ".mjs=js")
+		if !strings.Contains(output, "ban-console") {
+			t.Errorf("expected .mjs to be checked with JS rules, got: %s", output)
 		}
 	})
 }
 
-func TestCLI_Add_StackVariants(t *testing.T) {
-	stacks := []string{"python-fastapi", "python-django", "typescript-react"}
-	for _, stack := range stacks {
-		t.Run(stack, func(t *testing.T) {
-			withTestProject(t, func(dir string) {
-				output, err := runGuardianInDir(t, dir, "add", stack)
-				if err != nil {
-					t.Logf("add %s: %v\n%s", stack, err, output)
-				}
-			})
-		})
-	}
+func TestCLI_Check_IncludeExtInvalid(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "check", "--include-ext", ".rb=ruby")
+		if err == nil {
+			t.Errorf("expected non-zero exit for unknown language, got: %s", output)
+		}
+	})
 }
 
-// ============================================================================
-// CONFIG COMMAND
-// ============================================================================
+func TestCLI_Check_GroupByRule(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "a.py"), []byte("x = eval(data)\nprint(x)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "b.py"), []byte("y = eval(other)\nprint(y)\n"), 0644)
 
-func TestCLI_Config_NoConfigFile(t *testing.T) {
+		byFile, _ := runGuardianInDir(t, dir, "check")
+		byRule, _ := runGuardianInDir(t, dir, "check", "--group-by", "rule")
+
+		// Each rule is a single heading with both files listed under it
+		if n := strings.Count(byRule, "[ban-eval]"); n != 1 {
+			t.Errorf("expected one [ban-eval] heading, got %d:\n%s", n, byRule)
+		}
+		evalAt := strings.Index(byRule, "[ban-eval]")
+		printAt := strings.Index(byRule, "[ban-print]")
+		first, second := evalAt, printAt
+		if printAt < evalAt {
+			first, second = printAt, evalAt
+		}
+		section := byRule[first:second]
+		if !strings.Contains(section, "a.py:") || !strings.Contains(section, "b.py:") {
+			t.Errorf("expected both files under the first rule heading, got:\n%s", section)
+		}
+
+		summary := func(out string) string {
+			return out[strings.Index(out, "GUARDIAN_SUMMARY"):]
+		}
+		if summary(byFile) != summary(byRule) {
+			t.Errorf("counts changed with grouping: %q vs %q", summary(byFile), summary(byRule))
+		}
+	})
+}
+
+func TestCLI_Check_GroupByInvalid(t *testing.T) {
 	withTestProject(t, func(dir string) {
-		output, err := runGuardianInDir(t, dir, "config")
+		if _, err := runGuardianInDir(t, dir, "check", "--group-by", "owner"); err == nil {
+			t.Error("expected non-zero exit for invalid --group-by")
+		}
+	})
+}
 
-		// Should fail or warn when no config exists
-		if err == nil && !strings.Contains(strings.ToLower(output), "no") &&
-			!strings.Contains(strings.ToLower(output), "not found") {
-			// Might show editor, which is okay
-			t.Logf("config without config file: %s", output)
+func TestCLI_Check_FormatGuardian(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(data)\nprint(x)\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "check", "--format", "guardian")
+		if err == nil {
+			t.Error("expected non-zero exit for a critical issue")
+		}
+
+		want := []string{
+			"app.py:1 [ban-eval] Avoid eval() - security risk",
+			"app.py:2 [ban-print] Remove print() - use logging instead",
+		}
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if len(lines) < len(want) || strings.Join(lines[:len(want)], "\n") != strings.Join(want, "\n") {
+			t.Errorf("expected plain issue lines first, got:\n%s", output)
+		}
+		if strings.Contains(output, "\x1b[") {
+			t.Errorf("guardian format should not be styled:\n%s", output)
 		}
 	})
 }
 
-func TestCLI_Config_WithConfigFile(t *testing.T) {
-	// Skip this test - it tries to open an editor which hangs in CI/test env
-	t.Skip("Skipping - opens editor which blocks in test environment")
+func TestCLI_Check_Porcelain(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(data)\nprint(x)\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "check", "--porcelain", "--summary-line")
+		if err == nil {
+			t.Error("expected non-zero exit for a critical issue")
+		}
+
+		want := "critical\tban-eval\tapp.py\t1\tAvoid eval() - security risk\n" +
+			"info\tban-print\tapp.py\t2\tRemove print() - use logging instead\n"
+		if output != want {
+			t.Errorf("expected only porcelain records, got:\n%q", output)
+		}
+	})
 }
 
-// ============================================================================
-// UNKNOWN COMMAND
-// ============================================================================
+func TestCLI_Check_FollowSymlinks(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		shared := t.TempDir()
+		os.WriteFile(filepath.Join(shared, "util.py"), []byte("result = eval(x)\n"), 0644)
+		if err := os.Symlink(shared, filepath.Join(dir, "shared")); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = 1\n"), 0644)
 
-func TestCLI_UnknownCommand(t *testing.T) {
-	output, err := runGuardian(t, "unknowncommand")
+		output, err := runGuardianInDir(t, dir, "check", "--engine", "builtin")
+		if err != nil || strings.Contains(output, "ban-eval") {
+			t.Errorf("expected the symlinked dir skipped by default, got %v: %s", err, output)
+		}
+		if !strings.Contains(output, "shared: skipped") {
+			t.Errorf("expected the skipped symlink listed, got: %s", output)
+		}
 
-	// Should fail with error
-	if err == nil {
-		t.Error("unknown command should fail")
-	}
+		output, err = runGuardianInDir(t, dir, "check", "--engine", "builtin", "--follow-symlinks")
+		if err == nil || !strings.Contains(output, "ban-eval") {
+			t.Errorf("expected ban-eval in the symlinked dir with --follow-symlinks, got %v: %s", err, output)
+		}
+	})
+}
 
-	// Should mention unknown or show help
-	if !strings.Contains(strings.ToLower(output), "unknown") &&
-		!strings.Contains(strings.ToLower(output), "usage") {
-		t.Errorf("unknown command output should be helpful: %s", output)
-	}
+func TestCLI_Check_Count(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(data)\nprint(x)\nprint(data)\n"), 0644)
+
+		porcelain, _ := runGuardianInDir(t, dir, "check", "--porcelain")
+		records := strings.Split(strings.TrimSpace(porcelain), "\n")
+		critical := 0
+		for _, record := range records {
+			if strings.HasPrefix(record, "critical\t") {
+				critical++
+			}
+		}
+
+		output, err := runGuardianInDir(t, dir, "check", "--count")
+		if err == nil {
+			t.Error("expected non-zero exit for a critical issue")
+		}
+		if want := fmt.Sprintf("%d\n", len(records)); output != want {
+			t.Errorf("expected only the issue count %q, got %q", want, output)
+		}
+
+		output, _ = runGuardianInDir(t, dir, "check", "--count", "critical")
+		if want := fmt.Sprintf("%d\n", critical); output != want {
+			t.Errorf("expected only the critical count %q, got %q", want, output)
+		}
+
+		output, err = runGuardianInDir(t, dir, "check", "--count=info", "--exit-zero")
+		if err != nil || output != "2\n" {
+			t.Errorf("expected 2 info issues and exit 0, got %q (%v)", output, err)
+		}
+
+		if _, err := runGuardianInDir(t, dir, "check", "--count=fatal"); err == nil {
+			t.Error("expected an invalid severity to be rejected")
+		}
+
+		// A config warning mustn't end up in the count
+		os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte("[checks\n"), 0644)
+		cmd := exec.Command(getGuardianBinary(t), "check", "--count", "--exit-zero")
+		cmd.Dir = dir
+		stdout, _ := cmd.Output()
+		if want := fmt.Sprintf("%d\n", len(records)); string(stdout) != want {
+			t.Errorf("expected only the issue count %q on stdout with a bad config, got %q", want, stdout)
+		}
+	})
 }
 
-// ============================================================================
-// EDGE CASES
-// ============================================================================
+func TestCLI_Check_FormatGitHub(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(data)\nprint(x)\n"), 0644)
 
-func TestCLI_MultipleIssuesSameFile(t *testing.T) {
+		output, err := runGuardianInDir(t, dir, "check", "--format", "github")
+		if err == nil {
+			t.Error("expected non-zero exit for a critical issue")
+		}
+
+		want := []string{
+			"::error file=app.py,line=1,title=ban-eval::Avoid eval() - security risk",
+			"::notice file=app.py,line=2,title=ban-print::Remove print() - use logging instead",
+		}
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if len(lines) < len(want) || strings.Join(lines[:len(want)], "\n") != strings.Join(want, "\n") {
+			t.Errorf("expected workflow commands first, got:\n%s", output)
+		}
+	})
+}
+
+func TestCLI_Check_GitHubActionsDefault(t *testing.T) {
 	withTestProject(t, func(dir string) {
-		// Create a file with multiple issues
-		code := `# Multiple issues here
-result = eval("bad")
-result2 = exec("also bad")
-print("debug")
-from os import *
-password = "secret123"
-`
-		os.WriteFile(filepath.Join(dir, "bad.py"), []byte(code), 0644)
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(x)\n"), 0644)
+
+		cmd := exec.Command(getGuardianBinary(t), "check")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GITHUB_ACTIONS=true")
+		output, _ := cmd.CombinedOutput()
+		if !strings.Contains(string(output), "::notice file=app.py,line=1,title=ban-print::") {
+			t.Errorf("expected annotations under GitHub Actions, got:\n%s", output)
+		}
 
-		output, _ := runGuardianInDir(t, dir, "check")
+		cmd = exec.Command(getGuardianBinary(t), "check", "--format", "text")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GITHUB_ACTIONS=true")
+		output, _ = cmd.CombinedOutput()
+		if strings.Contains(string(output), "::notice") {
+			t.Errorf("an explicit --format should win over GITHUB_ACTIONS, got:\n%s", output)
+		}
+	})
+}
 
-		// Should find multiple issues
-		issueKeywords := []string{"eval", "print", "password", "import"}
-		foundCount := 0
-		for _, kw := range issueKeywords {
-			if strings.Contains(strings.ToLower(output), kw) {
-				foundCount++
-			}
+func TestCLI_Check_MaxIssues(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(a)\nprint(1)\nprint(2)\nprint(3)\nprint(4)\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "check", "--no-color", "--max-issues", "2", "--summary-line")
+		if err == nil {
+			t.Error("expected non-zero exit: the critical issue counts even when hidden")
 		}
 
-		if foundCount < 2 {
-			t.Errorf("should find multiple issues, output: %s", output)
+		if n := strings.Count(output, "[ban-print]") + strings.Count(output, "[ban-eval]"); n != 2 {
+			t.Errorf("expected 2 issues shown, got %d:\n%s", n, output)
+		}
+		if !strings.Contains(output, "... and 3 more") {
+			t.Errorf("expected a truncation note, got:\n%s", output)
+		}
+		if !strings.Contains(output, "GUARDIAN_SUMMARY critical=1 warnings=0 info=4") {
+			t.Errorf("expected summary to count every issue, got:\n%s", output)
 		}
 	})
 }
 
-func TestCLI_LargeFile(t *testing.T) {
+func TestCLI_Check_MaxIssuesCountsHiddenCritical(t *testing.T) {
 	withTestProject(t, func(dir string) {
-		// Create a large file (> 500 lines)
-		var lines []string
-		for i := 0; i < 510; i++ {
-			lines = append(lines, "x = 1")
+		os.WriteFile(filepath.Join(dir, "a.py"), []byte("print(1)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "b.py"), []byte("x = eval(a)\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "check", "--no-color", "--max-issues", "1")
+		if err == nil {
+			t.Errorf("expected non-zero exit for a critical issue past the cap:\n%s", output)
 		}
-		os.WriteFile(filepath.Join(dir, "large.py"), []byte(strings.Join(lines, "\n")), 0644)
+		if strings.Contains(output, "[ban-eval]") {
+			t.Errorf("expected the critical issue to be cut off by the cap:\n%s", output)
+		}
+	})
+}
 
-		output, _ := runGuardianInDir(t, dir, "check")
+func TestCLI_Check_BaselineUpdateThenFilter(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(data)\nprint(x)\n"), 0644)
 
-		// Should warn about file size
-		if !strings.Contains(strings.ToLower(output), "500") &&
-			!strings.Contains(strings.ToLower(output), "lines") &&
-			!strings.Contains(strings.ToLower(output), "size") {
-			t.Logf("large file output: %s", output)
+		if _, err := runGuardianInDir(t, dir, "check", "--baseline"); err == nil {
+			t.Error("expected --baseline to fail before a baseline exists")
+		}
+
+		output, err := runGuardianInDir(t, dir, "check", "--baseline-update")
+		if err != nil {
+			t.Fatalf("--baseline-update failed: %v\n%s", err, output)
+		}
+		saved, found, err := checks.LoadBaseline(dir)
+		if err != nil || !found || len(saved.Issues) != 2 || saved.Format != checks.BaselineFuzzy {
+			t.Fatalf("expected 2 accepted issues, got %+v (found=%v, err=%v)", saved, found, err)
+		}
+
+		// Accepted issues no longer fail the read-only run
+		output, err = runGuardianInDir(t, dir, "check", "--baseline", "--no-color")
+		if err != nil {
+			t.Errorf("expected a clean run against the baseline, got %v:\n%s", err, output)
+		}
+		if strings.Contains(output, "[ban-eval]") {
+			t.Errorf("accepted issue still reported:\n%s", output)
+		}
+
+		// ...but new ones do, and the read-only run leaves the baseline alone
+		os.WriteFile(filepath.Join(dir, "new.py"), []byte("y = eval(other)\n"), 0644)
+		output, err = runGuardianInDir(t, dir, "check", "--baseline", "--no-color")
+		if err == nil || !strings.Contains(output, "new.py") {
+			t.Errorf("expected the new critical issue to fail the run:\n%s", output)
+		}
+		if saved, _, _ := checks.LoadBaseline(dir); len(saved.Issues) != 2 {
+			t.Errorf("--baseline should not rewrite the baseline, now has %d issues", len(saved.Issues))
 		}
 	})
 }
 
-func TestCLI_UnicodeFilenames(t *testing.T) {
+func TestCLI_Check_BaselineFormat(t *testing.T) {
 	withTestProject(t, func(dir string) {
-		// Create file with unicode name
-		os.WriteFile(filepath.Join(dir, "тест.py"), []byte(`result = eval("x")`), 0644)
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(data)\n"), 0644)
 
-		output, _ := runGuardianInDir(t, dir, "check")
+		if _, err := runGuardianInDir(t, dir, "check", "--baseline-update", "--baseline-format", "loose"); err == nil {
+			t.Error("expected an invalid --baseline-format to fail")
+		}
 
-		// Should still find the issue
-		if !strings.Contains(output, "eval") {
-			t.Logf("unicode filename output: %s", output)
+		output, err := runGuardianInDir(t, dir, "check", "--baseline-update", "--baseline-format", "strict")
+		if err != nil {
+			t.Fatalf("--baseline-update failed: %v\n%s", err, output)
+		}
+		if saved, _, _ := checks.LoadBaseline(dir); saved == nil || saved.Format != checks.BaselineStrict {
+			t.Fatalf("expected a strict baseline, got %+v", saved)
+		}
+
+		// Strict baselines pin the line, so a shifted issue is new
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("import os\n\nx = eval(data)\n"), 0644)
+		output, err = runGuardianInDir(t, dir, "check", "--baseline", "--no-color")
+		if err == nil || !strings.Contains(output, "[ban-eval]") {
+			t.Errorf("expected the shifted issue to be reported under a strict baseline:\n%s", output)
 		}
 	})
 }
 
-func TestCLI_DeepNestedFiles(t *testing.T) {
+func TestCLI_Check_Snooze(t *testing.T) {
 	withTestProject(t, func(dir string) {
-		// Create deeply nested file
-		deepPath := filepath.Join(dir, "a/b/c/d/e")
-		os.MkdirAll(deepPath, 0755)
-		os.WriteFile(filepath.Join(deepPath, "deep.py"), []byte(`result = eval("x")`), 0644)
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("# TODO: handle retries\nx = 1\n"), 0644)
+		snooze := func(until time.Time) {
+			content := fmt.Sprintf("[[snooze]]\nrule = \"todo-marker\"\nuntil = %q\n", until.Format("2006-01-02"))
+			os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte(content), 0644)
+		}
 
-		output, _ := runGuardianInDir(t, dir, "check")
+		snooze(time.Now().AddDate(0, 0, 7))
+		output, _ := runGuardianInDir(t, dir, "check", "--no-color")
+		if strings.Contains(output, "[todo-marker]") {
+			t.Errorf("snoozed rule still reported:\n%s", output)
+		}
+		if !strings.Contains(output, "todo-marker is snoozed until") {
+			t.Errorf("expected a note that the rule is snoozed:\n%s", output)
+		}
 
-		// Should find deeply nested issue
-		if !strings.Contains(output, "eval") {
-			t.Errorf("should find deep nested issue, output: %s", output)
+		snooze(time.Now().AddDate(0, 0, -7))
+		output, _ = runGuardianInDir(t, dir, "check", "--no-color")
+		if !strings.Contains(output, "[todo-marker]") {
+			t.Errorf("expired snooze should let the rule fire again:\n%s", output)
+		}
+		if !strings.Contains(output, "Snooze on todo-marker ended") {
+			t.Errorf("expected a warning that the snooze expired:\n%s", output)
+		}
+	})
+}
+
+func TestCLI_Check_BaselineFlagsAreExclusive(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		if _, err := runGuardianInDir(t, dir, "check", "--baseline", "--baseline-update"); err == nil {
+			t.Error("expected --baseline and --baseline-update together to fail")
+		}
+	})
+}
+
+func TestCLI_Check_FormatJUnit(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(data)\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "check", "--format", "junit", "--no-color")
+		if err == nil {
+			t.Error("expected a critical issue to fail the run")
+		}
+		start := strings.Index(output, "<?xml")
+		if start < 0 {
+			t.Fatalf("expected a JUnit document, got:\n%s", output)
+		}
+		var suites struct {
+			Failures int `xml:"failures,attr"`
+		}
+		if err := xml.Unmarshal([]byte(output[start:]), &suites); err != nil || suites.Failures != 1 {
+			t.Errorf("expected 1 failure, got %+v (err=%v):\n%s", suites, err, output)
+		}
+		if strings.Contains(output, "GUARDIAN_SUMMARY") {
+			t.Errorf("the summary line would break the XML:\n%s", output)
+		}
+	})
+}
+
+func TestCLI_Check_FormatInvalid(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		if _, err := runGuardianInDir(t, dir, "check", "--format", "sarif"); err == nil {
+			t.Error("expected non-zero exit for invalid --format")
+		}
+	})
+}
+
+// ============================================================================
+// EXPLAIN COMMAND
+// ============================================================================
+
+func TestCLI_Explain_JSON(t *testing.T) {
+	output, err := runGuardian(t, "explain", "ban-eval", "--format", "json")
+	if err != nil {
+		t.Fatalf("explain failed: %v\n%s", err, output)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal([]byte(output), &got); err != nil {
+		t.Fatalf("expected JSON, got %q: %v", output, err)
+	}
+	exp := prompts.GetExplanation("ban-eval")
+	want := map[string]string{
+		"rule":     "ban-eval",
+		"problem":  exp.Problem,
+		"why":      exp.Why,
+		"fix":      exp.Fix,
+		"severity": "critical",
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s: expected %q, got %q", key, value, got[key])
+		}
+	}
+}
+
+func TestCLI_Explain_UnknownRuleFallsBack(t *testing.T) {
+	output, err := runGuardian(t, "explain", "--format", "json", "no-such-rule")
+	if err != nil {
+		t.Fatalf("explain failed: %v\n%s", err, output)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal([]byte(output), &got); err != nil {
+		t.Fatalf("expected JSON, got %q: %v", output, err)
+	}
+	generic := prompts.GetExplanation("no-such-rule")
+	if got["rule"] != "no-such-rule" || got["problem"] != generic.Problem || got["fix"] != generic.Fix || got["severity"] != "warning" {
+		t.Errorf("expected the generic explanation, got %+v", got)
+	}
+}
+
+func TestCLI_Explain_Text(t *testing.T) {
+	output, err := runGuardian(t, "explain", "ban-print", "--no-color")
+	if err != nil {
+		t.Fatalf("explain failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "[ban-print]") || !strings.Contains(output, prompts.GetExplanation("ban-print").Fix) {
+		t.Errorf("expected the rule's explanation, got:\n%s", output)
+	}
+
+	if _, err := runGuardian(t, "explain"); err == nil {
+		t.Error("expected non-zero exit without a rule")
+	}
+}
+
+func TestGroupIssues(t *testing.T) {
+	issues := []checks.Issue{
+		{File: "a.py", Rule: "ban-print", Severity: "info"},
+		{File: "a.py", Rule: "ban-eval", Severity: "critical"},
+		{File: "b.py", Rule: "ban-print", Severity: "info"},
+		{File: "b.py", Rule: "ban-except", Severity: "warning"},
+	}
+
+	byRule := groupIssues(issues, "rule")
+	if len(byRule) != 3 || byRule[0].key != "ban-print" || len(byRule[0].issues) != 2 {
+		t.Errorf("unexpected rule groups: %+v", byRule)
+	}
+
+	bySeverity := groupIssues(issues, "severity")
+	var keys []string
+	total := 0
+	for _, g := range bySeverity {
+		keys = append(keys, g.key)
+		total += len(g.issues)
+	}
+	if strings.Join(keys, ",") != "critical,warning,info" || total != len(issues) {
+		t.Errorf("severity groups should be ordered and keep every issue, got %v (%d issues)", keys, total)
+	}
+
+	byFile := groupIssues(issues, "file")
+	if len(byFile) != 2 || byFile[0].key != "a.py" {
+		t.Errorf("unexpected file groups: %+v", byFile)
+	}
+}
+
+func TestCLI_Check_MultipleRoots(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		for root, limit := range map[string]int{"a": 10, "b": 100} {
+			rootDir := filepath.Join(dir, "services", root)
+			os.MkdirAll(rootDir, 0755)
+			os.WriteFile(filepath.Join(rootDir, "guardian_config.toml"), []byte(fmt.Sprintf("[limits]\nmax_file_lines = %d\n", limit)), 0644)
+			os.WriteFile(filepath.Join(rootDir, "app.py"), []byte(strings.Repeat("x = 1\n", 50)), 0644)
+		}
+
+		output, _ := runGuardianInDir(t, dir, "check", "--format", "guardian", "services/a", "services/b")
+
+		if !strings.Contains(output, "services/a/app.py:1 [file-size]") {
+			t.Errorf("expected services/a to use its 10-line limit, got:\n%s", output)
+		}
+		if strings.Contains(output, "services/b/app.py:1 [file-size]") {
+			t.Errorf("services/b should use its own 100-line limit, got:\n%s", output)
+		}
+		if !strings.Contains(output, "files=2") {
+			t.Errorf("expected files from both roots to be counted, got:\n%s", output)
+		}
+	})
+}
+
+func TestCLI_Check_RelativeTo(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		repo := filepath.Join(dir, "repo")
+		root := filepath.Join(repo, "services", "api")
+		os.MkdirAll(root, 0755)
+		os.WriteFile(filepath.Join(root, "app.py"), []byte("print(\"debug\")\n"), 0644)
+		elsewhere := t.TempDir()
+
+		// Scanning an absolute root shows absolute paths by default
+		output, _ := runGuardianInDir(t, elsewhere, "check", "--format", "guardian", root)
+		if !strings.Contains(output, filepath.Join(root, "app.py")+":1 [ban-print]") {
+			t.Errorf("expected the scan root's absolute path, got:\n%s", output)
+		}
+
+		output, _ = runGuardianInDir(t, elsewhere, "check", "--format", "guardian", "--relative-to", repo, root)
+		want := filepath.Join("services", "api", "app.py") + ":1 [ban-print]"
+		if !strings.Contains(output, want) || strings.Contains(output, root) {
+			t.Errorf("expected %q relative to the repo, got:\n%s", want, output)
+		}
+
+		output, err := runGuardianInDir(t, elsewhere, "check", "--relative-to", filepath.Join(dir, "missing"), root)
+		if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 2 {
+			t.Errorf("expected exit 2 for a missing --relative-to, got %v:\n%s", err, output)
+		}
+	})
+}
+
+func TestCLI_Check_MixedFilesAndRoots(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.MkdirAll(filepath.Join(dir, "svc"), 0755)
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = 1\n"), 0644)
+
+		if _, err := runGuardianInDir(t, dir, "check", "app.py", "svc"); err == nil {
+			t.Error("expected non-zero exit when mixing files and directories")
+		}
+	})
+}
+
+func TestCLI_Check_RulesFile(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "security.toml"), []byte("[[custom_rules]]\nid = \"no-pickle\"\npattern = 'pickle\\.loads'\nseverity = \"critical\"\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("obj = pickle.loads(data)\nprint(obj)\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "check", "--format", "guardian", "--rules-file", "security.toml")
+		if err == nil {
+			t.Error("expected non-zero exit for a critical custom rule")
+		}
+		if !strings.Contains(output, "app.py:1 [no-pickle]") || !strings.Contains(output, "app.py:2 [ban-print]") {
+			t.Errorf("expected the custom rule alongside built-ins, got:\n%s", output)
+		}
+
+		if _, err := runGuardianInDir(t, dir, "check", "--rules-file", "missing.toml"); err == nil {
+			t.Error("expected non-zero exit for a missing rules file")
+		}
+	})
+}
+
+func TestCLI_Check_EnvDisable(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(\"debug\")\n"), 0644)
+
+		cmd := exec.Command(getGuardianBinary(t), "check")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GUARDIAN_DISABLE=ban-print")
+		output, _ := cmd.CombinedOutput()
+
+		if strings.Contains(string(output), "ban-print") {
+			t.Errorf("GUARDIAN_DISABLE=ban-print should suppress the rule, got: %s", output)
+		}
+	})
+}
+
+func TestCLI_Check_PrintConfig(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte("[limits]\nmax_file_lines = 500\nmax_function_lines = 40\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(\"debug\")\n"), 0644)
+
+		cmd := exec.Command(getGuardianBinary(t), "check", "--print-config")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GUARDIAN_MAX_FILE_LINES=300")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("--print-config failed: %v\n%s", err, output)
+		}
+
+		out := string(output)
+		if !strings.Contains(out, "max_file_lines = 300") {
+			t.Errorf("expected the env override on top of the file's 500, got: %s", out)
+		}
+		if !strings.Contains(out, "max_function_lines = 40") {
+			t.Errorf("expected the file value where there's no override, got: %s", out)
+		}
+		if !strings.Contains(out, "guardian_config.toml") || !strings.Contains(out, "GUARDIAN_MAX_FILE_LINES=300") {
+			t.Errorf("expected the sources listed, got: %s", out)
+		}
+		if strings.Contains(out, "ban-print") {
+			t.Errorf("expected no scan, got: %s", out)
+		}
+
+		cmd = exec.Command(getGuardianBinary(t), "check", "--print-config", "--format", "json")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GUARDIAN_MAX_FILE_LINES=300")
+		output, err = cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("--print-config --format json failed: %v\n%s", err, output)
+		}
+		var dumped config.Config
+		if err := json.Unmarshal(output, &dumped); err != nil {
+			t.Fatalf("expected JSON, got %v: %s", err, output)
+		}
+		if dumped.Limits.MaxFileLines != 300 || dumped.Limits.MaxFunctionLines != 40 {
+			t.Errorf("expected limits 300/40, got %+v", dumped.Limits)
+		}
+
+		if _, err := runGuardianInDir(t, dir, "check", "--format", "json"); err == nil {
+			t.Error("expected --format json without --print-config to be rejected")
+		}
+	})
+}
+
+func TestCLI_Check_SummaryLine(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("result = eval(\"1+1\")\nprint(\"x\")\nprint(\"y\")\ncfg = \"/home/alice/config.yaml\"\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "clean.py"), []byte("x = 1\n"), 0644)
+
+		output, _ := runGuardianInDir(t, dir, "check", "--summary-line")
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		last := lines[len(lines)-1]
+		want := "GUARDIAN_SUMMARY critical=1 warnings=0 info=3 files=2"
+		if last != want {
+			t.Errorf("expected final line %q, got %q", want, last)
+		}
+	})
+}
+
+func TestCLI_Check_SummaryLineWhenClean(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "clean.py"), []byte("x = 1\n"), 0644)
+
+		// Piped output (as in tests) gets the summary line without the flag
+		output, err := runGuardianInDir(t, dir, "check")
+		if err != nil {
+			t.Fatalf("check failed: %v\n%s", err, output)
+		}
+		if !strings.HasSuffix(strings.TrimSpace(output), "GUARDIAN_SUMMARY critical=0 warnings=0 info=0 files=1") {
+			t.Errorf("expected zero-count summary line, got: %s", output)
+		}
+	})
+}
+
+func TestCLI_Check_ExitZero(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("result = eval(data)\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "check", "--exit-zero", "--no-color")
+		if err != nil {
+			t.Errorf("--exit-zero should exit 0 with critical issues: %v\n%s", err, output)
+		}
+		if !strings.Contains(output, "[ban-eval]") || !strings.Contains(output, "1 critical") {
+			t.Errorf("the report should still be printed, got: %s", output)
+		}
+
+		for _, args := range [][]string{{"app.py"}, {"--format", "guardian"}, {"--porcelain"}, {"--format", "junit"}} {
+			args = append([]string{"check", "--exit-zero"}, args...)
+			output, err := runGuardianInDir(t, dir, args...)
+			if err != nil {
+				t.Errorf("%v: expected exit 0, got %v\n%s", args, err, output)
+			}
+			if !strings.Contains(output, "ban-eval") {
+				t.Errorf("%v: expected the issue in the output, got: %s", args, output)
+			}
+		}
+
+		if _, err := runGuardianInDir(t, dir, "check"); err == nil {
+			t.Error("without --exit-zero the critical issue should fail the check")
+		}
+	})
+}
+
+// Helper to run the binary the way a pre-commit hook does (PRE_COMMIT=1)
+func runAsPreCommit(t *testing.T, dir string, args ...string) (string, error) {
+	t.Helper()
+	cmd := exec.Command(getGuardianBinary(t), args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "PRE_COMMIT=1")
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+func TestCLI_Check_PreCommitFiles(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "clean.py"), []byte("x = 1\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "bad.py"), []byte("result = eval(data)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "untouched.py"), []byte("exec(data)\n"), 0644)
+
+		output, err := runAsPreCommit(t, dir, "check", "clean.py", "bad.py")
+		if err == nil {
+			t.Error("expected non-zero exit for a critical issue")
+		}
+		if !strings.Contains(output, "bad.py:1: [ban-eval]") {
+			t.Errorf("expected file:line: output for bad.py, got: %s", output)
+		}
+		if strings.Contains(output, "untouched.py") {
+			t.Errorf("files not passed as args should not be checked, got: %s", output)
+		}
+		if !strings.Contains(output, "GUARDIAN_SUMMARY critical=1 warnings=0 info=0 files=2") {
+			t.Errorf("expected summary for 2 files, got: %s", output)
+		}
+	})
+}
+
+func TestCLI_Check_PreCommitBelowThreshold(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(\"debug\")\n"), 0644)
+
+		output, err := runAsPreCommit(t, dir, "check", "app.py", "README.md")
+		if err != nil {
+			t.Errorf("non-critical issues should not fail the hook: %v\n%s", err, output)
+		}
+		if !strings.Contains(output, "app.py:1: [ban-print]") {
+			t.Errorf("expected ban-print to be reported, got: %s", output)
+		}
+	})
+}
+
+func TestCLI_Check_PreCommitNoFiles(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "bad.py"), []byte("result = eval(data)\n"), 0644)
+
+		output, err := runAsPreCommit(t, dir, "check")
+		if err != nil {
+			t.Errorf("no staged files should exit 0: %v\n%s", err, output)
+		}
+		if strings.Contains(output, "ban-eval") {
+			t.Errorf("should not scan the whole project under pre-commit with no files, got: %s", output)
+		}
+	})
+}
+
+// Helper to create a git repo in dir and stage files in it
+func stageFiles(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	for _, args := range [][]string{{"init", "-q"}, append([]string{"add"}, files...)} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestCLI_Check_HookMode(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(\"debug\")\nresult = eval(data)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "unstaged.py"), []byte("exec(data)\n"), 0644)
+		stageFiles(t, dir, "app.py")
+
+		output, err := runGuardianInDir(t, dir, "check", "--hook-mode", "--no-color")
+		if err == nil {
+			t.Errorf("eval should block the commit, got: %s", output)
+		}
+		blocking := strings.Index(output, "Blocking (1)")
+		notBlocking := strings.Index(output, "Not blocking (1)")
+		if blocking < 0 || notBlocking < blocking {
+			t.Fatalf("expected Blocking then Not blocking sections, got: %s", output)
+		}
+		if evalAt := strings.Index(output, "app.py:2: [ban-eval]"); evalAt < blocking || evalAt > notBlocking {
+			t.Errorf("ban-eval should be listed as blocking, got: %s", output)
+		}
+		if printAt := strings.Index(output, "app.py:1: [ban-print]"); printAt < notBlocking {
+			t.Errorf("ban-print should be listed as not blocking, got: %s", output)
+		}
+		if strings.Contains(output, "unstaged.py") {
+			t.Errorf("only staged files should be checked, got: %s", output)
+		}
+	})
+}
+
+func TestCLI_Check_HookModeNonBlocking(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(\"debug\")\n# TODO: tidy up\n"), 0644)
+		stageFiles(t, dir, "app.py")
+
+		output, err := runGuardianInDir(t, dir, "check", "--hook-mode", "--no-color")
+		if err != nil {
+			t.Errorf("prints and TODOs should not block: %v\n%s", err, output)
+		}
+		if !strings.Contains(output, "Not blocking (2)") || strings.Contains(output, "Blocking (") {
+			t.Errorf("expected only a Not blocking section, got: %s", output)
+		}
+	})
+}
+
+func TestCLI_Check_HookModeNothingStaged(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "bad.py"), []byte("result = eval(data)\n"), 0644)
+		stageFiles(t, dir)
+
+		output, err := runGuardianInDir(t, dir, "check", "--hook-mode")
+		if err != nil || !strings.Contains(output, "no files to check") {
+			t.Errorf("nothing staged should exit 0 without checking, got %v: %s", err, output)
+		}
+	})
+}
+
+func TestCLI_Check_ChangedOnly(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "staged.py"), []byte("result = eval(data)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "other.py"), []byte("exec(data)\n"), 0644)
+		stageFiles(t, dir, "staged.py")
+
+		output, err := runGuardianInDir(t, dir, "check", "--changed-only", "--no-color")
+		if err == nil {
+			t.Errorf("expected non-zero exit for eval in a staged file, got: %s", output)
+		}
+		if !strings.Contains(output, "staged.py:1: [ban-eval]") {
+			t.Errorf("expected the staged file to be checked, got: %s", output)
+		}
+		if strings.Contains(output, "other.py") {
+			t.Errorf("only staged files should be checked, got: %s", output)
+		}
+
+		// Files passed in, as pre-commit does, are checked as given
+		output, _ = runGuardianInDir(t, dir, "check", "--changed-only", "--no-color", "other.py")
+		if !strings.Contains(output, "other.py:1: [ban-eval]") || strings.Contains(output, "staged.py") {
+			t.Errorf("expected only the passed file to be checked, got: %s", output)
+		}
+	})
+}
+
+// ============================================================================
+// SCAN COMMAND
+// ============================================================================
+
+func TestCLI_Scan_Offline(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = 1\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "scan", "--offline")
+		if err != nil {
+			t.Fatalf("scan --offline failed: %v\n%s", err, output)
+		}
+		if !strings.Contains(output, "Python") {
+			t.Errorf("offline scan should detect Python, got: %s", output)
+		}
+	})
+}
+
+// ============================================================================
+// SINCE LAST RUN
+// ============================================================================
+
+func TestCLI_Check_SinceLastRun(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(\"a\")\nfrom os import *\n"), 0644)
+
+		runGuardianInDir(t, dir, "check")
+		if _, err := os.Stat(filepath.Join(dir, checks.LastRunFile)); !os.IsNotExist(err) {
+			t.Errorf("a plain check shouldn't write %s (stat: %v)", checks.LastRunFile, err)
+		}
+
+		first, _ := runGuardianInDir(t, dir, "check", "--since-last-run")
+		if !strings.Contains(first, "No previous run") || !strings.Contains(first, "[ban-print]") {
+			t.Errorf("first run should report everything, got:\n%s", first)
+		}
+
+		// Fix the print; the star import moves up a line but isn't new
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("from os import *\n"), 0644)
+
+		second, err := runGuardianInDir(t, dir, "check", "--since-last-run", "--show-fixed")
+		if err != nil {
+			t.Fatalf("second run failed: %v\n%s", err, second)
+		}
+		if !strings.Contains(second, "No new issues since last run") {
+			t.Errorf("expected zero new issues, got:\n%s", second)
+		}
+		if !strings.Contains(second, "Fixed since last run (1)") || !strings.Contains(second, "[ban-print]") {
+			t.Errorf("expected one fixed ban-print, got:\n%s", second)
+		}
+		if !strings.Contains(second, "GUARDIAN_SUMMARY critical=0 warnings=0 info=0") {
+			t.Errorf("summary should count only new issues, got:\n%s", second)
+		}
+	})
+}
+
+// ============================================================================
+// PLAN COMMAND
+// ============================================================================
+
+func TestCLI_Plan_WritesChecklist(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.MkdirAll(filepath.Join(dir, "src"), 0755)
+		os.WriteFile(filepath.Join(dir, "src", "app.py"), []byte("x = eval(data)\nprint(x)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "util.py"), []byte("try:\n    run()\nexcept:\n    pass\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "plan")
+		if err != nil {
+			t.Fatalf("plan failed: %v\n%s", err, output)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, prompts.PlanFile))
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", prompts.PlanFile, err)
+		}
+		plan := string(data)
+
+		for _, want := range []struct{ file, rule string }{
+			{"src/app.py", "ban-eval"},
+			{"src/app.py", "ban-print"},
+			{"util.py", "ban-except"},
+		} {
+			if !strings.Contains(plan, "## "+want.file+"\n") {
+				t.Errorf("plan missing heading for %s:\n%s", want.file, plan)
+			}
+			checkbox := false
+			for _, line := range strings.Split(plan, "\n") {
+				if strings.HasPrefix(line, "- [ ] ") && strings.Contains(line, "`"+want.rule+"`") {
+					checkbox = true
+				}
+			}
+			if !checkbox {
+				t.Errorf("plan missing checkbox for %s:\n%s", want.rule, plan)
+			}
+			if fix := prompts.GetExplanation(want.rule).Fix; !strings.Contains(plan, "Fix: "+fix) {
+				t.Errorf("plan missing fix text for %s:\n%s", want.rule, plan)
+			}
+		}
+	})
+}
+
+func TestCLI_Plan_NoIssues(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("def add(a, b):\n    return a + b\n"), 0644)
+
+		if output, err := runGuardianInDir(t, dir, "plan"); err != nil {
+			t.Fatalf("plan failed: %v\n%s", err, output)
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, prompts.PlanFile))
+		if strings.Contains(string(data), "- [ ]") {
+			t.Errorf("expected no checkboxes for a clean project:\n%s", data)
+		}
+	})
+}
+
+// ============================================================================
+// SCAFFOLDING VERSION
+// ============================================================================
+
+func TestCLI_Check_WarnsOnStaleScripts(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = 1\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "guardian_config.toml"), []byte("# guardian-version: 0.0.1\n"), 0644)
+
+		output, _ := runGuardianInDir(t, dir, "check")
+		if !strings.Contains(output, "Installed scripts are from guardian 0.0.1") {
+			t.Errorf("expected a stale scaffolding warning:\n%s", output)
+		}
+	})
+}
+
+// ============================================================================
+// SCORE COMMAND
+// ============================================================================
+
+func TestCLI_Score_GatesOnMinScore(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(data)\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "score")
+		if err != nil {
+			t.Fatalf("score without --min-score should pass: %v\n%s", err, output)
+		}
+		if !strings.Contains(output, "Score: ") {
+			t.Errorf("expected a score line:\n%s", output)
+		}
+
+		output, err = runGuardianInDir(t, dir, "score", "--min-score", "80")
+		if err == nil {
+			t.Errorf("expected exit 1 below --min-score 80:\n%s", output)
+		}
+	})
+}
+
+func TestCLI_Score_CleanProject(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("def add(a, b):\n    return a + b\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "score", "--min-score", "100")
+		if err != nil {
+			t.Fatalf("clean project should pass --min-score 100: %v\n%s", err, output)
+		}
+		if !strings.Contains(output, "Score: 100/100") {
+			t.Errorf("expected a perfect score:\n%s", output)
+		}
+	})
+}
+
+// ============================================================================
+// STATS COMMAND
+// ============================================================================
+
+func TestCLI_Stats_RecordedRuns(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(\"a\")\nprint(\"b\")\n"), 0644)
+		runGuardianInDir(t, dir, "check", "--record")
+
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(\"a\")\n"), 0644)
+		runGuardianInDir(t, dir, "check", "--record")
+
+		output, err := runGuardianInDir(t, dir, "stats", "--since", "30d")
+		if err != nil {
+			t.Fatalf("stats failed: %v\n%s", err, output)
+		}
+		if !strings.Contains(output, "2 runs") || !strings.Contains(output, "down 1") {
+			t.Errorf("expected downward trend over 2 runs, got: %s", output)
+		}
+	})
+}
+
+func TestCLI_Check_RecordIgnoresReportFilters(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(\"a\")\npath = \"/home/alice/data\"\n"), 0644)
+		runGuardianInDir(t, dir, "check", "--record")
+		runGuardianInDir(t, dir, "check", "--record", "--min-confidence", "high")
+
+		data, err := os.ReadFile(filepath.Join(dir, checks.HistoryFile))
+		if err != nil {
+			t.Fatalf("expected history to be recorded: %v", err)
+		}
+		var totals []int
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var entry checks.HistoryEntry
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("invalid history line %q: %v", line, err)
+			}
+			totals = append(totals, entry.Total)
+		}
+		if len(totals) != 2 || totals[0] != totals[1] || totals[0] < 2 {
+			t.Errorf("expected both runs to record every issue found, got totals %v", totals)
+		}
+	})
+}
+
+func TestCLI_Watch_DryRunWithoutFix(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "watch", "--dry-run")
+		if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 2 {
+			t.Errorf("expected exit 2 for --dry-run without --fix, got %v:\n%s", err, output)
+		}
+	})
+}
+
+func TestCLI_Stats_NoHistory(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "stats")
+		if err != nil {
+			t.Fatalf("stats failed: %v\n%s", err, output)
+		}
+		if !strings.Contains(output, "No recorded runs") {
+			t.Errorf("expected empty-history message, got: %s", output)
+		}
+	})
+}
+
+func TestCLI_Stats_InvalidSince(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		_, err := runGuardianInDir(t, dir, "stats", "--since", "soon")
+		if err == nil {
+			t.Error("expected non-zero exit for invalid --since")
+		}
+	})
+}
+
+// ============================================================================
+// ADD COMMAND
+// ============================================================================
+
+func TestCLI_Add_Python(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "add", "python")
+		if err != nil {
+			t.Fatalf("add python failed: %v\n%s", err, output)
+		}
+
+		// Should create .guardian directory
+		if _, err := os.Stat(filepath.Join(dir, ".guardian")); os.IsNotExist(err) {
+			t.Error(".guardian directory not created")
+		}
+
+		// Should create guardian_config.toml
+		if _, err := os.Stat(filepath.Join(dir, "guardian_config.toml")); os.IsNotExist(err) {
+			t.Error("guardian_config.toml not created")
+		}
+	})
+}
+
+func TestCLI_Add_TypeScript(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "add", "typescript")
+		if err != nil {
+			t.Fatalf("add typescript failed: %v\n%s", err, output)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, ".guardian")); os.IsNotExist(err) {
+			t.Error(".guardian directory not created for typescript")
+		}
+	})
+}
+
+func TestCLI_Add_Go(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "add", "go")
+		if err != nil {
+			t.Fatalf("add go failed: %v\n%s", err, output)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, ".guardian")); os.IsNotExist(err) {
+			t.Error(".guardian directory not created for go")
+		}
+	})
+}
+
+func TestCLI_Add_InvalidLanguage(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "add", "nonexistent")
+
+		// Should fail or warn about invalid language
+		if err == nil && !strings.Contains(strings.ToLower(output), "unknown") &&
+			!strings.Contains(strings.ToLower(output), "unsupported") &&
+			!strings.Contains(strings.ToLower(output), "invalid") {
+			t.Logf("add invalid language output: %s", output)
+		}
+	})
+}
+
+func TestCLI_Add_NoLanguage(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "add")
+
+		// Should show usage or error
+		if err == nil && !strings.Contains(strings.ToLower(output), "usage") &&
+			!strings.Contains(strings.ToLower(output), "language") {
+			t.Logf("add without language output: %s", output)
+		}
+	})
+}
+
+func TestCLI_Add_StackVariants(t *testing.T) {
+	stacks := []string{"python-fastapi", "python-django", "typescript-react"}
+	for _, stack := range stacks {
+		t.Run(stack, func(t *testing.T) {
+			withTestProject(t, func(dir string) {
+				output, err := runGuardianInDir(t, dir, "add", stack)
+				if err != nil {
+					t.Logf("add %s: %v\n%s", stack, err, output)
+				}
+			})
+		})
+	}
+}
+
+func TestCLI_Add_PHPLaravelUsesPHPHook(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "add", "php-laravel")
+		if err != nil {
+			t.Fatalf("add php-laravel failed: %v\n%s", err, output)
+		}
+		content, _ := os.ReadFile(filepath.Join(dir, ".pre-commit-config.yaml"))
+		if !strings.Contains(string(content), "types: [php]") {
+			t.Errorf("php-laravel should get the PHP hook, got:\n%s", content)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".guardian", "guardian.php")); err != nil {
+			t.Errorf("guardian.php not installed: %v", err)
+		}
+	})
+}
+
+func TestCLI_Add_GoNativeHook(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "add", "go", "--go-hook", "native")
+		if err != nil {
+			t.Fatalf("add go failed: %v\n%s", err, output)
+		}
+		content, _ := os.ReadFile(filepath.Join(dir, ".pre-commit-config.yaml"))
+		if !strings.Contains(string(content), "entry: guardian check") {
+			t.Errorf("hook should run guardian check, got:\n%s", content)
+		}
+	})
+
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "add", "go", "--go-hook", "vet")
+		if err == nil || !strings.Contains(output, "Invalid --go-hook") {
+			t.Errorf("invalid --go-hook should fail, got err=%v:\n%s", err, output)
+		}
+	})
+}
+
+func TestCLI_Add_HuskyHook(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "add", "typescript", "--init-hook", "husky")
+		if err != nil {
+			t.Fatalf("add typescript failed: %v\n%s", err, output)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".husky", "pre-commit")); err != nil {
+			t.Errorf(".husky/pre-commit not created: %v", err)
+		}
+	})
+
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "add", "python", "--init-hook", "husky")
+		if err == nil || !strings.Contains(output, "only for TypeScript") {
+			t.Errorf("husky on a Python project should fail, got err=%v:\n%s", err, output)
+		}
+	})
+}
+
+// ============================================================================
+// BENCH COMMAND
+// ============================================================================
+
+func TestCLI_Bench(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(data)\nprint(x)\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "bench", "--rounds", "1", ".")
+		if err != nil {
+			t.Fatalf("bench failed: %v\n%s", err, output)
+		}
+		for _, want := range []string{"1 files, 2 lines", "files/sec", "ns/line"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("missing %q in:\n%s", want, output)
+			}
+		}
+
+		if _, err := runGuardianInDir(t, dir, "bench", "missing-dir"); err == nil {
+			t.Error("expected bench on a missing directory to fail")
+		}
+	})
+}
+
+// ============================================================================
+// CONFIG COMMAND
+// ============================================================================
+
+func TestCLI_Config_NoConfigFile(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		output, err := runGuardianInDir(t, dir, "config")
+
+		// Should fail or warn when no config exists
+		if err == nil && !strings.Contains(strings.ToLower(output), "no") &&
+			!strings.Contains(strings.ToLower(output), "not found") {
+			// Might show editor, which is okay
+			t.Logf("config without config file: %s", output)
+		}
+	})
+}
+
+func TestCLI_Config_WithConfigFile(t *testing.T) {
+	// Skip this test - it tries to open an editor which hangs in CI/test env
+	t.Skip("Skipping - opens editor which blocks in test environment")
+}
+
+func TestCLI_Config_Schema(t *testing.T) {
+	output, err := runGuardian(t, "config", "schema")
+	if err != nil {
+		t.Fatalf("config schema failed: %v\n%s", err, output)
+	}
+
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(output), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v\n%s", err, output)
+	}
+	for _, section := range []string{"project", "limits", "quality", "security", "rules"} {
+		if _, ok := schema.Properties[section]; !ok {
+			t.Errorf("schema is missing [%s]", section)
+		}
+	}
+}
+
+// ============================================================================
+// UNKNOWN COMMAND
+// ============================================================================
+
+func TestCLI_UnknownCommand(t *testing.T) {
+	output, err := runGuardian(t, "unknowncommand")
+
+	// Should fail with error
+	if err == nil {
+		t.Error("unknown command should fail")
+	}
+
+	// Should mention unknown or show help
+	if !strings.Contains(strings.ToLower(output), "unknown") &&
+		!strings.Contains(strings.ToLower(output), "usage") {
+		t.Errorf("unknown command output should be helpful: %s", output)
+	}
+}
+
+// ============================================================================
+// EDGE CASES
+// ============================================================================
+
+func TestCLI_MultipleIssuesSameFile(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		// Create a file with multiple issues
+		code := `# Multiple issues here
+result = eval("bad")
+result2 = exec("also bad")
+print("debug")
+from os import *
+password = "secret123"
+`
+		os.WriteFile(filepath.Join(dir, "bad.py"), []byte(code), 0644)
+
+		output, _ := runGuardianInDir(t, dir, "check")
+
+		// Should find multiple issues
+		issueKeywords := []string{"eval", "print", "password", "import"}
+		foundCount := 0
+		for _, kw := range issueKeywords {
+			if strings.Contains(strings.ToLower(output), kw) {
+				foundCount++
+			}
+		}
+
+		if foundCount < 2 {
+			t.Errorf("should find multiple issues, output: %s", output)
+		}
+	})
+}
+
+func TestCLI_LargeFile(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		// Create a large file (> 500 lines)
+		var lines []string
+		for i := 0; i < 510; i++ {
+			lines = append(lines, "x = 1")
+		}
+		os.WriteFile(filepath.Join(dir, "large.py"), []byte(strings.Join(lines, "\n")), 0644)
+
+		output, _ := runGuardianInDir(t, dir, "check")
+
+		// Should warn about file size
+		if !strings.Contains(strings.ToLower(output), "500") &&
+			!strings.Contains(strings.ToLower(output), "lines") &&
+			!strings.Contains(strings.ToLower(output), "size") {
+			t.Logf("large file output: %s", output)
+		}
+	})
+}
+
+func TestCLI_UnicodeFilenames(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		// Create file with unicode name
+		os.WriteFile(filepath.Join(dir, "тест.py"), []byte(`result = eval("x")`), 0644)
+
+		output, _ := runGuardianInDir(t, dir, "check")
+
+		// Should still find the issue
+		if !strings.Contains(output, "eval") {
+			t.Logf("unicode filename output: %s", output)
+		}
+	})
+}
+
+func TestCLI_DeepNestedFiles(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		// Create deeply nested file
+		deepPath := filepath.Join(dir, "a/b/c/d/e")
+		os.MkdirAll(deepPath, 0755)
+		os.WriteFile(filepath.Join(deepPath, "deep.py"), []byte(`result = eval("x")`), 0644)
+
+		output, _ := runGuardianInDir(t, dir, "check")
+
+		// Should find deeply nested issue
+		if !strings.Contains(output, "eval") {
+			t.Errorf("should find deep nested issue, output: %s", output)
+		}
+	})
+}
+
+func TestCLI_Check_OutputIsStable(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		for _, name := range []string{"zeta.py", "alpha.py", "mid/beta.py", "mid/alpha.js"} {
+			path := filepath.Join(dir, name)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			os.WriteFile(path, []byte("print(\"a\")\nresult = eval(\"x\")\nconsole.log(\"b\")\n"), 0644)
+		}
+
+		first, _ := runGuardianInDir(t, dir, "check", "--no-color")
+		second, _ := runGuardianInDir(t, dir, "check", "--no-color")
+		if first != second {
+			t.Errorf("two runs differ:\n%s\n---\n%s", first, second)
+		}
+		if strings.Index(first, "alpha.py") > strings.Index(first, "zeta.py") {
+			t.Errorf("files should be listed in sorted order, got:\n%s", first)
+		}
+	})
+}
+
+// ============================================================================
+// CHECK ENGINE
+// ============================================================================
+
+func TestCLI_Check_EngineScriptRequiresScripts(t *testing.T) {
+	for _, args := range [][]string{{"--engine", "script"}, {"--no-builtin"}} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			withTestProject(t, func(dir string) {
+				os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(data)\n"), 0644)
+
+				output, err := runGuardianInDir(t, dir, append([]string{"check", "--no-color"}, args...)...)
+				var exitErr *exec.ExitError
+				if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+					t.Fatalf("expected exit 2 without guardian.py, got %v:\n%s", err, output)
+				}
+				if !strings.Contains(output, "guardian.py is not installed") {
+					t.Errorf("expected the missing script to be named:\n%s", output)
+				}
+				if strings.Contains(output, "ban-eval") {
+					t.Errorf("should not fall back to the builtin checks:\n%s", output)
+				}
+			})
+		})
+	}
+}
+
+func TestCLI_Check_EngineBuiltin(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = eval(data)\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "check", "--no-color", "--engine", "builtin")
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 || !strings.Contains(output, "ban-eval") {
+			t.Errorf("expected the builtin checks to find ban-eval and exit 1, got %v:\n%s", err, output)
+		}
+	})
+}
+
+func TestCLI_Check_EngineInvalid(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		if _, err := runGuardianInDir(t, dir, "check", "--engine", "python"); err == nil {
+			t.Error("expected non-zero exit for invalid --engine")
+		}
+		if _, err := runGuardianInDir(t, dir, "check", "--engine", "script", "app.py"); err == nil {
+			t.Error("expected --engine script with files to fail")
+		}
+	})
+}
+
+// ============================================================================
+// FILES FROM A LIST
+// ============================================================================
+
+func TestCLI_Check_FilesFrom(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "bad.py"), []byte("x = eval(data)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "other.py"), []byte("y = eval(data)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "changed.txt"), []byte("bad.py\n\ndeleted.py\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "check", "--no-color", "--files-from", "changed.txt")
+		if err == nil {
+			t.Errorf("expected exit 1 for the critical issue in bad.py:\n%s", output)
+		}
+		if !strings.Contains(output, "bad.py:1:") {
+			t.Errorf("expected bad.py to be checked:\n%s", output)
+		}
+		if strings.Contains(output, "other.py") {
+			t.Errorf("only listed files should be checked:\n%s", output)
+		}
+		if !strings.Contains(output, "deleted.py: skipped, file not found") {
+			t.Errorf("expected a warning for the missing file:\n%s", output)
+		}
+	})
+}
+
+func TestCLI_Check_FilesFromStdin(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "bad.py"), []byte("x = eval(data)\n"), 0644)
+
+		cmd := exec.Command(getGuardianBinary(t), "check", "--no-color", "--files-from", "-")
+		cmd.Dir = dir
+		cmd.Stdin = strings.NewReader("bad.py\n")
+		output, _ := cmd.CombinedOutput()
+		if !strings.Contains(string(output), "bad.py:1:") {
+			t.Errorf("expected bad.py from stdin to be checked:\n%s", output)
+		}
+	})
+}
+
+func TestCLI_Check_FilesFromOnlyMissing(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		os.WriteFile(filepath.Join(dir, "bad.py"), []byte("x = eval(data)\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "changed.txt"), []byte("gone.py\n"), 0644)
+
+		output, err := runGuardianInDir(t, dir, "check", "--files-from", "changed.txt")
+		if err != nil || !strings.Contains(output, "no files to check") {
+			t.Errorf("a list of only missing files should check nothing and pass, got %v:\n%s", err, output)
+		}
+	})
+}
+
+// ============================================================================
+// RULE GROUPS
+// ============================================================================
+
+func TestCLI_Check_GroupSecurity(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		code := "# TODO: tidy up\nprint(\"debug\")\nresult = eval(data)\napi_key = \"sk-live-4f9a8b7c6d5e4f3a2b1c\"\n"
+		os.WriteFile(filepath.Join(dir, "app.py"), []byte(code), 0644)
+
+		output, _ := runGuardianInDir(t, dir, "check", "--no-color", "--group", "security")
+		for _, rule := range []string{"ban-eval", "secret-pattern"} {
+			if !strings.Contains(output, rule) {
+				t.Errorf("--group security should report %s:\n%s", rule, output)
+			}
+		}
+		for _, rule := range []string{"ban-print", "todo-marker"} {
+			if strings.Contains(output, rule) {
+				t.Errorf("--group security should not report %s:\n%s", rule, output)
+			}
+		}
+
+		output, err := runGuardianInDir(t, dir, "check", "--no-color", "--group", "quality")
+		if err != nil {
+			t.Errorf("quality issues alone aren't critical, expected exit 0: %v\n%s", err, output)
+		}
+		if strings.Contains(output, "ban-eval") || !strings.Contains(output, "ban-print") {
+			t.Errorf("--group quality should report only quality rules:\n%s", output)
+		}
+	})
+}
+
+func TestCLI_Check_GroupInvalid(t *testing.T) {
+	withTestProject(t, func(dir string) {
+		if _, err := runGuardianInDir(t, dir, "check", "--group", "style"); err == nil {
+			t.Error("expected non-zero exit for invalid --group")
 		}
 	})
 }